@@ -1,23 +1,28 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"os"
+	"os/signal"
 
 	tea "github.com/charmbracelet/bubbletea"
 
 	"github.com/camikura/dito/internal/app"
+	"github.com/camikura/dito/internal/cli"
+	"github.com/camikura/dito/internal/config"
 	"github.com/camikura/dito/internal/version"
 )
 
 // model wraps app.Model to allow methods in main package
 type model struct {
 	app.Model
+	initCmd tea.Cmd // Startup command (auto-connect)
 }
 
 func (m model) Init() tea.Cmd {
-	return nil
+	return m.initCmd
 }
 
 func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
@@ -31,8 +36,23 @@ func (m model) View() string {
 }
 
 func main() {
+	// Non-interactive subcommands
+	if len(os.Args) > 1 && os.Args[1] == "query" {
+		os.Exit(cli.RunQuery(os.Args[2:], os.Stdin, os.Stdout, os.Stderr, os.Getenv))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "import" {
+		// Stop between batches on Ctrl+C so the summary and rejects file are complete
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		code := cli.RunImport(ctx, os.Args[2:], os.Stdin, os.Stdout, os.Stderr, os.Getenv)
+		stop()
+		os.Exit(code)
+	}
+
 	showVersion := flag.Bool("version", false, "Show version information")
 	flag.BoolVar(showVersion, "v", false, "Show version information (shorthand)")
+	var opts cli.Options
+	opts.RegisterFlags(flag.CommandLine, os.Getenv)
+	opts.RegisterStartupFlags(flag.CommandLine, os.Getenv)
 	flag.Parse()
 
 	if *showVersion {
@@ -46,8 +66,34 @@ func main() {
 		return
 	}
 
+	m := app.InitialModel()
+	// Saved connection profiles; without a config directory profiles are disabled
+	if path, err := config.ProfilesPath(); err == nil {
+		m.Profiles.Path = path
+		profiles, err := config.LoadProfiles(path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
+		m.Profiles.Items = profiles
+	}
+
+	// Auto-connect when connection flags or DITO_* variables are given
+	startup := app.StartupOptions{Profile: opts.Profile, Table: opts.Table, SQL: opts.SQL, Refresh: opts.Refresh}
+	if opts.HasConnection() {
+		cfg, err := opts.ConnectionConfig(m.Profiles.Items)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(2)
+		}
+		startup.Connection = &cfg
+	} else if opts.Table != "" || opts.SQL != "" {
+		fmt.Fprintln(os.Stderr, "Error: --table and --sql require a connection (use --endpoint, --profile or --edition)")
+		os.Exit(2)
+	}
+	m, initCmd := app.ApplyStartup(m, startup)
+
 	p := tea.NewProgram(
-		model{Model: app.InitialModel()},
+		model{Model: m, initCmd: initCmd},
 		tea.WithAltScreen(),
 		tea.WithMouseCellMotion(),
 	)
@@ -1,9 +1,29 @@
 package app
 
 import (
+	"github.com/camikura/dito/internal/ddl"
 	"github.com/camikura/dito/internal/ui"
 )
 
+// tableSchema returns the loaded schema of tableName, or nil when its details
+// have not been fetched yet
+func tableSchema(m Model, tableName string) *ddl.Table {
+	details, exists := m.Schema.TableDetails[tableName]
+	if !exists || details == nil || details.Schema == nil {
+		return nil
+	}
+	return details.Table()
+}
+
+// tablePrimaryKeys returns the primary key columns declared by tableName, or nil
+// when its schema is not loaded
+func tablePrimaryKeys(m Model, tableName string) []string {
+	if table := tableSchema(m, tableName); table != nil {
+		return table.PrimaryKey
+	}
+	return nil
+}
+
 // getColumnsInSchemaOrder returns column names in schema definition order.
 // For custom SQL with explicit column list, it uses the parsed column order.
 // For SELECT * or normal queries, it uses schema definition order.
@@ -14,22 +34,28 @@ func getColumnsInSchemaOrder(m Model, tableName string, rows []map[string]interf
 		return m.SQL.ColumnOrder
 	}
 
-	// Use schema order for SELECT * and normal queries
-	var ddl string
-	if details, exists := m.Schema.TableDetails[tableName]; exists && details != nil && details.Schema != nil {
-		ddl = details.Schema.DDL
+	// Use schema order for SELECT * and normal queries, with the ancestor
+	// schemas of child tables (root to parent order)
+	var ancestors []*ddl.Table
+	for _, ancestor := range ui.GetAncestorTableNames(tableName) {
+		ancestors = append(ancestors, tableSchema(m, ancestor))
 	}
 
-	// Get ancestor DDLs for child tables (root to parent order)
-	var ancestorDDLs []string
-	ancestors := ui.GetAncestorTableNames(tableName)
-	for _, ancestor := range ancestors {
-		if details, exists := m.Schema.TableDetails[ancestor]; exists && details != nil && details.Schema != nil {
-			ancestorDDLs = append(ancestorDDLs, details.Schema.DDL)
-		} else {
-			ancestorDDLs = append(ancestorDDLs, "")
+	return ui.GetColumnsInSchemaOrderFromTables(tableSchema(m, tableName), ancestors, rows)
+}
+
+// inheritedKeyColumns returns the primary key columns a table inherits from its
+// ancestors (root to immediate parent) whose schemas are loaded
+func inheritedKeyColumns(m Model, tableName string) []ui.ColumnInfo {
+	var columns []ui.ColumnInfo
+	for _, ancestorName := range ui.GetAncestorTableNames(tableName) {
+		// Only add primary key columns from ancestors
+		for _, col := range ui.ColumnsFromTable(tableSchema(m, ancestorName)) {
+			if col.IsPrimaryKey {
+				col.IsInherited = true
+				columns = append(columns, col)
+			}
 		}
 	}
-
-	return ui.GetColumnsInSchemaOrderWithAncestors(ddl, ancestorDDLs, rows)
+	return columns
 }
@@ -0,0 +1,226 @@
+package app
+
+import (
+	"github.com/camikura/dito/internal/db"
+)
+
+// ConnectionField identifies an input field in the connection setup dialog
+type ConnectionField int
+
+const (
+	ConnFieldEndpoint ConnectionField = iota
+	ConnFieldPort
+	ConnFieldHTTPS
+	ConnFieldCertPath
+	ConnFieldSkipVerify
+	ConnFieldUsername
+	ConnFieldPassword
+	ConnFieldOCIConfigFile
+	ConnFieldOCIProfile
+	ConnFieldRegion
+	ConnFieldCompartment
+	ConnFieldTenantID
+	ConnFieldNamespace
+	ConnFieldProfileName
+	ConnFieldPasswordEnv
+	ConnFieldPasswordFile
+	ConnFieldEdition
+	ConnFieldReadOnly
+)
+
+// connectionDialogFields returns the dialog fields for an edition in display (and Tab) order.
+// The certificate fields are only listed with HTTPS enabled.
+// ConnectionDialogState.Field is an index into this list.
+func connectionDialogFields(edition db.Edition, useHTTPS bool) []ConnectionField {
+	switch edition {
+	case db.EditionCloud:
+		return []ConnectionField{ConnFieldOCIConfigFile, ConnFieldOCIProfile, ConnFieldRegion, ConnFieldCompartment, ConnFieldReadOnly, ConnFieldEdition}
+	case db.EditionCloudSim:
+		return []ConnectionField{ConnFieldEndpoint, ConnFieldPort, ConnFieldTenantID, ConnFieldReadOnly, ConnFieldEdition}
+	default:
+		fields := []ConnectionField{ConnFieldEndpoint, ConnFieldPort, ConnFieldHTTPS}
+		if useHTTPS {
+			fields = append(fields, ConnFieldCertPath, ConnFieldSkipVerify)
+		}
+		return append(fields,
+			ConnFieldUsername, ConnFieldPassword,
+			ConnFieldNamespace,
+			ConnFieldReadOnly,
+			ConnFieldEdition,
+		)
+	}
+}
+
+// Fields returns the fields shown in the dialog. When editing a saved profile,
+// a name field is added and the password is replaced by references to it.
+// The edition selector stays the last field.
+func (d ConnectionDialogState) Fields() []ConnectionField {
+	fields := connectionDialogFields(d.Edition, d.UseHTTPS)
+	if !d.ProfileMode {
+		return fields
+	}
+	result := []ConnectionField{ConnFieldProfileName}
+	for _, field := range fields {
+		if field == ConnFieldPassword {
+			result = append(result, ConnFieldPasswordEnv, ConnFieldPasswordFile)
+			continue
+		}
+		result = append(result, field)
+	}
+	return result
+}
+
+// connectionFieldLabel returns the label shown in front of a dialog field
+func connectionFieldLabel(field ConnectionField) string {
+	switch field {
+	case ConnFieldEndpoint:
+		return "Endpoint"
+	case ConnFieldPort:
+		return "Port"
+	case ConnFieldHTTPS:
+		return "Secure"
+	case ConnFieldCertPath:
+		return "CA Cert"
+	case ConnFieldSkipVerify:
+		return "Insecure"
+	case ConnFieldUsername:
+		return "Username"
+	case ConnFieldPassword:
+		return "Password"
+	case ConnFieldOCIConfigFile:
+		return "OCI Config"
+	case ConnFieldOCIProfile:
+		return "Profile"
+	case ConnFieldRegion:
+		return "Region"
+	case ConnFieldCompartment:
+		return "Compartment"
+	case ConnFieldTenantID:
+		return "Tenant ID"
+	case ConnFieldNamespace:
+		return "Namespace"
+	case ConnFieldProfileName:
+		return "Name"
+	case ConnFieldPasswordEnv:
+		return "Password Env"
+	case ConnFieldPasswordFile:
+		return "Password File"
+	case ConnFieldEdition:
+		return "Edition"
+	case ConnFieldReadOnly:
+		return "Safety"
+	}
+	return ""
+}
+
+// CurrentField returns the field under the dialog cursor
+func (d ConnectionDialogState) CurrentField() ConnectionField {
+	fields := d.Fields()
+	if d.Field < 0 || d.Field >= len(fields) {
+		return fields[0]
+	}
+	return fields[d.Field]
+}
+
+// fieldValue returns a pointer to the text being edited for a field,
+// or nil if the field is not a text field
+func (d *ConnectionDialogState) fieldValue(field ConnectionField) *string {
+	switch field {
+	case ConnFieldEndpoint:
+		return &d.EditEndpoint
+	case ConnFieldPort:
+		return &d.EditPort
+	case ConnFieldCertPath:
+		return &d.EditCertPath
+	case ConnFieldUsername:
+		return &d.EditUsername
+	case ConnFieldPassword:
+		return &d.EditPassword
+	case ConnFieldOCIConfigFile:
+		return &d.EditOCIConfigFile
+	case ConnFieldOCIProfile:
+		return &d.EditOCIProfile
+	case ConnFieldRegion:
+		return &d.EditRegion
+	case ConnFieldCompartment:
+		return &d.EditCompartment
+	case ConnFieldTenantID:
+		return &d.EditTenantID
+	case ConnFieldNamespace:
+		return &d.EditNamespace
+	case ConnFieldProfileName:
+		return &d.EditProfileName
+	case ConnFieldPasswordEnv:
+		return &d.EditPasswordEnv
+	case ConnFieldPasswordFile:
+		return &d.EditPasswordFile
+	}
+	return nil
+}
+
+// fieldToggle returns a pointer to the flag toggled by a checkbox field,
+// or nil if the field is not a checkbox
+func (d *ConnectionDialogState) fieldToggle(field ConnectionField) *bool {
+	switch field {
+	case ConnFieldHTTPS:
+		return &d.UseHTTPS
+	case ConnFieldSkipVerify:
+		return &d.SkipVerify
+	case ConnFieldReadOnly:
+		return &d.ReadOnly
+	}
+	return nil
+}
+
+// checkboxLabel returns the text shown next to a checkbox field
+func checkboxLabel(field ConnectionField) string {
+	switch field {
+	case ConnFieldHTTPS:
+		return "HTTPS/TLS"
+	case ConnFieldSkipVerify:
+		return "Skip certificate verification"
+	case ConnFieldReadOnly:
+		return "Read-only (no writes or schema changes)"
+	}
+	return ""
+}
+
+// nextEdition cycles through the supported editions
+func nextEdition(current db.Edition, delta int) db.Edition {
+	editions := db.Editions()
+	index := 0
+	for i, e := range editions {
+		if e == current {
+			index = i
+			break
+		}
+	}
+	index = (index + delta + len(editions)) % len(editions)
+	return editions[index]
+}
+
+// ConnectionConfig builds the database connection configuration from the dialog values
+func (d ConnectionDialogState) ConnectionConfig() db.ConnectionConfig {
+	cfg := db.ConnectionConfig{Edition: d.Edition, ReadOnly: d.ReadOnly}
+	switch d.Edition {
+	case db.EditionCloud:
+		cfg.OCIConfigFile = d.EditOCIConfigFile
+		cfg.OCIProfile = d.EditOCIProfile
+		cfg.Region = d.EditRegion
+		cfg.Compartment = d.EditCompartment
+	case db.EditionCloudSim:
+		cfg.Endpoint = d.EditEndpoint
+		cfg.Port = d.EditPort
+		cfg.TenantID = d.EditTenantID
+	default:
+		cfg.Endpoint = d.EditEndpoint
+		cfg.Port = d.EditPort
+		cfg.UseHTTPS = d.UseHTTPS
+		cfg.CertPath = d.EditCertPath
+		cfg.InsecureSkipVerify = d.SkipVerify
+		cfg.Username = d.EditUsername
+		cfg.Password = d.EditPassword
+		cfg.Namespace = d.EditNamespace
+	}
+	return cfg
+}
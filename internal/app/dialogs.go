@@ -1,10 +1,14 @@
 package app
 
 import (
+	"fmt"
+	"slices"
 	"strings"
 
 	"github.com/charmbracelet/lipgloss"
 
+	"github.com/camikura/dito/internal/importer"
+	"github.com/camikura/dito/internal/output"
 	"github.com/camikura/dito/internal/ui"
 )
 
@@ -37,6 +41,12 @@ func renderRecordDetailDialog(m Model) string {
 	dialogWidth := m.Window.Width * ui.DialogSizeRatio / ui.DialogSizeDivisor
 	dialogHeight := m.Window.Height * ui.DialogSizeRatio / ui.DialogSizeDivisor
 
+	// Table rows can be edited from the dialog
+	title := ""
+	if !m.SQL.CustomSQL && !m.Connection.ReadOnly {
+		title = " Record Details (Edit: e) "
+	}
+
 	// Create record detail component
 	rd := ui.NewRecordDetail(ui.RecordDetailConfig{
 		Row:          row,
@@ -44,6 +54,7 @@ func renderRecordDetailDialog(m Model) string {
 		Width:        dialogWidth,
 		Height:       dialogHeight,
 		ScrollOffset: m.RecordDetail.ScrollOffset,
+		Title:        title,
 		BorderColor:  ui.ColorPrimaryHex,
 	})
 
@@ -62,6 +73,12 @@ func renderConnectionDialog(m Model) string {
 
 	// Title
 	titleText := " Connection Setup "
+	if m.ConnectionDialog.ProfileMode {
+		titleText = " Edit Profile "
+		if m.ConnectionDialog.ProfileIndex < 0 {
+			titleText = " New Profile "
+		}
+	}
 	title := ui.StyleTitleBold.Render(titleText)
 	titleLen := len([]rune(titleText))
 
@@ -145,19 +162,54 @@ func renderConnectionDialog(m Model) string {
 		return line.String()
 	}
 
+	// Edition selector line: "Edition: < On-Premise >"
+	renderEditionLine := func(focused bool) string {
+		labelPart := connectionFieldLabel(ConnFieldEdition) + ": "
+		value := "< " + m.ConnectionDialog.Edition.Label() + " >"
+		padding := contentWidth - len(labelPart) - lipgloss.Width(value)
+		if padding < 0 {
+			padding = 0
+		}
+		valueDisplay := value
+		if focused {
+			valueDisplay = ui.StyleSelected.Render(value)
+		}
+		return borderStyle.Render("│") + " " + labelStyle.Render(labelPart) + valueDisplay + strings.Repeat(" ", padding) + " " + borderStyle.Render("│")
+	}
+
+	// Checkbox line: "Secure: [x] HTTPS/TLS"
+	renderCheckboxLine := func(label string, text string, checked bool, focused bool) string {
+		labelPart := label + ": "
+		checkbox := ui.Checkbox(text, checked, focused)
+		padding := contentWidth - len(labelPart) - lipgloss.Width(checkbox)
+		if padding < 0 {
+			padding = 0
+		}
+		return borderStyle.Render("│") + " " + labelStyle.Render(labelPart) + checkbox + strings.Repeat(" ", padding) + " " + borderStyle.Render("│")
+	}
+
 	// Empty line
 	dialog.WriteString(borderStyle.Render("│"))
 	dialog.WriteString(strings.Repeat(" ", dialogWidth-2))
 	dialog.WriteString(borderStyle.Render("│"))
 	dialog.WriteString("\n")
 
-	// Endpoint field
-	dialog.WriteString(renderFieldLine("Endpoint", m.ConnectionDialog.EditEndpoint, 0, m.ConnectionDialog.EditCursorPos))
-	dialog.WriteString("\n")
-
-	// Port field
-	dialog.WriteString(renderFieldLine("Port", m.ConnectionDialog.EditPort, 1, m.ConnectionDialog.EditCursorPos))
-	dialog.WriteString("\n")
+	// Fields for the selected edition
+	for i, field := range m.ConnectionDialog.Fields() {
+		focused := m.ConnectionDialog.Field == i
+		if field == ConnFieldEdition {
+			dialog.WriteString(renderEditionLine(focused))
+		} else if toggle := m.ConnectionDialog.fieldToggle(field); toggle != nil {
+			dialog.WriteString(renderCheckboxLine(connectionFieldLabel(field), checkboxLabel(field), *toggle, focused))
+		} else {
+			value := *m.ConnectionDialog.fieldValue(field)
+			if field == ConnFieldPassword {
+				value = ui.MaskText(value)
+			}
+			dialog.WriteString(renderFieldLine(connectionFieldLabel(field), value, i, m.ConnectionDialog.EditCursorPos))
+		}
+		dialog.WriteString("\n")
+	}
 
 	// Empty line
 	dialog.WriteString(borderStyle.Render("│"))
@@ -165,8 +217,30 @@ func renderConnectionDialog(m Model) string {
 	dialog.WriteString(borderStyle.Render("│"))
 	dialog.WriteString("\n")
 
+	// Validation message
+	if m.ConnectionDialog.Message != "" {
+		message := ui.TruncateString(m.ConnectionDialog.Message, contentWidth)
+		messagePadding := contentWidth - lipgloss.Width(message)
+		if messagePadding < 0 {
+			messagePadding = 0
+		}
+		dialog.WriteString(borderStyle.Render("│") + " " + ui.StyleError.Render(message))
+		dialog.WriteString(strings.Repeat(" ", messagePadding))
+		dialog.WriteString(" " + borderStyle.Render("│") + "\n")
+	}
+
 	// Help text
 	helpText := "Connect: <enter> | Close: esc"
+	if m.ConnectionDialog.ProfileMode {
+		helpText = "Save: <enter> | Close: esc"
+	}
+	if m.ConnectionDialog.CurrentField() == ConnFieldEdition {
+		helpText = "Switch: <space> | " + helpText
+	} else if m.ConnectionDialog.fieldToggle(m.ConnectionDialog.CurrentField()) != nil {
+		helpText = "Toggle: <space> | " + helpText
+	} else if !m.ConnectionDialog.ProfileMode {
+		helpText = "Connect: <enter> | Save profile: ctrl+s | Close: esc"
+	}
 	helpDisplayWidth := lipgloss.Width(helpText)
 	helpPadding := contentWidth - helpDisplayWidth
 	if helpPadding < 0 {
@@ -194,3 +268,688 @@ func renderConnectionDialog(m Model) string {
 		dialog.String(),
 	)
 }
+
+// dialogBox builds a bordered dialog line by line
+type dialogBox struct {
+	width int
+	b     strings.Builder
+}
+
+// newDialogBox starts a dialog of the given outer width with a title in the top border
+func newDialogBox(title string, width int) *dialogBox {
+	d := &dialogBox{width: width}
+	titleText := " " + title + " "
+	// Title line: ╭─ + title + ─...─ + ╮
+	dashesLen := width - 3 - lipgloss.Width(titleText)
+	if dashesLen < 0 {
+		dashesLen = 0
+	}
+	d.b.WriteString(ui.StyleBorderActive.Render("╭─"))
+	d.b.WriteString(ui.StyleTitleBold.Render(titleText))
+	d.b.WriteString(ui.StyleBorderActive.Render(strings.Repeat("─", dashesLen) + "╮"))
+	d.b.WriteString("\n")
+	return d
+}
+
+// contentWidth returns the width available between the borders
+func (d *dialogBox) contentWidth() int {
+	return d.width - 4
+}
+
+// line writes content padded to the content width between borders
+func (d *dialogBox) line(content string) {
+	padding := d.contentWidth() - lipgloss.Width(content)
+	if padding < 0 {
+		padding = 0
+	}
+	borderStyle := ui.StyleBorderActive
+	d.b.WriteString(borderStyle.Render("│") + " " + content + strings.Repeat(" ", padding) + " " + borderStyle.Render("│") + "\n")
+}
+
+// render closes the dialog and centers it in the window
+func (d *dialogBox) render(m Model) string {
+	d.b.WriteString(ui.StyleBorderActive.Render("╰" + strings.Repeat("─", d.width-2) + "╯"))
+	return lipgloss.Place(
+		m.Window.Width,
+		m.Window.Height,
+		lipgloss.Center,
+		lipgloss.Center,
+		d.b.String(),
+	)
+}
+
+// renderProfilePicker renders the saved connection profile picker
+func renderProfilePicker(m Model) string {
+	dialog := newDialogBox("Profiles", ui.ConnectionDialogWidth)
+	contentWidth := dialog.contentWidth()
+	picker := m.ProfilePicker
+
+	dialog.line("")
+
+	if len(m.Profiles.Items) == 0 {
+		dialog.line(ui.StyleDim.Render("(no saved profiles)"))
+	}
+
+	// Profile lines: "name  On-Premise: localhost:8080"
+	nameWidth := 16
+	for i, profile := range m.Profiles.Items {
+		if i == picker.Cursor && picker.Renaming {
+			dialog.line(ui.StyleTitleActive.Render("Name: ") + ui.TextField(picker.EditName, contentWidth-10, true, picker.EditCursorPos))
+			continue
+		}
+		marker := "  "
+		if profile.Name == m.Profiles.Active {
+			marker = "* "
+		}
+		name := ui.TruncateString(profile.Name, nameWidth)
+		line := marker + name + strings.Repeat(" ", nameWidth-lipgloss.Width(name)+1) + profile.Summary()
+		line = ui.TruncateString(line, contentWidth)
+		if i == picker.Cursor {
+			line = ui.StyleSelected.Render(line + strings.Repeat(" ", contentWidth-lipgloss.Width(line)))
+		}
+		dialog.line(line)
+	}
+
+	dialog.line("")
+
+	// Status line: delete confirmation or message
+	if picker.ConfirmDelete && picker.Cursor < len(m.Profiles.Items) {
+		dialog.line(ui.StyleError.Render(ui.TruncateString("Delete "+m.Profiles.Items[picker.Cursor].Name+"? (y/n)", contentWidth)))
+	} else if picker.Message != "" {
+		dialog.line(ui.StyleHelpText.Render(ui.TruncateString(picker.Message, contentWidth)))
+	}
+
+	// Help text
+	if picker.Renaming {
+		dialog.line(ui.StyleHelpText.Render("Rename: <enter> | Cancel: esc"))
+	} else {
+		dialog.line(ui.StyleHelpText.Render("Connect: <enter> | Close: esc"))
+		dialog.line(ui.StyleHelpText.Render("New: n | Edit: e | Rename: r | Delete: d"))
+	}
+
+	return dialog.render(m)
+}
+
+// exportFormatLabel returns the display name of an export format
+func exportFormatLabel(format output.Format) string {
+	switch format {
+	case output.FormatJSONL:
+		return "JSON Lines"
+	case output.FormatJSON:
+		return "JSON array"
+	}
+	return strings.ToUpper(string(format))
+}
+
+// renderExportDialog renders the export dialog and its progress
+func renderExportDialog(m Model) string {
+	d := m.ExportDialog
+	dialog := newDialogBox("Export "+m.SelectedTableName(), ui.ConnectionDialogWidth)
+	contentWidth := dialog.contentWidth()
+	labelStyle := ui.StyleTitleActive
+	editing := d.Job == nil
+
+	dialog.line("")
+
+	// File path
+	pathLabel := "File: "
+	dialog.line(labelStyle.Render(pathLabel) + ui.TextField(d.EditPath, contentWidth-len(pathLabel), editing && d.Field == 0, d.EditCursorPos))
+
+	// Format selector: "Format: < CSV >"
+	format := "< " + exportFormatLabel(d.Format) + " >"
+	if editing && d.Field == 1 {
+		format = ui.StyleSelected.Render(format)
+	}
+	dialog.line(labelStyle.Render("Format: ") + format)
+
+	dialog.line("")
+
+	// Progress or message
+	switch {
+	case d.Cancelling:
+		dialog.line(ui.StyleHelpText.Render(fmt.Sprintf("Cancelling... %d rows written", d.Rows)))
+	case d.Job != nil:
+		dialog.line(ui.StyleHelpText.Render(fmt.Sprintf("Exporting... %d rows written", d.Rows)))
+	case d.ConfirmOverwrite:
+		dialog.line(ui.StyleError.Render(ui.TruncateString("File exists. Overwrite it?", contentWidth)))
+	case d.Message != "":
+		dialog.line(ui.StyleError.Render(ui.TruncateString(d.Message, contentWidth)))
+	}
+
+	// Help text
+	switch {
+	case !editing:
+		dialog.line(ui.StyleHelpText.Render("Cancel: esc"))
+	case d.ConfirmOverwrite:
+		dialog.line(ui.StyleHelpText.Render("Overwrite: <enter> | Cancel: esc"))
+	case d.Field == 1:
+		dialog.line(ui.StyleHelpText.Render("Switch: <space> | Export: <enter> | Close: esc"))
+	default:
+		dialog.line(ui.StyleHelpText.Render("Export: <enter> | Next: tab | Close: esc"))
+	}
+
+	return dialog.render(m)
+}
+
+// importModeLabel returns the display name of an import mode
+func importModeLabel(mode importer.Mode) string {
+	if mode == importer.ModeInsert {
+		return "Insert only"
+	}
+	return "Upsert"
+}
+
+// renderImportDialog renders the import dialog and its progress
+func renderImportDialog(m Model) string {
+	d := m.ImportDialog
+	dialog := newDialogBox("Import into "+d.TableName, ui.ConnectionDialogWidth)
+	contentWidth := dialog.contentWidth()
+	labelStyle := ui.StyleTitleActive
+	editing := d.Job == nil
+
+	dialog.line("")
+
+	// Input file
+	pathLabel := "File:    "
+	dialog.line(labelStyle.Render(pathLabel) + ui.TextField(d.EditPath, contentWidth-len(pathLabel), editing && d.Field == 0, d.EditCursorPos))
+
+	// Mode selector: "Mode:    < Upsert >"
+	mode := "< " + importModeLabel(d.Mode) + " >"
+	if editing && d.Field == 1 {
+		mode = ui.StyleSelected.Render(mode)
+	}
+	dialog.line(labelStyle.Render("Mode:    ") + mode)
+
+	// Rejects file
+	rejectsLabel := "Rejects: "
+	dialog.line(labelStyle.Render(rejectsLabel) + ui.TextField(d.EditRejects, contentWidth-len(rejectsLabel), editing && d.Field == 2, d.RejectsCursorPos))
+
+	dialog.line("")
+
+	// Progress or message
+	progress := fmt.Sprintf("%d rows written, %d rejected", d.Stats.Written, d.Stats.Rejected)
+	switch {
+	case d.Cancelling:
+		dialog.line(ui.StyleHelpText.Render("Cancelling... " + progress))
+	case d.Job != nil:
+		dialog.line(ui.StyleHelpText.Render("Importing... " + progress))
+	case d.Message != "":
+		dialog.line(ui.StyleError.Render(ui.TruncateString(d.Message, contentWidth)))
+	}
+
+	// Help text
+	switch {
+	case !editing:
+		dialog.line(ui.StyleHelpText.Render("Cancel: esc"))
+	case d.Field == 1:
+		dialog.line(ui.StyleHelpText.Render("Switch: <space> | Import: <enter> | Close: esc"))
+	default:
+		dialog.line(ui.StyleHelpText.Render("Import: <enter> | Next: tab | Close: esc"))
+	}
+
+	return dialog.render(m)
+}
+
+// renderDeleteDialog renders the row deletion confirmation with the keys to delete
+func renderDeleteDialog(m Model) string {
+	d := m.DeleteDialog
+	dialog := newDialogBox("Delete from "+d.TableName, ui.ConnectionDialogWidth)
+	contentWidth := dialog.contentWidth()
+
+	dialog.line("")
+	if len(d.Rows) == 1 {
+		dialog.line("Delete the row with this primary key?")
+	} else {
+		dialog.line(fmt.Sprintf("Delete %d rows with these primary keys?", len(d.Rows)))
+	}
+	dialog.line("")
+
+	end := min(d.ScrollOffset+deleteDialogVisibleKeys, len(d.Labels))
+	for _, label := range d.Labels[d.ScrollOffset:end] {
+		dialog.line(ui.StyleTitleActive.Render(ui.TruncateString("  "+label, contentWidth)))
+	}
+	if hidden := len(d.Labels) - end; hidden > 0 {
+		dialog.line(ui.StyleDim.Render(fmt.Sprintf("  ... %d more", hidden)))
+	}
+
+	dialog.line("")
+
+	// Status and help text
+	if d.Deleting {
+		dialog.line(ui.StyleHelpText.Render("Deleting..."))
+		dialog.line("")
+		return dialog.render(m)
+	}
+	dialog.line(ui.StyleHelpText.Render("Deleted rows can be restored with u until dito exits"))
+	switch {
+	case len(d.Labels) > deleteDialogVisibleKeys:
+		dialog.line(ui.StyleHelpText.Render("Delete: y | Scroll: ↑/↓ | Cancel: esc"))
+	default:
+		dialog.line(ui.StyleHelpText.Render("Delete: y | Cancel: esc"))
+	}
+
+	return dialog.render(m)
+}
+
+// rowEditorFixedLines is the number of row editor lines besides the fields:
+// borders, blank lines above and below the fields, message and help
+const rowEditorFixedLines = 6
+
+// rowEditorMaxTypeWidth limits the column type shown next to each field (e.g. long RECORD types)
+const rowEditorMaxTypeWidth = 20
+
+// rowEditorVisibleFields returns how many fields fit in the row editor
+func rowEditorVisibleFields(m Model) int {
+	visible := m.Window.Height*ui.DialogSizeRatio/ui.DialogSizeDivisor - rowEditorFixedLines
+	if visible < 1 {
+		visible = 1
+	}
+	return visible
+}
+
+// renderRowEditor renders the row editor dialog
+func renderRowEditor(m Model) string {
+	e := m.RowEditor
+	dialogWidth := m.Window.Width * ui.DialogSizeRatio / ui.DialogSizeDivisor
+	if dialogWidth < ui.ConnectionDialogWidth {
+		dialogWidth = ui.ConnectionDialogWidth
+	}
+	title := "Edit " + e.TableName
+	if e.Insert {
+		title = "New row in " + e.TableName
+	}
+	dialog := newDialogBox(title, dialogWidth)
+	contentWidth := dialog.contentWidth()
+	editing := !e.Loading && !e.Saving
+	visible := rowEditorVisibleFields(m)
+
+	dialog.line("")
+
+	// Field labels: "name  TYPE  value"
+	nameWidth, typeWidth := 0, 0
+	for _, field := range e.Fields {
+		nameWidth = max(nameWidth, ui.RuneLen(field.Column.Name))
+		typeWidth = max(typeWidth, ui.RuneLen(field.Column.Type))
+	}
+	typeWidth = min(typeWidth, rowEditorMaxTypeWidth)
+	valueWidth := contentWidth - nameWidth - typeWidth - 4
+	if valueWidth < 1 {
+		valueWidth = 1
+	}
+
+	// Render each field as one or more lines: the focused JSON field shows its
+	// lines, others are compacted to one
+	fieldLines := make([][]string, len(e.Fields))
+	for i, field := range e.Fields {
+		focused := editing && i == e.Field
+
+		labelStyle := ui.StyleTitleActive
+		if field.validate(e.Schema) != nil {
+			labelStyle = ui.StyleError
+		}
+		name := field.Column.Name
+		if e.Insert && field.required() {
+			name += "*"
+		}
+		label := labelStyle.Render(fmt.Sprintf("%-*s", nameWidth, name)) + "  " +
+			ui.StyleDim.Render(fmt.Sprintf("%-*s", typeWidth, ui.TruncateString(field.Column.Type, typeWidth))) + "  "
+
+		var values []string
+		switch {
+		case !e.editableField(i):
+			values = []string{ui.StyleDim.Render(ui.TruncateString(field.Text, valueWidth))}
+		case focused && importer.TypeName(field.Column.Type) == "BOOLEAN":
+			text := field.Text
+			if field.Null {
+				text = "null"
+			}
+			values = []string{ui.StyleSelected.Render("< " + text + " >")}
+		case focused && field.Null:
+			values = []string{ui.TextField("", 1, true, 0) + ui.StyleDim.Render(nullPlaceholder(e, field))}
+		case focused:
+			values = multiLineTextField(field.Text, max(valueWidth-4, 1), e.CursorPos, visible) // -4 for the field brackets
+		case field.Null:
+			values = []string{ui.StyleDim.Render(nullPlaceholder(e, field))}
+		default:
+			values = []string{ui.TruncateString(strings.ReplaceAll(field.Text, "\n", " "), valueWidth)}
+		}
+
+		indent := strings.Repeat(" ", nameWidth+typeWidth+4)
+		for j, value := range values {
+			if j == 0 {
+				fieldLines[i] = append(fieldLines[i], label+value)
+			} else {
+				fieldLines[i] = append(fieldLines[i], indent+value)
+			}
+		}
+	}
+
+	// Keep the focused field visible
+	offset := 0
+	for offset < e.Field && countLines(fieldLines[offset:e.Field+1]) > visible {
+		offset++
+	}
+
+	switch {
+	case e.Loading:
+		dialog.line(ui.StyleHelpText.Render("Loading..."))
+	case len(e.Fields) == 0:
+		dialog.line("")
+	}
+	shown := 0
+	for _, lines := range fieldLines[offset:] {
+		for _, line := range lines {
+			if shown == visible {
+				break
+			}
+			dialog.line(line)
+			shown++
+		}
+	}
+
+	dialog.line("")
+
+	// Status or message
+	switch {
+	case e.Saving:
+		dialog.line(ui.StyleHelpText.Render("Saving..."))
+	case e.Message != "":
+		dialog.line(ui.StyleError.Render(ui.TruncateString(e.Message, contentWidth)))
+	default:
+		dialog.line("")
+	}
+
+	// Help text
+	switch {
+	case !editing:
+		dialog.line(ui.StyleHelpText.Render("Close: esc"))
+	case e.editableField(e.Field) && importer.IsJSONType(e.Fields[e.Field].Column.Type):
+		dialog.line(ui.StyleHelpText.Render("Save: ctrl+s | New line: <enter> | Null: ctrl+d | Next: tab | Close: esc"))
+	case e.Insert:
+		dialog.line(ui.StyleHelpText.Render("Insert: <enter> | Null: ctrl+d | Next: tab | Close: esc"))
+	case e.Conflict || len(e.Fields) == 0:
+		dialog.line(ui.StyleHelpText.Render("Reload: ctrl+r | Close: esc"))
+	case e.editableField(e.Field) && importer.TypeName(e.Fields[e.Field].Column.Type) == "BOOLEAN":
+		dialog.line(ui.StyleHelpText.Render("Switch: <space> | Null: ctrl+d | Save: <enter> | Next: tab | Close: esc"))
+	default:
+		dialog.line(ui.StyleHelpText.Render("Save: <enter> | Null: ctrl+d | Next: tab | Reload: ctrl+r | Close: esc"))
+	}
+
+	return dialog.render(m)
+}
+
+// nullPlaceholder returns the text shown for a null field
+func nullPlaceholder(e RowEditorState, field RowEditorField) string {
+	switch {
+	case e.Insert && field.Column.Identity:
+		return "(generated)"
+	case e.Insert && field.required():
+		return "(required)"
+	}
+	return "(null)"
+}
+
+// multiLineTextField renders text as one text field per line with the cursor
+// (a rune offset into text) on its line. At most maxLines lines around the cursor are returned.
+func multiLineTextField(text string, width, cursorPos, maxLines int) []string {
+	lines := strings.Split(text, "\n")
+	cursorLine, cursorCol := 0, cursorPos
+	for cursorLine < len(lines)-1 && cursorCol > ui.RuneLen(lines[cursorLine]) {
+		cursorCol -= ui.RuneLen(lines[cursorLine]) + 1
+		cursorLine++
+	}
+
+	start := 0
+	if cursorLine >= maxLines {
+		start = cursorLine - maxLines + 1
+	}
+	var fields []string
+	for i := start; i < len(lines) && i < start+maxLines; i++ {
+		if i == cursorLine {
+			fields = append(fields, ui.TextField(lines[i], width, true, cursorCol))
+		} else {
+			fields = append(fields, ui.TextField(lines[i], width, false, 0))
+		}
+	}
+	return fields
+}
+
+// countLines returns the total number of lines of the rendered fields
+func countLines(fieldLines [][]string) int {
+	total := 0
+	for _, lines := range fieldLines {
+		total += len(lines)
+	}
+	return total
+}
+
+// tableDesignerFixedLines is the number of table designer lines besides the
+// column list and the DDL preview, including the borders
+const tableDesignerFixedLines = 12
+
+// designerPicker renders a value chosen with space or left/right, e.g. "< STRING >"
+func designerPicker(value string, width int, focused bool) string {
+	text := fmt.Sprintf("< %-*s >", width, value)
+	if focused {
+		return ui.StyleSelected.Render(text)
+	}
+	return text
+}
+
+// renderTableDesigner renders the table designer with the generated DDL
+func renderTableDesigner(m Model) string {
+	s := m.TableDesigner
+	d := s.Design
+	dialogWidth := max(m.Window.Width*ui.DialogSizeRatio/ui.DialogSizeDivisor, ui.ConnectionDialogWidth)
+	title := "New table"
+	if parent := ui.GetParentTableName(d.Name); parent != "" {
+		title = "New child table of " + parent
+	}
+	dialog := newDialogBox(title, dialogWidth)
+	contentWidth := dialog.contentWidth()
+	labelStyle := ui.StyleTitleActive
+	focused := func(row int, cell designerCell) bool {
+		return s.Row == row && s.focusedCell() == cell
+	}
+
+	dialog.line("")
+	nameLabel := "Name: "
+	dialog.line(labelStyle.Render(nameLabel) + ui.TextField(d.Name, contentWidth-len(nameLabel)-4, focused(designerRowName, cellTableName), s.CursorPos))
+	dialog.line(labelStyle.Render("TTL:  ") + ui.TextField(d.TTL, 6, focused(designerRowTTL, cellTTL), s.CursorPos) + " " +
+		designerPicker(d.TTLUnit, 5, focused(designerRowTTL, cellTTLUnit)))
+	dialog.line("")
+	dialog.line(ui.StyleSchemaLabel.Render("Columns:"))
+
+	// Inherited keys first, as in the Schema pane
+	const nameWidth = 12
+	var columnLines []string
+	for _, col := range inheritedKeyColumns(m, d.Name) {
+		columnLines = append(columnLines, ui.StyleDim.Render(fmt.Sprintf("  %-*s  %s (↑)", nameWidth, col.Name, col.Type)))
+	}
+	if d.IsChild() && len(columnLines) == 0 {
+		columnLines = append(columnLines, ui.StyleDim.Render("  (keys of "+ui.GetParentTableName(d.Name)+" not loaded)"))
+	}
+	inherited := len(columnLines)
+
+	for i, col := range d.Columns {
+		row := designerRowColumns + i
+		line := ui.TextField(col.Name, nameWidth, focused(row, cellColumnName), s.CursorPos) + " " +
+			designerPicker(col.Type, 9, focused(row, cellType)) + " " +
+			ui.Checkbox("PK", col.PrimaryKey, focused(row, cellPrimaryKey)) + " "
+		if slices.Contains(designerCells(d, row), cellShardKey) {
+			line += ui.Checkbox("Shard", col.ShardKey, focused(row, cellShardKey)) + " "
+		} else {
+			line += strings.Repeat(" ", len("[ ] Shard "))
+		}
+		switch col.Type {
+		case "TIMESTAMP":
+			line += "precision " + designerPicker(fmt.Sprint(col.Precision), 1, focused(row, cellPrecision))
+		case "ARRAY", "MAP":
+			line += "of " + designerPicker(col.ElementType, 7, focused(row, cellElementType))
+		case "RECORD":
+			fieldsWidth := max(contentWidth-lipgloss.Width(line)-len("fields ")-4, 1)
+			line += "fields " + ui.TextField(col.Fields, fieldsWidth, focused(row, cellFields), s.CursorPos)
+		}
+		columnLines = append(columnLines, line)
+	}
+
+	// DDL preview or the reason it cannot be generated
+	var previewLines []string
+	ddl, err := d.DDL()
+	if err != nil {
+		previewLines = []string{ui.StyleError.Render(ui.TruncateString(err.Error(), contentWidth))}
+	} else {
+		for _, line := range strings.Split(ddl, "\n") {
+			previewLines = append(previewLines, ui.StyleSchemaType.Render(ui.TruncateString(line, contentWidth)))
+		}
+	}
+
+	// Share the height between the columns and the preview, keeping the focused column visible
+	available := max(m.Window.Height*ui.DialogSizeRatio/ui.DialogSizeDivisor-tableDesignerFixedLines, 2)
+	shownColumns := min(len(columnLines), max(available-len(previewLines), available/2))
+	shownPreview := max(available-shownColumns, 1)
+	offset := 0
+	if s.Row >= designerRowColumns {
+		offset = max(inherited+s.Row-designerRowColumns-shownColumns+1, 0)
+	}
+	for _, line := range columnLines[offset : offset+shownColumns] {
+		dialog.line(line)
+	}
+
+	dialog.line("")
+	dialog.line(ui.StyleSchemaLabel.Render("Preview:"))
+	if len(previewLines) > shownPreview {
+		previewLines = append(previewLines[:shownPreview-1], ui.StyleDim.Render("..."))
+	}
+	for _, line := range previewLines {
+		dialog.line(line)
+	}
+	dialog.line("")
+
+	// Help text
+	switch s.focusedCell() {
+	case cellTableName, cellTTL, cellColumnName, cellFields:
+		dialog.line(ui.StyleHelpText.Render(ui.TruncateString("Next: tab | Add column: <enter> | Delete column: ctrl+d", contentWidth)))
+	default:
+		dialog.line(ui.StyleHelpText.Render(ui.TruncateString("Switch: <space> | Next: tab | Add column: <enter>", contentWidth)))
+	}
+	dialog.line(ui.StyleHelpText.Render(ui.TruncateString("Create: ctrl+s | Edit as SQL: ctrl+e | Close: esc", contentWidth)))
+
+	return dialog.render(m)
+}
+
+// indexDialogFixedLines is the number of create index dialog lines besides the
+// field list and the DDL preview, including the borders
+const indexDialogFixedLines = 12
+
+// renderIndexDialog renders the create index dialog with the generated DDL
+func renderIndexDialog(m Model) string {
+	s := m.IndexDialog
+	d := s.Design
+	dialogWidth := max(m.Window.Width*ui.DialogSizeRatio/ui.DialogSizeDivisor, ui.ConnectionDialogWidth)
+	dialog := newDialogBox("New index on "+d.TableName, dialogWidth)
+	contentWidth := dialog.contentWidth()
+	labelStyle := ui.StyleTitleActive
+
+	dialog.line("")
+	nameLabel := "Name: "
+	dialog.line(labelStyle.Render(nameLabel) + ui.TextField(d.Name, contentWidth-len(nameLabel)-4, s.Row == 0, s.CursorPos))
+	dialog.line("")
+	dialog.line(ui.StyleSchemaLabel.Render("Fields:"))
+
+	const typeWidth = 9
+	const multiKeyLabel = " multi-key"
+	pathWidth := max(contentWidth-typeWidth-len(multiKeyLabel)-9, 8)
+	var fieldLines []string
+	for i, field := range d.Fields {
+		row := i + 1
+		typ := field.Type
+		if typ == "" {
+			typ = "(none)"
+		}
+		line := ui.TextField(field.Path, pathWidth, s.Row == row && s.Cell == indexCellPath, s.CursorPos) + " " +
+			designerPicker(typ, typeWidth, s.Row == row && s.Cell == indexCellType)
+		if ui.IsMultiKeyPath(field.Path) {
+			line += ui.StyleDim.Render(multiKeyLabel)
+		}
+		fieldLines = append(fieldLines, line)
+	}
+
+	// DDL preview or the reason it cannot be generated
+	var preview string
+	if ddl, err := d.DDL(); err != nil {
+		preview = ui.StyleError.Render(ui.TruncateString(err.Error(), contentWidth))
+	} else {
+		preview = ui.StyleSchemaType.Render(ui.TruncateString(ddl, contentWidth))
+	}
+
+	// Keep the focused field visible
+	shown := min(len(fieldLines), max(m.Window.Height*ui.DialogSizeRatio/ui.DialogSizeDivisor-indexDialogFixedLines, 1))
+	offset := max(s.Row-shown, 0)
+	for _, line := range fieldLines[offset : offset+shown] {
+		dialog.line(line)
+	}
+
+	dialog.line("")
+	dialog.line(ui.StyleSchemaLabel.Render("Preview:"))
+	dialog.line(preview)
+	dialog.line("")
+
+	// Help text
+	if s.Row > 0 && s.Cell == indexCellType {
+		dialog.line(ui.StyleHelpText.Render(ui.TruncateString("Switch type: <space> | Next: tab | Add field: <enter>", contentWidth)))
+	} else {
+		dialog.line(ui.StyleHelpText.Render(ui.TruncateString("Next: tab | Add field: <enter> | Delete field: ctrl+d", contentWidth)))
+	}
+	dialog.line(ui.StyleHelpText.Render(ui.TruncateString("Create: ctrl+s | Close: esc", contentWidth)))
+
+	return dialog.render(m)
+}
+
+// renderIndexDetail renders an index definition, or the confirmation to drop it
+func renderIndexDetail(m Model) string {
+	d := m.IndexDetail
+	title := "Index " + d.Index.IndexName
+	if d.ConfirmDrop {
+		title = "Drop index " + d.Index.IndexName
+	}
+	dialog := newDialogBox(title, ui.ConnectionDialogWidth)
+	contentWidth := dialog.contentWidth()
+	labelStyle := ui.StyleTitleActive
+
+	dialog.line("")
+	dialog.line(labelStyle.Render("Table: ") + d.TableName)
+	dialog.line("")
+	dialog.line(ui.StyleSchemaLabel.Render("Fields:"))
+
+	types := indexFieldTypes(m, d.TableName, d.Index)
+	nameWidth := 0
+	for _, field := range d.Index.FieldNames {
+		nameWidth = max(nameWidth, ui.RuneLen(field))
+	}
+	for i, field := range d.Index.FieldNames {
+		typ := types[i]
+		if typ == "" {
+			typ = "-"
+		}
+		line := fmt.Sprintf("  %s%s  %s", ui.StyleSchemaIndex.Render(field), strings.Repeat(" ", nameWidth-ui.RuneLen(field)), ui.StyleSchemaType.Render(typ))
+		if ui.IsMultiKeyPath(field) {
+			line += ui.StyleDim.Render(" multi-key")
+		}
+		dialog.line(line)
+	}
+	dialog.line("")
+
+	// Help text
+	switch {
+	case d.ConfirmDrop:
+		dialog.line(ui.TruncateString(fmt.Sprintf("Drop index %s on %s?", d.Index.IndexName, d.TableName), contentWidth))
+		dialog.line(ui.StyleHelpText.Render("Drop: y | Cancel: esc"))
+	case m.Connection.ReadOnly:
+		dialog.line(ui.StyleHelpText.Render("Close: esc"))
+	default:
+		dialog.line(ui.StyleHelpText.Render("Drop: d | Close: esc"))
+	}
+
+	return dialog.render(m)
+}
@@ -15,6 +15,44 @@ func handleKeyPress(m Model, msg tea.KeyMsg) (Model, tea.Cmd) {
 		return handleConnectionDialogKeys(m, msg)
 	}
 
+	// Profile picker takes precedence
+	if m.ProfilePicker.Visible {
+		return handleProfilePickerKeys(m, msg)
+	}
+
+	// Export dialog takes precedence
+	if m.ExportDialog.Visible {
+		return handleExportDialogKeys(m, msg)
+	}
+
+	// Import dialog takes precedence
+	if m.ImportDialog.Visible {
+		return handleImportDialogKeys(m, msg)
+	}
+
+	// Table designer takes precedence
+	if m.TableDesigner.Visible {
+		return handleTableDesignerKeys(m, msg)
+	}
+
+	// Index dialogs take precedence
+	if m.IndexDialog.Visible {
+		return handleIndexDialogKeys(m, msg)
+	}
+	if m.IndexDetail.Visible {
+		return handleIndexDetailKeys(m, msg)
+	}
+
+	// Delete confirmation takes precedence
+	if m.DeleteDialog.Visible {
+		return handleDeleteDialogKeys(m, msg)
+	}
+
+	// Row editor takes precedence (it may be opened from the record detail dialog)
+	if m.RowEditor.Visible {
+		return handleRowEditorKeys(m, msg)
+	}
+
 	// Record detail dialog takes precedence
 	if m.RecordDetail.Visible {
 		return handleRecordDetailKeys(m, msg)
@@ -75,17 +113,21 @@ func handleConnectionKeys(m Model, msg tea.KeyMsg) (Model, tea.Cmd) {
 	case "enter":
 		// Open connection setup dialog
 		m.ConnectionDialog.Visible = true
+		m.ConnectionDialog.ProfileMode = false
+		m.ConnectionDialog.Message = ""
 		m.ConnectionDialog.Field = 0
 		// Initialize with current values or defaults
-		if m.ConnectionDialog.EditEndpoint == "" {
-			m.ConnectionDialog.EditEndpoint = "localhost"
-		}
-		if m.ConnectionDialog.EditPort == "" {
-			m.ConnectionDialog.EditPort = "8080"
+		initConnectionDialogDefaults(&m.ConnectionDialog)
+		m.ConnectionDialog.EditCursorPos = 0
+		if v := m.ConnectionDialog.fieldValue(m.ConnectionDialog.CurrentField()); v != nil {
+			m.ConnectionDialog.EditCursorPos = ui.RuneLen(*v)
 		}
-		m.ConnectionDialog.EditCursorPos = ui.RuneLen(m.ConnectionDialog.EditEndpoint)
 		return m, nil
 
+	case "p":
+		// Open saved profile picker
+		return openProfilePicker(m), nil
+
 	case "ctrl+d":
 		// Disconnect
 		if m.Connection.Connected {
@@ -94,6 +136,11 @@ func handleConnectionKeys(m Model, msg tea.KeyMsg) (Model, tea.Cmd) {
 			m.Tables.Tables = []string{}
 			m.Tables.SelectedTable = -1
 			m.Tables.CursorTable = 0
+			m.Tables.ScrollOffset = 0
+			m.Tables.Namespace = ""
+			m.Tables.Namespaces = nil
+			m.Tables.NamespaceErr = nil
+			m.Tables.Refreshing = false
 			m.SQL.CurrentSQL = ""
 			m.SQL.CursorPos = 0
 			// Clear all cached data
@@ -107,50 +154,97 @@ func handleConnectionKeys(m Model, msg tea.KeyMsg) (Model, tea.Cmd) {
 }
 
 func handleConnectionDialogKeys(m Model, msg tea.KeyMsg) (Model, tea.Cmd) {
+	fields := m.ConnectionDialog.Fields()
+	currentField := m.ConnectionDialog.CurrentField()
+	value := m.ConnectionDialog.fieldValue(currentField)
+
 	// Helper to move to field and set cursor position
 	moveToField := func(field int) {
 		m.ConnectionDialog.Field = field
-		if field == 0 {
-			m.ConnectionDialog.EditCursorPos = ui.RuneLen(m.ConnectionDialog.EditEndpoint)
-		} else if field == 1 {
-			m.ConnectionDialog.EditCursorPos = ui.RuneLen(m.ConnectionDialog.EditPort)
+		if v := m.ConnectionDialog.fieldValue(m.ConnectionDialog.CurrentField()); v != nil {
+			m.ConnectionDialog.EditCursorPos = ui.RuneLen(*v)
+		} else {
+			m.ConnectionDialog.EditCursorPos = 0
+		}
+	}
+
+	// Edition selector: cycle with space or left/right
+	if currentField == ConnFieldEdition {
+		delta := 0
+		switch msg.Type {
+		case tea.KeySpace, tea.KeyRight:
+			delta = 1
+		case tea.KeyLeft:
+			delta = -1
 		}
+		if delta != 0 {
+			m.ConnectionDialog.Edition = nextEdition(m.ConnectionDialog.Edition, delta)
+			initConnectionDialogDefaults(&m.ConnectionDialog)
+			// Keep focus on the edition selector, which is always the last field
+			moveToField(len(m.ConnectionDialog.Fields()) - 1)
+			return m, nil
+		}
+	}
+
+	// Checkboxes: toggle with space
+	if toggle := m.ConnectionDialog.fieldToggle(currentField); toggle != nil && msg.Type == tea.KeySpace {
+		*toggle = !*toggle
+		return m, nil
 	}
 
 	switch msg.Type {
 	case tea.KeyEsc:
-		// Close dialog
+		// Close dialog (profile editing returns to the picker)
 		m.ConnectionDialog.Visible = false
+		if m.ConnectionDialog.ProfileMode {
+			m.ConnectionDialog = ConnectionDialogState{}
+			m = openProfilePicker(m)
+		}
 		return m, nil
 
 	case tea.KeyEnter:
+		if m.ConnectionDialog.ProfileMode {
+			return saveProfileFromDialog(m)
+		}
 		// Connect from any field
 		m.ConnectionDialog.Visible = false
-		m.Connection.Endpoint = m.ConnectionDialog.EditEndpoint + ":" + m.ConnectionDialog.EditPort
-		return m, db.Connect(m.ConnectionDialog.EditEndpoint, m.ConnectionDialog.EditPort, false)
+		cfg := m.ConnectionDialog.ConnectionConfig()
+		m.Connection.Endpoint = cfg.DisplayEndpoint()
+		m.Connection.Edition = cfg.EffectiveEdition()
+		m.Profiles.Active = ""
+		return m, db.Connect(cfg, false)
+
+	case tea.KeyCtrlS:
+		// Save the current settings as a new profile
+		if !m.ConnectionDialog.ProfileMode {
+			m.ConnectionDialog.ProfileMode = true
+			m.ConnectionDialog.ProfileIndex = -1
+			m.ConnectionDialog.Message = ""
+			moveToField(0)
+		}
+		return m, nil
 
 	case tea.KeyTab, tea.KeyDown:
-		moveToField((m.ConnectionDialog.Field + 1) % 2)
+		moveToField((m.ConnectionDialog.Field + 1) % len(fields))
 		return m, nil
 
 	case tea.KeyShiftTab, tea.KeyUp:
-		moveToField((m.ConnectionDialog.Field + 1) % 2)
+		moveToField((m.ConnectionDialog.Field - 1 + len(fields)) % len(fields))
 		return m, nil
+	}
+
+	// Remaining keys edit text fields only
+	if value == nil {
+		return m, nil
+	}
 
+	switch msg.Type {
 	case tea.KeyBackspace:
-		if m.ConnectionDialog.Field == 0 {
-			m.ConnectionDialog.EditEndpoint, m.ConnectionDialog.EditCursorPos = ui.Backspace(m.ConnectionDialog.EditEndpoint, m.ConnectionDialog.EditCursorPos)
-		} else {
-			m.ConnectionDialog.EditPort, m.ConnectionDialog.EditCursorPos = ui.Backspace(m.ConnectionDialog.EditPort, m.ConnectionDialog.EditCursorPos)
-		}
+		*value, m.ConnectionDialog.EditCursorPos = ui.Backspace(*value, m.ConnectionDialog.EditCursorPos)
 		return m, nil
 
 	case tea.KeyDelete:
-		if m.ConnectionDialog.Field == 0 {
-			m.ConnectionDialog.EditEndpoint = ui.DeleteAt(m.ConnectionDialog.EditEndpoint, m.ConnectionDialog.EditCursorPos)
-		} else {
-			m.ConnectionDialog.EditPort = ui.DeleteAt(m.ConnectionDialog.EditPort, m.ConnectionDialog.EditCursorPos)
-		}
+		*value = ui.DeleteAt(*value, m.ConnectionDialog.EditCursorPos)
 		return m, nil
 
 	case tea.KeyLeft:
@@ -160,11 +254,7 @@ func handleConnectionDialogKeys(m Model, msg tea.KeyMsg) (Model, tea.Cmd) {
 		return m, nil
 
 	case tea.KeyRight:
-		maxPos := ui.RuneLen(m.ConnectionDialog.EditEndpoint)
-		if m.ConnectionDialog.Field == 1 {
-			maxPos = ui.RuneLen(m.ConnectionDialog.EditPort)
-		}
-		if m.ConnectionDialog.EditCursorPos < maxPos {
+		if m.ConnectionDialog.EditCursorPos < ui.RuneLen(*value) {
 			m.ConnectionDialog.EditCursorPos++
 		}
 		return m, nil
@@ -174,133 +264,192 @@ func handleConnectionDialogKeys(m Model, msg tea.KeyMsg) (Model, tea.Cmd) {
 		return m, nil
 
 	case tea.KeyEnd:
-		if m.ConnectionDialog.Field == 0 {
-			m.ConnectionDialog.EditCursorPos = ui.RuneLen(m.ConnectionDialog.EditEndpoint)
-		} else {
-			m.ConnectionDialog.EditCursorPos = ui.RuneLen(m.ConnectionDialog.EditPort)
-		}
+		m.ConnectionDialog.EditCursorPos = ui.RuneLen(*value)
+		return m, nil
+
+	case tea.KeySpace:
+		*value, m.ConnectionDialog.EditCursorPos = ui.InsertWithCursor(*value, m.ConnectionDialog.EditCursorPos, " ")
 		return m, nil
 
 	case tea.KeyRunes:
-		char := string(msg.Runes)
-		if m.ConnectionDialog.Field == 0 {
-			m.ConnectionDialog.EditEndpoint, m.ConnectionDialog.EditCursorPos = ui.InsertWithCursor(m.ConnectionDialog.EditEndpoint, m.ConnectionDialog.EditCursorPos, char)
-		} else {
-			m.ConnectionDialog.EditPort, m.ConnectionDialog.EditCursorPos = ui.InsertWithCursor(m.ConnectionDialog.EditPort, m.ConnectionDialog.EditCursorPos, char)
-		}
+		*value, m.ConnectionDialog.EditCursorPos = ui.InsertWithCursor(*value, m.ConnectionDialog.EditCursorPos, string(msg.Runes))
 		return m, nil
 	}
 
 	return m, nil
 }
 
+// initConnectionDialogDefaults fills empty dialog fields with defaults for the selected edition
+func initConnectionDialogDefaults(d *ConnectionDialogState) {
+	switch d.Edition {
+	case db.EditionCloud:
+		if d.EditOCIConfigFile == "" {
+			d.EditOCIConfigFile = db.DefaultOCIConfigFile
+		}
+		if d.EditOCIProfile == "" {
+			d.EditOCIProfile = db.DefaultOCIProfile
+		}
+	case db.EditionCloudSim:
+		if d.EditEndpoint == "" {
+			d.EditEndpoint = "localhost"
+		}
+		if d.EditPort == "" {
+			d.EditPort = "8080"
+		}
+		if d.EditTenantID == "" {
+			d.EditTenantID = db.DefaultCloudSimTenantID
+		}
+	default:
+		if d.EditEndpoint == "" {
+			d.EditEndpoint = "localhost"
+		}
+		if d.EditPort == "" {
+			d.EditPort = "8080"
+		}
+	}
+}
+
 func handleTablesKeys(m Model, msg tea.KeyMsg) (Model, tea.Cmd) {
-	// Calculate tables pane height using the same logic as view.go
-	visibleLines := calculateTablesHeight(m)
+	if m.Tables.Filtering {
+		return handleTableFilterKeys(m, msg)
+	}
 
 	// Handle M-< and M-> (Alt+Shift+, and Alt+Shift+.)
 	// On Mac, these produce special characters: ¯ (175) and ˘ (728)
 	switch msg.String() {
 	case "alt+<", "¯":
-		// Jump to first table
-		m.Tables.CursorTable = 0
+		// Jump to the first line
+		if lines := tableLines(m); len(lines) > 0 {
+			m = setTableCursor(m, lines[0])
+		}
 		m.Tables.ScrollOffset = 0
 		return m, nil
 
 	case "alt+>", "˘":
-		// Jump to last table
-		if len(m.Tables.Tables) > 0 {
-			m.Tables.CursorTable = len(m.Tables.Tables) - 1
-			// Adjust scroll offset to show cursor
-			if m.Tables.CursorTable >= visibleLines {
-				m.Tables.ScrollOffset = m.Tables.CursorTable - visibleLines + 1
-			}
+		// Jump to the last line
+		if lines := tableLines(m); len(lines) > 0 {
+			m = setTableCursor(m, lines[len(lines)-1])
+			m = scrollTablesToCursor(m)
 		}
 		return m, nil
-	}
 
-	switch msg.Type {
-	case tea.KeyUp, tea.KeyCtrlP:
-		if m.Tables.CursorTable > 0 {
-			m.Tables.CursorTable--
+	case "N":
+		// Filter the list to the next namespace
+		return cycleNamespace(m)
+
+	case "S":
+		// Show or hide the system tables
+		return toggleSystemTables(m)
+
+	case "r":
+		// Fetch the table list and the cached schemas again
+		return refreshTables(m)
+
+	case "/":
+		// Filter the list by fuzzy matching table names
+		return startTableFilter(m), nil
 
-			// Adjust scroll offset to keep cursor visible
-			if m.Tables.CursorTable < m.Tables.ScrollOffset {
-				m.Tables.ScrollOffset = m.Tables.CursorTable
+	case "i":
+		// Import rows from a file into the table under cursor
+		if m.Connection.ReadOnly {
+			return showFooterMessage(m, readOnlyRefusal)
+		}
+		return openImportDialog(m), nil
+
+	case "n", "c":
+		// Design a new table, or a child table of the table under cursor
+		if m.Connection.ReadOnly {
+			return showFooterMessage(m, readOnlyRefusal)
+		}
+		if msg.String() == "c" {
+			if parent := m.CursorTableName(); parent != "" {
+				return openTableDesigner(m, parent)
 			}
+			return m, nil
 		}
+		return openTableDesigner(m, "")
+	}
+
+	// Expand and collapse group headers and parent tables
+	if m, ok := handleTableTreeKeys(m, msg); ok {
 		return m, nil
+	}
+
+	switch msg.Type {
+	case tea.KeyUp, tea.KeyCtrlP:
+		return moveTableCursor(m, -1), nil
 
 	case tea.KeyDown, tea.KeyCtrlN:
-		if m.Tables.CursorTable < len(m.Tables.Tables)-1 {
-			m.Tables.CursorTable++
+		return moveTableCursor(m, 1), nil
 
-			// Adjust scroll offset to keep cursor visible
-			if m.Tables.CursorTable >= m.Tables.ScrollOffset+visibleLines {
-				m.Tables.ScrollOffset = m.Tables.CursorTable - visibleLines + 1
-			}
+	case tea.KeyEsc:
+		if m.Tables.Filter != "" {
+			return clearTableFilter(m), nil
 		}
 		return m, nil
 
 	case tea.KeyEnter:
 		// Select table and load data (only on Enter)
-		if m.Tables.CursorTable < len(m.Tables.Tables) {
-			m.Tables.SelectedTable = m.Tables.CursorTable
-			tableName := m.Tables.Tables[m.Tables.SelectedTable]
-
-			// Reset state
-			m.SQL.CustomSQL = false
-			m.Data.SelectedDataRow = 0
-			m.Data.ViewportOffset = 0
-			m.Data.HorizontalOffset = 0
-			m.Schema.ScrollOffset = 0
-
-			// Move focus to Data pane for immediate interaction
-			m.CurrentPane = FocusPaneData
+		if m.HasValidCursorTable() {
+			return selectCursorTable(m)
+		}
+		return m, nil
+	}
 
-			// Fetch ancestor table schemas if not already loaded (for inherited columns display)
-			var ancestorCmds []tea.Cmd
-			ancestors := ui.GetAncestorTableNames(tableName)
-			for _, ancestor := range ancestors {
-				if _, exists := m.Schema.TableDetails[ancestor]; !exists {
-					ancestorCmds = append(ancestorCmds, db.FetchTableDetails(m.Connection.NosqlClient, ancestor))
-				}
-			}
+	return m, nil
+}
 
-			// Check if schema is already loaded
-			if details, exists := m.Schema.TableDetails[tableName]; exists && details != nil && details.Schema != nil {
-				// Schema available - fetch data with ORDER BY
-				ddl := details.Schema.DDL
-				primaryKeys := ui.ParsePrimaryKeysFromDDL(ddl)
-				m.SQL.CurrentSQL = buildDefaultSQL(tableName, ddl)
-				m.SQL.CursorPos = ui.RuneLen(m.SQL.CurrentSQL)
-				dataCmd := db.FetchTableData(m.Connection.NosqlClient, tableName, ui.DefaultFetchSize, primaryKeys)
-				if len(ancestorCmds) > 0 {
-					ancestorCmds = append(ancestorCmds, dataCmd)
-					return m, tea.Batch(ancestorCmds...)
-				}
-				return m, dataCmd
-			}
+// selectCursorTable selects the table under cursor and loads its data
+func selectCursorTable(m Model) (Model, tea.Cmd) {
+	m.Tables.SelectedTable = m.Tables.CursorTable
+	tableName := m.Tables.Tables[m.Tables.SelectedTable]
+
+	// Reset state
+	m.SQL.CustomSQL = false
+	m.Data.Statement = nil
+	m.Data.SelectedDataRow = 0
+	m.Data.ViewportOffset = 0
+	m.Data.HorizontalOffset = 0
+	m.Schema.ScrollOffset = 0
+	m.Schema.Cursor = 0
+
+	// Move focus to Data pane for immediate interaction
+	m.CurrentPane = FocusPaneData
+
+	// Fetch ancestor table schemas if not already loaded (for inherited columns display)
+	var ancestorCmds []tea.Cmd
+	ancestors := ui.GetAncestorTableNames(tableName)
+	for _, ancestor := range ancestors {
+		if _, exists := m.Schema.TableDetails[ancestor]; !exists {
+			ancestorCmds = append(ancestorCmds, db.FetchTableDetails(m.Connection.NosqlClient, ancestor))
+		}
+	}
 
-			// Schema not loaded - fetch schema first, data will be fetched when schema arrives
-			m.SQL.CurrentSQL = "SELECT * FROM " + tableName
-			m.SQL.CursorPos = ui.RuneLen(m.SQL.CurrentSQL)
-			m.Data.LoadingData = true
-			ancestorCmds = append(ancestorCmds, db.FetchTableDetails(m.Connection.NosqlClient, tableName))
+	// Check if schema is already loaded
+	if details, exists := m.Schema.TableDetails[tableName]; exists && details != nil && details.Schema != nil {
+		// Schema available - fetch data with ORDER BY
+		primaryKeys := details.Table().PrimaryKey
+		m.SQL.CurrentSQL = buildDefaultSQL(tableName, m.Connection.Namespace, primaryKeys)
+		m.SQL.CursorPos = ui.RuneLen(m.SQL.CurrentSQL)
+		dataCmd := db.FetchTableData(m.Connection.NosqlClient, tableName, ui.DefaultFetchSize, primaryKeys)
+		if len(ancestorCmds) > 0 {
+			ancestorCmds = append(ancestorCmds, dataCmd)
 			return m, tea.Batch(ancestorCmds...)
 		}
-		return m, nil
+		return m, dataCmd
 	}
 
-	return m, nil
+	// Schema not loaded - fetch schema first, data will be fetched when schema arrives
+	m.SQL.CurrentSQL = "SELECT * FROM " + tableName
+	m.SQL.CursorPos = ui.RuneLen(m.SQL.CurrentSQL)
+	m.Data.LoadingData = true
+	ancestorCmds = append(ancestorCmds, db.FetchTableDetails(m.Connection.NosqlClient, tableName))
+	return m, tea.Batch(ancestorCmds...)
 }
 
 func handleSchemaKeys(m Model, msg tea.KeyMsg) (Model, tea.Cmd) {
 	// Determine which table schema is displayed (same logic as view)
-	schemaTableName := m.SelectedTableName()
-	if schemaTableName == "" {
-		schemaTableName = m.CursorTableName()
-	}
+	schemaTableName := schemaPaneTable(m)
 	if schemaTableName == "" {
 		return m, nil
 	}
@@ -315,37 +464,135 @@ func handleSchemaKeys(m Model, msg tea.KeyMsg) (Model, tea.Cmd) {
 		maxScroll = 0
 	}
 
+	// The cursor moves over the column tree nodes, then the indexes
+	nodes, indexes, cursor := schemaItems(m, schemaTableName)
+	itemCount := len(nodes) + len(indexes)
+
+	// moveCursor places the cursor on item pos and scrolls it into view,
+	// along with the "Columns:" or "Indexes:" label above the first item
+	moveCursor := func(pos int) (Model, tea.Cmd) {
+		m.Schema.Cursor = pos
+		line := schemaItemLine(len(nodes), pos)
+		if pos == 0 || pos == len(nodes) {
+			line--
+		}
+		if line < m.Schema.ScrollOffset {
+			m.Schema.ScrollOffset = line
+		}
+		if last := schemaItemLine(len(nodes), pos); last >= m.Schema.ScrollOffset+schemaHeight {
+			m.Schema.ScrollOffset = last - schemaHeight + 1
+		}
+		m.Schema.ScrollOffset = max(min(m.Schema.ScrollOffset, maxScroll), 0)
+		return m, nil
+	}
+
 	// Handle M-< and M-> (Alt+Shift+, and Alt+Shift+.)
 	// On Mac, these produce special characters: ¯ (175) and ˘ (728)
 	switch msg.String() {
 	case "alt+<", "¯":
 		// Scroll to top
+		m.Schema.Cursor = 0
 		m.Schema.ScrollOffset = 0
 		return m, nil
 
 	case "alt+>", "˘":
 		// Scroll to bottom
+		m.Schema.Cursor = max(itemCount-1, 0)
 		m.Schema.ScrollOffset = maxScroll
 		return m, nil
 	}
 
+	// Column tree actions
+	if cursor < len(nodes) {
+		node := nodes[cursor]
+		switch msg.String() {
+		case "enter":
+			if node.Expandable() {
+				return toggleSchemaNode(m, schemaTableName, node.Path, !node.Expanded), nil
+			}
+			return m, nil
+
+		case "right", "ctrl+f":
+			if node.Expandable() && !node.Expanded {
+				return toggleSchemaNode(m, schemaTableName, node.Path, true), nil
+			}
+			return m, nil
+
+		case "left", "ctrl+b":
+			// Collapse the node, or move to the node above it
+			if node.Expanded {
+				return toggleSchemaNode(m, schemaTableName, node.Path, false), nil
+			}
+			if parent := schemaParentNode(nodes, cursor); parent >= 0 {
+				return moveCursor(parent)
+			}
+			return m, nil
+
+		case "i":
+			return insertSchemaPath(m, schemaTableName, node)
+		}
+	}
+
+	// Table info actions
+	switch msg.String() {
+	case "t":
+		return toggleTableInfo(m, schemaTableName)
+	case "u":
+		return refreshTableUsage(m, schemaTableName)
+	case "a":
+		return toggleUsageRefresh(m, schemaTableName)
+	case "r":
+		return refreshSchema(m, schemaTableName)
+	}
+
+	// Index actions
+	switch msg.String() {
+	case "n":
+		if m.Connection.ReadOnly {
+			return showFooterMessage(m, readOnlyRefusal)
+		}
+		return openIndexDialog(m, schemaTableName)
+
+	case "d", "enter":
+		index, ok := schemaCursorIndex(m, schemaTableName)
+		if !ok {
+			return m, nil
+		}
+		confirmDrop := msg.String() == "d"
+		if confirmDrop && m.Connection.ReadOnly {
+			return showFooterMessage(m, readOnlyRefusal)
+		}
+		m.IndexDetail = IndexDetailState{Visible: true, TableName: schemaTableName, Index: index, ConfirmDrop: confirmDrop}
+		return m, nil
+	}
+
 	switch msg.Type {
 	case tea.KeyUp, tea.KeyCtrlP:
-		if m.Schema.ScrollOffset > 0 {
-			m.Schema.ScrollOffset--
+		if itemCount == 0 {
+			// Nothing to select: scroll
+			if m.Schema.ScrollOffset > 0 {
+				m.Schema.ScrollOffset--
+			}
+			return m, nil
 		}
-		return m, nil
+		return moveCursor(max(cursor-1, 0))
 
 	case tea.KeyDown, tea.KeyCtrlN:
-		if m.Schema.ScrollOffset < maxScroll {
-			m.Schema.ScrollOffset++
+		if itemCount == 0 {
+			if m.Schema.ScrollOffset < maxScroll {
+				m.Schema.ScrollOffset++
+			}
+			return m, nil
 		}
-		return m, nil
+		return moveCursor(min(cursor+1, itemCount-1))
 	}
 
 	return m, nil
 }
 
+// readOnlyRefusal is shown when an action that changes data is refused on a read-only connection
+const readOnlyRefusal = "Refused: read-only connection"
+
 func handleSQLKeys(m Model, msg tea.KeyMsg) (Model, tea.Cmd) {
 	switch msg.Type {
 	case tea.KeyCtrlR:
@@ -354,6 +601,21 @@ func handleSQLKeys(m Model, msg tea.KeyMsg) (Model, tea.Cmd) {
 			return m, nil
 		}
 
+		// Read-only connections only run queries; anything else is refused before it is prepared
+		if m.Connection.ReadOnly {
+			if err := db.CheckReadOnly(m.SQL.CurrentSQL); err != nil {
+				return showFooterMessage(m, "Refused: "+err.Error())
+			}
+		}
+
+		// DDL runs as an asynchronous table operation, system statements as a system request
+		switch kind, _ := db.ClassifyStatement(m.SQL.CurrentSQL); kind {
+		case db.StatementDDL:
+			return executeDDL(m, m.SQL.CurrentSQL)
+		case db.StatementSystem:
+			return executeSystemStatement(m, m.SQL.CurrentSQL)
+		}
+
 		// Parse table name from SQL
 		tableName := ui.ExtractTableNameFromSQL(m.SQL.CurrentSQL)
 		// Use case-insensitive table name matching
@@ -389,8 +651,13 @@ func handleSQLKeys(m Model, msg tea.KeyMsg) (Model, tea.Cmd) {
 				cmds = append(cmds, db.FetchTableDetails(m.Connection.NosqlClient, tableName))
 			}
 
-			// Execute custom SQL
+			// Execute custom SQL (DML statements report the affected rows instead)
 			cmds = append(cmds, db.ExecuteCustomSQL(m.Connection.NosqlClient, tableName, m.SQL.CurrentSQL, ui.DefaultFetchSize))
+			m.Data.Statement = nil
+			if kind, _ := db.ClassifyStatement(m.SQL.CurrentSQL); kind == db.StatementDML {
+				m.Data.Running = true
+				m.Data.ErrorMsg = ""
+			}
 
 			// Reset data row selection to top
 			m.Data.SelectedDataRow = 0
@@ -499,7 +766,7 @@ func handleMouseClick(m Model, msg tea.MouseMsg) (Model, tea.Cmd) {
 	}
 
 	// Ignore if dialogs are visible
-	if m.ConnectionDialog.Visible || m.RecordDetail.Visible {
+	if m.ConnectionDialog.Visible || m.ProfilePicker.Visible || m.ExportDialog.Visible || m.ImportDialog.Visible || m.TableDesigner.Visible || m.IndexDialog.Visible || m.IndexDetail.Visible || m.DeleteDialog.Visible || m.RowEditor.Visible || m.RecordDetail.Visible {
 		return m, nil
 	}
 
@@ -132,6 +132,10 @@ func handleDataKeys(m Model, msg tea.KeyMsg) (Model, tea.Cmd) {
 		return m, nil
 
 	case tea.KeyEscape:
+		// Clear marks first
+		if markedRowSet(m) != nil {
+			return clearRowMarks(m), nil
+		}
 		// Reset to default SQL (only if custom SQL is active)
 		if m.SQL.CustomSQL {
 			m.SQL.CustomSQL = false
@@ -141,18 +145,13 @@ func handleDataKeys(m Model, msg tea.KeyMsg) (Model, tea.Cmd) {
 			m.Data.HorizontalOffset = 0
 			m.Schema.ErrorMsg = ""
 			m.Data.ErrorMsg = ""
+			m.Data.Statement = nil
 
 			// Reload data with default SQL if a table is selected
 			tableName := m.SelectedTableName()
 			if tableName != "" {
-				var ddl string
-				var primaryKeys []string
-				if details := m.GetSelectedTableDetails(); details != nil && details.Schema != nil {
-					ddl = details.Schema.DDL
-					primaryKeys = ui.ParsePrimaryKeysFromDDL(ddl)
-				}
-
-				m.SQL.CurrentSQL = buildDefaultSQL(tableName, ddl)
+				primaryKeys := tablePrimaryKeys(m, tableName)
+				m.SQL.CurrentSQL = buildDefaultSQL(tableName, m.Connection.Namespace, primaryKeys)
 				m.SQL.CursorPos = ui.RuneLen(m.SQL.CurrentSQL)
 				return m, db.FetchTableData(m.Connection.NosqlClient, tableName, ui.DefaultFetchSize, primaryKeys)
 			}
@@ -161,6 +160,29 @@ func handleDataKeys(m Model, msg tea.KeyMsg) (Model, tea.Cmd) {
 		}
 		return m, nil
 
+	case tea.KeyCtrlS:
+		// Export the whole result set to a file
+		return openExportDialog(m), nil
+
+	case tea.KeyRunes:
+		switch msg.String() {
+		case "e":
+			// Edit the row, starting at the leftmost visible column
+			return openRowEditor(m, columnAtHorizontalOffset(m))
+		case "n":
+			return openNewRowForm(m)
+		case "d":
+			return openDeleteDialog(m)
+		case "u":
+			return undoDelete(m)
+		case "r":
+			return refreshTableData(m)
+		}
+		return m, nil
+
+	case tea.KeySpace:
+		return toggleRowMark(m)
+
 	case tea.KeyCtrlA:
 		// Scroll to leftmost
 		m.Data.HorizontalOffset = 0
@@ -222,6 +244,13 @@ func handleRecordDetailKeys(m Model, msg tea.KeyMsg) (Model, tea.Cmd) {
 		m.RecordDetail.ScrollOffset = 0
 		return m, nil
 
+	case tea.KeyRunes:
+		if msg.String() == "e" {
+			// Edit the whole row
+			return openRowEditor(m, "")
+		}
+		return m, nil
+
 	case tea.KeyUp, tea.KeyCtrlP:
 		if m.RecordDetail.ScrollOffset > 0 {
 			m.RecordDetail.ScrollOffset--
@@ -261,3 +290,15 @@ func handleRecordDetailKeys(m Model, msg tea.KeyMsg) (Model, tea.Cmd) {
 
 	return m, nil
 }
+
+// reloadTableData refetches the Data pane from the first row when it shows tableName,
+// e.g. after rows were written. Custom SQL results are left alone.
+func reloadTableData(m *Model, tableName string) tea.Cmd {
+	if m.SelectedTableName() != tableName || m.SQL.CustomSQL {
+		return nil
+	}
+	primaryKeys := tablePrimaryKeys(*m, tableName)
+	m.Data.SelectedDataRow = 0
+	m.Data.ViewportOffset = 0
+	return db.FetchTableData(m.Connection.NosqlClient, tableName, ui.DefaultFetchSize, primaryKeys)
+}
@@ -0,0 +1,117 @@
+package app
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/oracle/nosql-go-sdk/nosqldb/types"
+
+	"github.com/camikura/dito/internal/db"
+	"github.com/camikura/dito/internal/ui"
+)
+
+// ddlStateExpiredMsg removes the ACTIVE state of a completed operation from the Tables pane
+type ddlStateExpiredMsg struct {
+	TableName string
+}
+
+// executeDDL submits a DDL statement, e.g. from the SQL pane.
+// The state of the operation is shown in the Tables pane until it completes.
+func executeDDL(m Model, sql string) (Model, tea.Cmd) {
+	_, keyword := db.ClassifyStatement(sql)
+	tableName := db.DDLTableName(sql)
+	if actual := m.FindTableName(tableName); actual != "" {
+		tableName = actual
+	}
+
+	message := fmt.Sprintf("Running %s...", keyword)
+	if tableName != "" {
+		message = fmt.Sprintf("Running %s on %s...", keyword, tableName)
+	}
+	m, tick := showFooterMessage(m, message)
+	return m, tea.Batch(tick, db.ExecuteDDL(m.Connection.NosqlClient, tableName, sql))
+}
+
+// executeSystemStatement submits a system statement (GRANT, REVOKE, CREATE/DROP
+// NAMESPACE, USER or ROLE), which does not operate on a table
+func executeSystemStatement(m Model, sql string) (Model, tea.Cmd) {
+	_, keyword := db.ClassifyStatement(sql)
+	m, tick := showFooterMessage(m, fmt.Sprintf("Running %s...", keyword))
+	return m, tea.Batch(tick, db.ExecuteSystemStatement(m.Connection.NosqlClient, sql))
+}
+
+func handleSystemResult(m Model, msg db.SystemResult) (Model, tea.Cmd) {
+	if msg.Err != nil {
+		return showFooterMessage(m, fmt.Sprintf("%s failed: %v", msg.Keyword, msg.Err))
+	}
+	m, tick := showFooterMessage(m, msg.Keyword+" completed")
+	if m.Connection.NosqlClient == nil {
+		return m, tick
+	}
+	// Namespaces may have been created or dropped along with their tables
+	return m, tea.Batch(tick, fetchTables(m), db.FetchNamespaces(m.Connection.NosqlClient))
+}
+
+// setTableOperation records the state of a table's DDL operation
+func setTableOperation(m Model, tableName string, state string) Model {
+	if tableName == "" {
+		return m
+	}
+	if m.Tables.Operations == nil {
+		m.Tables.Operations = make(map[string]string)
+	}
+	m.Tables.Operations[tableName] = state
+	return m
+}
+
+func handleDDLResult(m Model, msg db.DDLResult) (Model, tea.Cmd) {
+	client := m.Connection.NosqlClient
+	if msg.Err != nil {
+		delete(m.Tables.Operations, msg.TableName)
+		return showFooterMessage(m, fmt.Sprintf("%s failed: %v", msg.Keyword, msg.Err))
+	}
+
+	if !msg.Done {
+		m = setTableOperation(m, msg.TableName, db.TableStateLabel(msg.State))
+		cmds := []tea.Cmd{db.WaitForDDL(client, msg)}
+		// A table being created is listed right away
+		if msg.TableName != "" && m.FindTableIndex(msg.TableName) < 0 {
+			cmds = append(cmds, fetchTables(m))
+		}
+		return m, tea.Batch(cmds...)
+	}
+
+	// Refresh the cached table list and schema
+	cmds := []tea.Cmd{fetchTables(m)}
+	message := msg.Keyword + " completed"
+	switch {
+	case msg.TableName == "":
+	case msg.State == types.Dropped:
+		delete(m.Tables.Operations, msg.TableName)
+		delete(m.Schema.TableDetails, msg.TableName)
+		delete(m.Data.TableData, msg.TableName)
+		message += ": " + msg.TableName + " dropped"
+	default:
+		state := db.TableStateLabel(msg.State)
+		m = setTableOperation(m, msg.TableName, state)
+		tableName := msg.TableName
+		cmds = append(cmds,
+			db.FetchTableDetails(client, tableName),
+			tea.Tick(ui.CopyMessageDuration, func(_ time.Time) tea.Msg {
+				return ddlStateExpiredMsg{TableName: tableName}
+			}),
+		)
+		message += ": " + tableName + " is " + state
+	}
+
+	m, tick := showFooterMessage(m, message)
+	return m, tea.Batch(append(cmds, tick)...)
+}
+
+func handleDDLStateExpired(m Model, msg ddlStateExpiredMsg) (Model, tea.Cmd) {
+	if m.Tables.Operations[msg.TableName] == db.TableStateLabel(types.Active) {
+		delete(m.Tables.Operations, msg.TableName)
+	}
+	return m, nil
+}
@@ -0,0 +1,284 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/oracle/nosql-go-sdk/nosqldb"
+	"github.com/oracle/nosql-go-sdk/nosqldb/types"
+
+	"github.com/camikura/dito/internal/db"
+	"github.com/camikura/dito/internal/importer"
+)
+
+// deleteDialogVisibleKeys is the number of primary keys listed in the delete dialog before scrolling
+const deleteDialogVisibleKeys = 10
+
+// rowsDeletedMsg is sent when the confirmed rows have been deleted
+type rowsDeletedMsg struct {
+	TableName string
+	Rows      []int             // Data pane indexes of the rows that are gone
+	Deleted   []*types.MapValue // Deleted rows, kept for undo
+	Err       error             // First error; the remaining rows were not deleted
+}
+
+// rowsRestoredMsg is sent when deleted rows have been written back
+type rowsRestoredMsg struct {
+	TableName string
+	Restored  int
+	Existing  int               // Rows skipped because their primary key is in use again
+	Failed    []*types.MapValue // Rows not written because of Err
+	Err       error
+}
+
+// markedRowSet returns the rows marked in the Data pane, or nil when the
+// marks belong to another table
+func markedRowSet(m Model) map[int]bool {
+	if m.SQL.CustomSQL || len(m.Data.Marked) == 0 || m.Data.MarkedTable != m.SelectedTableName() {
+		return nil
+	}
+	return m.Data.Marked
+}
+
+// toggleRowMark marks or unmarks the selected row for deletion and moves to the next row
+func toggleRowMark(m Model) (Model, tea.Cmd) {
+	tableName := m.SelectedTableName()
+	data := m.GetSelectedTableData()
+	if tableName == "" || data == nil || m.Data.SelectedDataRow < 0 || m.Data.SelectedDataRow >= len(data.Rows) {
+		return m, nil
+	}
+	if m.Connection.ReadOnly {
+		return showFooterMessage(m, readOnlyRefusal)
+	}
+	if m.SQL.CustomSQL {
+		return showFooterMessage(m, "Rows can only be deleted from table data (esc resets the query)")
+	}
+
+	if markedRowSet(m) == nil {
+		m.Data.MarkedTable = tableName
+		m.Data.Marked = make(map[int]bool)
+	}
+	if m.Data.Marked[m.Data.SelectedDataRow] {
+		delete(m.Data.Marked, m.Data.SelectedDataRow)
+	} else {
+		m.Data.Marked[m.Data.SelectedDataRow] = true
+	}
+	return handleDataKeys(m, tea.KeyMsg{Type: tea.KeyDown})
+}
+
+// clearRowMarks removes all marks
+func clearRowMarks(m Model) Model {
+	m.Data.MarkedTable = ""
+	m.Data.Marked = nil
+	return m
+}
+
+// openDeleteDialog asks for confirmation to delete the marked rows, or the selected row if none are marked
+func openDeleteDialog(m Model) (Model, tea.Cmd) {
+	tableName := m.SelectedTableName()
+	data := m.GetSelectedTableData()
+	if tableName == "" || data == nil || len(data.Rows) == 0 {
+		return m, nil
+	}
+	if m.Connection.ReadOnly {
+		return showFooterMessage(m, readOnlyRefusal)
+	}
+	if m.SQL.CustomSQL {
+		return showFooterMessage(m, "Rows can only be deleted from table data (esc resets the query)")
+	}
+
+	var rows []int
+	for i := range markedRowSet(m) {
+		if i < len(data.Rows) {
+			rows = append(rows, i)
+		}
+	}
+	sort.Ints(rows)
+	if len(rows) == 0 {
+		if m.Data.SelectedDataRow < 0 || m.Data.SelectedDataRow >= len(data.Rows) {
+			return m, nil
+		}
+		rows = []int{m.Data.SelectedDataRow}
+	}
+
+	schema, err := cachedTableSchema(m, tableName)
+	if err != nil {
+		return showFooterMessage(m, "Cannot delete: "+err.Error())
+	}
+	dialog := DeleteDialogState{Visible: true, TableName: tableName}
+	for _, i := range rows {
+		key, err := schema.PrimaryKey(data.Rows[i])
+		if err != nil {
+			return showFooterMessage(m, "Cannot delete: "+err.Error())
+		}
+		dialog.Rows = append(dialog.Rows, i)
+		dialog.Keys = append(dialog.Keys, key)
+		dialog.Labels = append(dialog.Labels, keyLabel(schema, data.Rows[i]))
+	}
+	m.DeleteDialog = dialog
+	return m, nil
+}
+
+// keyLabel returns the primary key of a Data pane row as "name=value, ..."
+func keyLabel(schema *importer.Schema, row map[string]interface{}) string {
+	var parts []string
+	for _, col := range schema.Columns {
+		if col.PrimaryKey {
+			parts = append(parts, col.Name+"="+keyText(row[col.Name]))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+func handleDeleteDialogKeys(m Model, msg tea.KeyMsg) (Model, tea.Cmd) {
+	d := &m.DeleteDialog
+	if d.Deleting {
+		return m, nil
+	}
+
+	switch msg.Type {
+	case tea.KeyEscape:
+		m.DeleteDialog = DeleteDialogState{}
+		return m, nil
+
+	case tea.KeyUp, tea.KeyCtrlP:
+		if d.ScrollOffset > 0 {
+			d.ScrollOffset--
+		}
+		return m, nil
+
+	case tea.KeyDown, tea.KeyCtrlN:
+		if d.ScrollOffset < len(d.Labels)-deleteDialogVisibleKeys {
+			d.ScrollOffset++
+		}
+		return m, nil
+
+	case tea.KeyRunes:
+		if msg.String() != "y" {
+			return m, nil
+		}
+		d.Deleting = true
+		return m, deleteRows(m.Connection.NosqlClient, d.TableName, d.Rows, d.Keys)
+	}
+	return m, nil
+}
+
+// deleteRows returns a command that deletes the rows with the given keys one at a time
+func deleteRows(client *nosqldb.Client, tableName string, rows []int, keys []*types.MapValue) tea.Cmd {
+	return func() tea.Msg {
+		msg := rowsDeletedMsg{TableName: tableName}
+		for i, key := range keys {
+			row, err := db.DeleteRow(client, tableName, key)
+			if err != nil {
+				msg.Err = err
+				break
+			}
+			// A row that no longer exists is removed from the Data pane as well
+			msg.Rows = append(msg.Rows, rows[i])
+			if row != nil {
+				msg.Deleted = append(msg.Deleted, row)
+			}
+		}
+		return msg
+	}
+}
+
+func handleRowsDeleted(m Model, msg rowsDeletedMsg) (Model, tea.Cmd) {
+	if len(msg.Deleted) > 0 {
+		m.Data.Undo = append(m.Data.Undo, DeletedRows{TableName: msg.TableName, Rows: msg.Deleted})
+	}
+	requested := len(m.DeleteDialog.Rows)
+	m.DeleteDialog = DeleteDialogState{}
+	if m.Data.MarkedTable == msg.TableName {
+		m = clearRowMarks(m)
+	}
+
+	// Remove the rows from the Data pane, keeping the cursor on the same row where possible
+	if data := m.Data.TableData[msg.TableName]; data != nil && !data.IsCustomSQL {
+		removed := make(map[int]bool, len(msg.Rows))
+		for _, i := range msg.Rows {
+			removed[i] = true
+		}
+		kept := make([]map[string]interface{}, 0, len(data.Rows))
+		selected := m.Data.SelectedDataRow
+		for i, row := range data.Rows {
+			if !removed[i] {
+				kept = append(kept, row)
+			} else if i < m.Data.SelectedDataRow {
+				selected--
+			}
+		}
+		data.Rows = kept
+		if m.SelectedTableName() == msg.TableName {
+			m.Data.SelectedDataRow = max(min(selected, len(kept)-1), 0)
+			m.Data.ViewportOffset = min(m.Data.ViewportOffset, m.Data.SelectedDataRow)
+		}
+	}
+
+	if msg.Err != nil {
+		return showFooterMessage(m, fmt.Sprintf("Deleted %d of %d rows: %v", len(msg.Rows), requested, msg.Err))
+	}
+	if len(msg.Deleted) == 0 {
+		return showFooterMessage(m, "The rows no longer exist")
+	}
+	return showFooterMessage(m, fmt.Sprintf("Deleted %d rows from %s (undo: u)", len(msg.Deleted), msg.TableName))
+}
+
+// undoDelete writes back the most recently deleted rows
+func undoDelete(m Model) (Model, tea.Cmd) {
+	if m.Data.Restoring {
+		return m, nil
+	}
+	if m.Connection.ReadOnly {
+		return showFooterMessage(m, readOnlyRefusal)
+	}
+	if len(m.Data.Undo) == 0 {
+		return showFooterMessage(m, "Nothing to undo")
+	}
+	batch := m.Data.Undo[len(m.Data.Undo)-1]
+	m.Data.Undo = m.Data.Undo[:len(m.Data.Undo)-1]
+	m.Data.Restoring = true
+	return m, restoreRows(m.Connection.NosqlClient, batch)
+}
+
+// restoreRows returns a command that writes deleted rows back unless their primary key is in use again
+func restoreRows(client *nosqldb.Client, batch DeletedRows) tea.Cmd {
+	return func() tea.Msg {
+		msg := rowsRestoredMsg{TableName: batch.TableName}
+		for i, row := range batch.Rows {
+			// Rows may have different shard keys, so each is written on its own
+			err := db.PutRows(client, batch.TableName, []*types.MapValue{row}, true)[0]
+			switch {
+			case errors.Is(err, db.ErrRowExists):
+				msg.Existing++
+			case err != nil:
+				msg.Err = err
+				msg.Failed = batch.Rows[i:]
+				return msg
+			default:
+				msg.Restored++
+			}
+		}
+		return msg
+	}
+}
+
+func handleRowsRestored(m Model, msg rowsRestoredMsg) (Model, tea.Cmd) {
+	m.Data.Restoring = false
+	var message string
+	if msg.Err != nil {
+		// Keep the rows not written so undo can be retried
+		m.Data.Undo = append(m.Data.Undo, DeletedRows{TableName: msg.TableName, Rows: msg.Failed})
+		message = fmt.Sprintf("Restore failed after %d rows: %v", msg.Restored, msg.Err)
+	} else {
+		message = fmt.Sprintf("Restored %d rows in %s", msg.Restored, msg.TableName)
+		if msg.Existing > 0 {
+			message += fmt.Sprintf(" (%d skipped: primary key in use again)", msg.Existing)
+		}
+	}
+	m, cmd := showFooterMessage(m, message)
+	return m, tea.Batch(cmd, reloadTableData(&m, msg.TableName))
+}
@@ -0,0 +1,277 @@
+package app
+
+import (
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/camikura/dito/internal/db"
+	"github.com/camikura/dito/internal/ui"
+)
+
+// Rows of the table designer; columns follow the fixed rows
+const (
+	designerRowName = iota
+	designerRowTTL
+	designerRowColumns
+)
+
+// designerCell identifies an input of the table designer
+type designerCell int
+
+const (
+	cellTableName designerCell = iota
+	cellTTL
+	cellTTLUnit
+	cellColumnName
+	cellType
+	cellPrimaryKey
+	cellShardKey
+	cellPrecision   // TIMESTAMP precision
+	cellElementType // ARRAY and MAP element type
+	cellFields      // RECORD fields
+)
+
+// openTableDesigner shows the table designer. With parent set, it designs
+// a child table of parent and loads the schemas of its ancestors if needed.
+func openTableDesigner(m Model, parent string) (Model, tea.Cmd) {
+	if m.Connection.NosqlClient == nil {
+		return m, nil
+	}
+
+	design := ui.TableDesign{TTLUnit: ui.DesignTTLUnits[0]}
+	column := ui.NewDesignColumn("id", "INTEGER")
+	column.PrimaryKey = true
+	var cmds []tea.Cmd
+	if parent != "" {
+		design.Name = parent + "."
+		column.Name = ""
+		// Inherited keys are shown from the ancestors' schemas
+		for _, ancestor := range ui.GetAncestorTableNames(design.Name) {
+			if _, exists := m.Schema.TableDetails[ancestor]; !exists {
+				cmds = append(cmds, db.FetchTableDetails(m.Connection.NosqlClient, ancestor))
+			}
+		}
+	}
+	design.Columns = []ui.DesignColumn{column}
+
+	m.TableDesigner = TableDesignerState{
+		Visible:   true,
+		Design:    design,
+		CursorPos: ui.RuneLen(design.Name),
+	}
+	return m, tea.Batch(cmds...)
+}
+
+// designerCells returns the inputs of a designer row in display order
+func designerCells(d ui.TableDesign, row int) []designerCell {
+	switch row {
+	case designerRowName:
+		return []designerCell{cellTableName}
+	case designerRowTTL:
+		return []designerCell{cellTTL, cellTTLUnit}
+	}
+
+	col := d.Columns[row-designerRowColumns]
+	cells := []designerCell{cellColumnName, cellType, cellPrimaryKey}
+	if col.PrimaryKey && !d.IsChild() {
+		cells = append(cells, cellShardKey)
+	}
+	switch col.Type {
+	case "TIMESTAMP":
+		cells = append(cells, cellPrecision)
+	case "ARRAY", "MAP":
+		cells = append(cells, cellElementType)
+	case "RECORD":
+		cells = append(cells, cellFields)
+	}
+	return cells
+}
+
+// focusedCell returns the input under focus
+func (s TableDesignerState) focusedCell() designerCell {
+	cells := designerCells(s.Design, s.Row)
+	return cells[min(s.Cell, len(cells)-1)]
+}
+
+// focusedColumn returns the column of the focused row, or nil on a fixed row
+func (s *TableDesignerState) focusedColumn() *ui.DesignColumn {
+	if s.Row < designerRowColumns {
+		return nil
+	}
+	return &s.Design.Columns[s.Row-designerRowColumns]
+}
+
+// cellText returns a pointer to the text of a text input, or nil for other inputs
+func (s *TableDesignerState) cellText(cell designerCell) *string {
+	switch cell {
+	case cellTableName:
+		return &s.Design.Name
+	case cellTTL:
+		return &s.Design.TTL
+	case cellColumnName:
+		return &s.focusedColumn().Name
+	case cellFields:
+		return &s.focusedColumn().Fields
+	}
+	return nil
+}
+
+// focus moves the focus to a row and cell, placing the cursor at the end of text inputs
+func (s *TableDesignerState) focus(row, cell int) {
+	s.Row, s.Cell = row, cell
+	s.Cell = min(s.Cell, len(designerCells(s.Design, row))-1)
+	if text := s.cellText(s.focusedCell()); text != nil {
+		s.CursorPos = ui.RuneLen(*text)
+	}
+}
+
+// addColumn inserts an empty column after the focused one and focuses it
+func (s *TableDesignerState) addColumn() {
+	at := len(s.Design.Columns)
+	if s.Row >= designerRowColumns {
+		at = s.Row - designerRowColumns + 1
+	}
+	s.Design.Columns = slices.Insert(s.Design.Columns, at, ui.NewDesignColumn("", "STRING"))
+	s.focus(designerRowColumns+at, 0)
+}
+
+// cycle returns the value next to current in values (backwards with step -1)
+func cycle(values []string, current string, step int) string {
+	i := slices.Index(values, current)
+	return values[(i+step+len(values))%len(values)]
+}
+
+func handleTableDesignerKeys(m Model, msg tea.KeyMsg) (Model, tea.Cmd) {
+	s := &m.TableDesigner
+	rows := designerRowColumns + len(s.Design.Columns)
+
+	switch msg.String() {
+	case "esc":
+		s.Visible = false
+		return m, nil
+
+	case "ctrl+s":
+		return createDesignedTable(m)
+
+	case "ctrl+e":
+		// Continue editing the DDL in the SQL pane
+		ddl, err := s.Design.DDL()
+		if err != nil {
+			return m, nil
+		}
+		s.Visible = false
+		m.SQL.CurrentSQL = ddl
+		m.SQL.CursorPos = ui.RuneLen(ddl)
+		m.CurrentPane = FocusPaneSQL
+		return m, nil
+
+	case "tab":
+		if s.Cell+1 < len(designerCells(s.Design, s.Row)) {
+			s.focus(s.Row, s.Cell+1)
+		} else {
+			s.focus((s.Row+1)%rows, 0)
+		}
+		return m, nil
+
+	case "shift+tab":
+		if s.Cell > 0 {
+			s.focus(s.Row, s.Cell-1)
+		} else {
+			row := (s.Row + rows - 1) % rows
+			s.focus(row, len(designerCells(s.Design, row))-1)
+		}
+		return m, nil
+
+	case "up":
+		if s.Row > 0 {
+			s.focus(s.Row-1, s.Cell)
+		}
+		return m, nil
+
+	case "down":
+		if s.Row+1 < rows {
+			s.focus(s.Row+1, s.Cell)
+		}
+		return m, nil
+
+	case "enter", "ctrl+n":
+		s.addColumn()
+		return m, nil
+
+	case "ctrl+d":
+		// Delete the focused column
+		if s.Row >= designerRowColumns && len(s.Design.Columns) > 1 {
+			s.Design.Columns = slices.Delete(s.Design.Columns, s.Row-designerRowColumns, s.Row-designerRowColumns+1)
+			s.focus(min(s.Row, rows-2), s.Cell)
+		}
+		return m, nil
+
+	case "alt+up", "alt+down":
+		// Move the focused column; the primary key follows the column order
+		i := s.Row - designerRowColumns
+		j := i - 1
+		if msg.String() == "alt+down" {
+			j = i + 1
+		}
+		if i >= 0 && j >= 0 && j < len(s.Design.Columns) {
+			s.Design.Columns[i], s.Design.Columns[j] = s.Design.Columns[j], s.Design.Columns[i]
+			s.Row = designerRowColumns + j
+		}
+		return m, nil
+	}
+
+	cell := s.focusedCell()
+	if text := s.cellText(cell); text != nil {
+		if cell == cellTTL && msg.Type == tea.KeyRunes && strings.Trim(string(msg.Runes), "0123456789") != "" {
+			return m, nil
+		}
+		*text, s.CursorPos = editTextInput(*text, s.CursorPos, msg)
+		return m, nil
+	}
+
+	step := 0
+	switch msg.Type {
+	case tea.KeySpace, tea.KeyRight:
+		step = 1
+	case tea.KeyLeft:
+		step = -1
+	}
+	if step == 0 {
+		return m, nil
+	}
+
+	col := s.focusedColumn()
+	switch cell {
+	case cellTTLUnit:
+		s.Design.TTLUnit = cycle(ui.DesignTTLUnits, s.Design.TTLUnit, step)
+	case cellType:
+		col.Type = cycle(ui.DesignTypes, col.Type, step)
+	case cellElementType:
+		col.ElementType = cycle(ui.DesignElementTypes, col.ElementType, step)
+	case cellPrecision:
+		col.Precision = (col.Precision + step + 10) % 10
+	case cellPrimaryKey:
+		col.PrimaryKey = !col.PrimaryKey
+		if !col.PrimaryKey {
+			col.ShardKey = false
+		}
+	case cellShardKey:
+		col.ShardKey = !col.ShardKey
+	}
+	return m, nil
+}
+
+// createDesignedTable runs the generated DDL; it is also placed in the SQL pane
+func createDesignedTable(m Model) (Model, tea.Cmd) {
+	ddl, err := m.TableDesigner.Design.DDL()
+	if err != nil {
+		return m, nil
+	}
+	m.TableDesigner.Visible = false
+	m.SQL.CurrentSQL = ddl
+	m.SQL.CursorPos = ui.RuneLen(ddl)
+	m.SQL.ScrollOffset = 0
+	return executeDDL(m, ddl)
+}
@@ -0,0 +1,533 @@
+package app
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/oracle/nosql-go-sdk/nosqldb"
+	"github.com/oracle/nosql-go-sdk/nosqldb/types"
+
+	"github.com/camikura/dito/internal/db"
+	"github.com/camikura/dito/internal/ddl"
+	"github.com/camikura/dito/internal/importer"
+	"github.com/camikura/dito/internal/ui"
+)
+
+// rowLoadedMsg is sent when the row to edit has been read
+type rowLoadedMsg struct {
+	TableName string
+	RowIndex  int
+	Schema    *importer.Schema
+	Row       *types.MapValue // nil when the row no longer exists
+	Version   types.Version
+	Err       error
+}
+
+// rowSavedMsg is sent when the edited row has been written
+type rowSavedMsg struct {
+	TableName string
+	RowIndex  int
+	Row       map[string]interface{} // Written row in Data pane form
+	Version   types.Version
+	Err       error
+}
+
+// rowInsertedMsg is sent when a new row has been written
+type rowInsertedMsg struct {
+	TableName string
+	Err       error
+}
+
+// openRowEditor opens the row editor for the selected row and reads its current
+// values and version. focusColumn is focused once loaded ("" for the first editable column).
+func openRowEditor(m Model, focusColumn string) (Model, tea.Cmd) {
+	tableName := m.SelectedTableName()
+	data := m.GetSelectedTableData()
+	if tableName == "" || data == nil || m.Data.SelectedDataRow < 0 || m.Data.SelectedDataRow >= len(data.Rows) {
+		return m, nil
+	}
+	if m.Connection.ReadOnly {
+		return showFooterMessage(m, readOnlyRefusal)
+	}
+	if m.SQL.CustomSQL {
+		// Custom SQL rows may be projections or come from another table
+		return showFooterMessage(m, "Editing is only available for table data (esc resets the query)")
+	}
+
+	m.RowEditor = RowEditorState{
+		Visible:     true,
+		TableName:   tableName,
+		RowIndex:    m.Data.SelectedDataRow,
+		FocusColumn: focusColumn,
+		Loading:     true,
+	}
+	return m, loadRowForEdit(m.Connection.NosqlClient, tableName, m.Data.SelectedDataRow, data.Rows[m.Data.SelectedDataRow])
+}
+
+// cachedTableSchema builds the schema of tableName from the cached schemas of
+// the table and its ancestors, whose primary key columns a child table inherits
+func cachedTableSchema(m Model, tableName string) (*importer.Schema, error) {
+	var ancestors []*ddl.Table
+	for _, name := range ui.GetAncestorTableNames(tableName) {
+		ancestor := tableSchema(m, name)
+		if ancestor == nil {
+			return nil, fmt.Errorf("schema of %s is not loaded yet", name)
+		}
+		ancestors = append(ancestors, ancestor)
+	}
+	table := tableSchema(m, tableName)
+	if table == nil {
+		return nil, fmt.Errorf("schema of %s is not loaded yet", tableName)
+	}
+	return importer.NewSchemaFromTables(tableName, table, ancestors)
+}
+
+// openNewRowForm opens the row editor with an empty row of the selected table.
+// Key columns inherited from the ancestors are prefilled from the selected row.
+func openNewRowForm(m Model) (Model, tea.Cmd) {
+	tableName := m.SelectedTableName()
+	if tableName == "" {
+		return m, nil
+	}
+	if m.Connection.ReadOnly {
+		return showFooterMessage(m, readOnlyRefusal)
+	}
+	if m.SQL.CustomSQL {
+		return showFooterMessage(m, "New rows can only be added to table data (esc resets the query)")
+	}
+	schema, err := cachedTableSchema(m, tableName)
+	if err != nil {
+		return showFooterMessage(m, "Cannot add a row: "+err.Error())
+	}
+
+	// Inherited key columns are the primary key columns not declared by the table itself
+	inherited := make(map[string]bool)
+	for _, col := range schema.Columns {
+		inherited[strings.ToLower(col.Name)] = col.PrimaryKey
+	}
+	for _, name := range tableSchema(m, tableName).PrimaryKey {
+		inherited[strings.ToLower(name)] = false
+	}
+	var selectedRow map[string]interface{}
+	if data := m.GetSelectedTableData(); data != nil && m.Data.SelectedDataRow >= 0 && m.Data.SelectedDataRow < len(data.Rows) {
+		selectedRow = data.Rows[m.Data.SelectedDataRow]
+	}
+
+	e := RowEditorState{
+		Visible:   true,
+		Insert:    true,
+		TableName: tableName,
+		Schema:    schema,
+		Field:     -1,
+	}
+	for i, col := range schema.Columns {
+		field := RowEditorField{Column: col, Null: true}
+		if value := selectedRow[col.Name]; inherited[strings.ToLower(col.Name)] && value != nil {
+			field.Text, field.Null = keyText(value), false
+		} else if e.Field < 0 {
+			e.Field = i
+		}
+		e.Fields = append(e.Fields, field)
+	}
+	if e.Field < 0 {
+		e.Field = 0
+	}
+	e.CursorPos = ui.RuneLen(e.Fields[e.Field].Text)
+	m.RowEditor = e
+	return m, nil
+}
+
+// keyText returns the text form of a key value from the Data pane
+func keyText(value interface{}) string {
+	if f, ok := value.(float64); ok {
+		// Query results decode numbers as float64; avoid exponent notation
+		return strconv.FormatFloat(f, 'f', -1, 64)
+	}
+	return fmt.Sprint(value)
+}
+
+// loadRowForEdit returns a command that reads the schema and the current row with its version
+func loadRowForEdit(client *nosqldb.Client, tableName string, rowIndex int, row map[string]interface{}) tea.Cmd {
+	return func() tea.Msg {
+		msg := rowLoadedMsg{TableName: tableName, RowIndex: rowIndex}
+		table, ancestors, err := db.GetTableSchemas(client, tableName)
+		if err != nil {
+			msg.Err = err
+			return msg
+		}
+		if msg.Schema, err = importer.NewSchemaFromTables(tableName, table, ancestors); err != nil {
+			msg.Err = err
+			return msg
+		}
+		key, err := msg.Schema.PrimaryKey(row)
+		if err != nil {
+			msg.Err = err
+			return msg
+		}
+		msg.Row, msg.Version, msg.Err = db.GetRow(client, tableName, key)
+		return msg
+	}
+}
+
+func handleRowLoaded(m Model, msg rowLoadedMsg) (Model, tea.Cmd) {
+	e := &m.RowEditor
+	if !e.Visible || !e.Loading || e.TableName != msg.TableName || e.RowIndex != msg.RowIndex {
+		return m, nil
+	}
+	e.Loading = false
+	e.Conflict = false
+	e.Fields = nil
+	switch {
+	case msg.Err != nil:
+		e.Message = "Load failed: " + msg.Err.Error()
+		return m, nil
+	case msg.Row == nil:
+		e.Message = "The row no longer exists"
+		return m, nil
+	}
+
+	e.Schema = msg.Schema
+	e.Version = msg.Version
+	e.Message = ""
+	for _, col := range msg.Schema.Columns {
+		value, _ := msg.Row.Get(col.Name)
+		e.Fields = append(e.Fields, RowEditorField{
+			Column:   col,
+			Text:     importer.FormatValue(col.Type, value),
+			Null:     value == nil,
+			Original: value,
+		})
+	}
+
+	// Focus the requested column, or the first editable one
+	e.Field = -1
+	for i, field := range e.Fields {
+		if field.Column.PrimaryKey {
+			continue
+		}
+		if e.Field < 0 || strings.EqualFold(field.Column.Name, e.FocusColumn) {
+			e.Field = i
+		}
+	}
+	if e.Field < 0 {
+		e.Field = 0
+		e.Message = "All columns are primary key columns; nothing to edit"
+	}
+	e.CursorPos = ui.RuneLen(e.Fields[e.Field].Text)
+	return m, nil
+}
+
+// editableField reports whether field i of the editor can be changed.
+// Primary key columns can only be set for new rows.
+func (e *RowEditorState) editableField(i int) bool {
+	return i >= 0 && i < len(e.Fields) && (e.Insert || !e.Fields[i].Column.PrimaryKey)
+}
+
+// moveField moves the focus to the next editable field in direction delta
+func (e *RowEditorState) moveField(delta int) {
+	n := len(e.Fields)
+	for step := 1; step <= n; step++ {
+		i := ((e.Field+delta*step)%n + n) % n
+		if e.editableField(i) {
+			e.Field = i
+			e.CursorPos = ui.RuneLen(e.Fields[i].Text)
+			return
+		}
+	}
+}
+
+// required reports whether the field must be set (primary key columns not generated as identity)
+func (f RowEditorField) required() bool {
+	return f.Column.PrimaryKey && !f.Column.Identity
+}
+
+// changed reports whether the field differs from the value read from the table
+func (f RowEditorField) changed() bool {
+	if f.Null || f.Original == nil {
+		return f.Null != (f.Original == nil)
+	}
+	return f.Text != importer.FormatValue(f.Column.Type, f.Original)
+}
+
+// validate converts a changed field, returning an error for invalid input.
+// JSON values must be valid JSON text.
+func (f RowEditorField) validate(schema *importer.Schema) error {
+	if f.Null || !f.changed() {
+		return nil
+	}
+	if importer.IsJSONType(f.Column.Type) && !json.Valid([]byte(f.Text)) {
+		return fmt.Errorf("column %s: invalid JSON", f.Column.Name)
+	}
+	_, err := schema.ConvertField(f.Column.Name, f.Text)
+	return err
+}
+
+func handleRowEditorKeys(m Model, msg tea.KeyMsg) (Model, tea.Cmd) {
+	e := &m.RowEditor
+
+	switch msg.Type {
+	case tea.KeyEscape:
+		m.RowEditor = RowEditorState{}
+		return m, nil
+
+	case tea.KeyCtrlR:
+		// Reload the current row, discarding changes (e.g. after a conflict)
+		if e.Insert || e.Loading || e.Saving {
+			return m, nil
+		}
+		data := m.Data.TableData[e.TableName]
+		if data == nil || e.RowIndex >= len(data.Rows) {
+			return m, nil
+		}
+		e.Loading = true
+		e.Message = ""
+		if e.editableField(e.Field) {
+			e.FocusColumn = e.Fields[e.Field].Column.Name
+		}
+		return m, loadRowForEdit(m.Connection.NosqlClient, e.TableName, e.RowIndex, data.Rows[e.RowIndex])
+	}
+
+	// Other keys need a loaded row that is not being written
+	if e.Loading || e.Saving || len(e.Fields) == 0 {
+		return m, nil
+	}
+
+	switch msg.Type {
+	case tea.KeyTab, tea.KeyDown:
+		e.moveField(1)
+		return m, nil
+
+	case tea.KeyShiftTab, tea.KeyUp:
+		e.moveField(-1)
+		return m, nil
+
+	case tea.KeyCtrlS:
+		return saveRowEditor(m)
+
+	case tea.KeyEnter:
+		// JSON values are multi-line; elsewhere enter saves
+		if !e.editableField(e.Field) || !importer.IsJSONType(e.Fields[e.Field].Column.Type) {
+			return saveRowEditor(m)
+		}
+	}
+
+	if !e.editableField(e.Field) {
+		return m, nil
+	}
+	field := &e.Fields[e.Field]
+	e.Message = ""
+
+	if msg.Type == tea.KeyCtrlD {
+		// Set to null
+		field.Null = true
+		field.Text = ""
+		e.CursorPos = 0
+		return m, nil
+	}
+
+	if importer.TypeName(field.Column.Type) == "BOOLEAN" {
+		// Boolean: toggle with space or left/right
+		switch msg.Type {
+		case tea.KeySpace, tea.KeyLeft, tea.KeyRight:
+			if field.Null || field.Text != "true" {
+				field.Text = "true"
+			} else {
+				field.Text = "false"
+			}
+			field.Null = false
+			e.CursorPos = ui.RuneLen(field.Text)
+		}
+		return m, nil
+	}
+
+	if msg.Type == tea.KeyRunes && !acceptsInput(field.Column.Type, msg.Runes) {
+		return m, nil
+	}
+	text, cursorPos := field.Text, e.CursorPos
+	if msg.Type == tea.KeyEnter {
+		text, cursorPos = ui.InsertWithCursor(text, cursorPos, "\n")
+	} else {
+		text, cursorPos = editTextInput(text, cursorPos, msg)
+	}
+	if text != field.Text {
+		field.Null = false
+	}
+	field.Text, e.CursorPos = text, cursorPos
+	return m, nil
+}
+
+// acceptsInput reports whether runes can be typed into a value of type typ.
+// Numeric columns only accept characters that can appear in a number.
+func acceptsInput(typ string, runes []rune) bool {
+	var allowed string
+	switch importer.TypeName(typ) {
+	case "INTEGER", "LONG":
+		allowed = "0123456789-+"
+	case "FLOAT", "DOUBLE", "NUMBER":
+		allowed = "0123456789-+.eE"
+	default:
+		return true
+	}
+	for _, r := range runes {
+		if !strings.ContainsRune(allowed, r) {
+			return false
+		}
+	}
+	return true
+}
+
+// saveRowEditor validates the fields and writes the row: edited rows only if
+// their version still matches, new rows only if the primary key is not taken
+func saveRowEditor(m Model) (Model, tea.Cmd) {
+	e := &m.RowEditor
+	if e.Insert {
+		return insertRow(m)
+	}
+
+	changed := false
+	for _, field := range e.Fields {
+		changed = changed || field.changed()
+	}
+	if !changed {
+		m.RowEditor = RowEditorState{}
+		return m, nil
+	}
+
+	row := types.NewOrderedMapValue()
+	for i, field := range e.Fields {
+		if err := field.validate(e.Schema); err != nil {
+			e.focusInvalidField(i, err)
+			return m, nil
+		}
+		switch {
+		case field.Null:
+			// Omitted columns are written as null
+		case field.changed():
+			value, _ := e.Schema.ConvertField(field.Column.Name, field.Text)
+			row.Put(field.Column.Name, value)
+		default:
+			row.Put(field.Column.Name, field.Original)
+		}
+	}
+
+	e.Saving = true
+	e.Message = ""
+	e.Conflict = false
+	client, tableName, rowIndex, version := m.Connection.NosqlClient, e.TableName, e.RowIndex, e.Version
+	return m, func() tea.Msg {
+		newVersion, err := db.PutRowIfVersion(client, tableName, row, version)
+		if err != nil {
+			return rowSavedMsg{TableName: tableName, RowIndex: rowIndex, Err: err}
+		}
+		return rowSavedMsg{TableName: tableName, RowIndex: rowIndex, Row: db.DisplayRow(row), Version: newVersion}
+	}
+}
+
+// focusInvalidField moves the focus to field i and shows err
+func (e *RowEditorState) focusInvalidField(i int, err error) {
+	e.Field = i
+	e.CursorPos = ui.RuneLen(e.Fields[i].Text)
+	e.Message = err.Error()
+}
+
+// insertRow writes the new row with PutIfAbsent
+func insertRow(m Model) (Model, tea.Cmd) {
+	e := &m.RowEditor
+	fields := make(map[string]interface{})
+	for i, field := range e.Fields {
+		if field.Null {
+			if field.required() {
+				e.focusInvalidField(i, fmt.Errorf("%s is required", field.Column.Name))
+				return m, nil
+			}
+			continue
+		}
+		if err := field.validate(e.Schema); err != nil {
+			e.focusInvalidField(i, err)
+			return m, nil
+		}
+		fields[field.Column.Name] = field.Text
+	}
+	row, err := e.Schema.Convert(fields)
+	if err != nil {
+		e.Message = err.Error()
+		return m, nil
+	}
+
+	e.Saving = true
+	e.Message = ""
+	client, tableName := m.Connection.NosqlClient, e.TableName
+	return m, func() tea.Msg {
+		errs := db.PutRows(client, tableName, []*types.MapValue{row}, true)
+		return rowInsertedMsg{TableName: tableName, Err: errs[0]}
+	}
+}
+
+func handleRowSaved(m Model, msg rowSavedMsg) (Model, tea.Cmd) {
+	e := &m.RowEditor
+	if !e.Visible || e.Insert || !e.Saving || e.TableName != msg.TableName || e.RowIndex != msg.RowIndex {
+		return m, nil
+	}
+	e.Saving = false
+
+	switch {
+	case errors.Is(msg.Err, db.ErrRowChanged):
+		e.Conflict = true
+		e.Message = "Conflict: the row was changed or deleted by someone else since it was loaded"
+		return m, nil
+	case msg.Err != nil:
+		e.Message = "Save failed: " + msg.Err.Error()
+		return m, nil
+	}
+
+	// Show the written values without refetching, keeping the cursor on the row
+	if data := m.Data.TableData[msg.TableName]; data != nil && msg.RowIndex < len(data.Rows) {
+		data.Rows[msg.RowIndex] = msg.Row
+	}
+	m.RowEditor = RowEditorState{}
+	return showFooterMessage(m, "Row updated")
+}
+
+func handleRowInserted(m Model, msg rowInsertedMsg) (Model, tea.Cmd) {
+	e := &m.RowEditor
+	if !e.Visible || !e.Insert || !e.Saving || e.TableName != msg.TableName {
+		return m, nil
+	}
+	e.Saving = false
+
+	switch {
+	case errors.Is(msg.Err, db.ErrRowExists):
+		e.Message = "A row with this primary key already exists"
+		return m, nil
+	case msg.Err != nil:
+		e.Message = "Insert failed: " + msg.Err.Error()
+		return m, nil
+	}
+
+	m.RowEditor = RowEditorState{}
+	m, cmd := showFooterMessage(m, "Row inserted")
+	return m, tea.Batch(cmd, reloadTableData(&m, msg.TableName))
+}
+
+// columnAtHorizontalOffset returns the Data pane column shown at the left edge of the grid
+func columnAtHorizontalOffset(m Model) string {
+	tableName := m.SelectedTableName()
+	data := m.GetSelectedTableData()
+	if tableName == "" || data == nil {
+		return ""
+	}
+	columns := getColumnsInSchemaOrder(m, tableName, data.Rows)
+	grid := ui.NewGrid(columns, getColumnTypes(m, tableName, columns), data.Rows)
+	pos := 0
+	for _, col := range grid.Columns {
+		pos += col.Width + 1 // +1 for the separator
+		if pos > m.Data.HorizontalOffset {
+			return col.Name
+		}
+	}
+	return ""
+}
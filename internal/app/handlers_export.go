@@ -0,0 +1,289 @@
+package app
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/oracle/nosql-go-sdk/nosqldb"
+
+	"github.com/camikura/dito/internal/db"
+	"github.com/camikura/dito/internal/output"
+	"github.com/camikura/dito/internal/ui"
+)
+
+// exportFormats are the file formats offered by the export dialog
+var exportFormats = []output.Format{output.FormatCSV, output.FormatJSONL, output.FormatJSON}
+
+// exportJob is an export in progress.
+// It is shared by pointer between the model and the command writing the next
+// page; only one such command runs at a time.
+type exportJob struct {
+	path        string
+	file        *os.File
+	writer      output.Writer
+	rows        int
+	client      *nosqldb.Client
+	tableName   string
+	primaryKeys []string
+}
+
+// close flushes the writer and closes the file
+func (j *exportJob) close() error {
+	err := j.writer.Close()
+	if closeErr := j.file.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// exportProgressMsg is sent after each page has been written
+type exportProgressMsg struct {
+	Job    *exportJob
+	Rows   int                // Rows written so far
+	Cursor db.TableDataResult // Last page, used to fetch the next one
+	Err    error
+}
+
+// openExportDialog shows the export dialog for the current result set
+func openExportDialog(m Model) Model {
+	tableName := m.SelectedTableName()
+	if tableName == "" || m.GetSelectedTableData() == nil {
+		return m
+	}
+	format := m.ExportDialog.Format
+	if format == "" {
+		format = output.FormatCSV
+	}
+	path := tableName + "." + string(format)
+	m.ExportDialog = ExportDialogState{
+		Visible:       true,
+		EditPath:      path,
+		EditCursorPos: ui.RuneLen(path),
+		Format:        format,
+	}
+	return m
+}
+
+func handleExportDialogKeys(m Model, msg tea.KeyMsg) (Model, tea.Cmd) {
+	d := &m.ExportDialog
+
+	// While exporting only cancellation is possible
+	if d.Job != nil {
+		if msg.Type == tea.KeyEscape {
+			d.Cancelling = true
+		}
+		return m, nil
+	}
+
+	switch msg.Type {
+	case tea.KeyEscape:
+		if d.ConfirmOverwrite {
+			d.ConfirmOverwrite = false
+			return m, nil
+		}
+		d.Visible = false
+		return m, nil
+
+	case tea.KeyEnter:
+		return startExport(m)
+	}
+
+	// Any other key takes back the confirmation to overwrite
+	d.ConfirmOverwrite = false
+
+	switch msg.Type {
+	case tea.KeyTab, tea.KeyShiftTab, tea.KeyUp, tea.KeyDown:
+		d.Field = 1 - d.Field
+		return m, nil
+	}
+
+	if d.Field == 1 {
+		// Format selector: cycle with space or left/right
+		delta := 0
+		switch msg.Type {
+		case tea.KeySpace, tea.KeyRight:
+			delta = 1
+		case tea.KeyLeft:
+			delta = -1
+		}
+		if delta != 0 {
+			setExportFormat(d, nextExportFormat(d.Format, delta))
+		}
+		return m, nil
+	}
+
+	d.Message = ""
+	d.EditPath, d.EditCursorPos = editTextInput(d.EditPath, d.EditCursorPos, msg)
+	return m, nil
+}
+
+// nextExportFormat returns the format delta steps from current
+func nextExportFormat(current output.Format, delta int) output.Format {
+	index := 0
+	for i, format := range exportFormats {
+		if format == current {
+			index = i
+		}
+	}
+	n := len(exportFormats)
+	return exportFormats[((index+delta)%n+n)%n]
+}
+
+// setExportFormat changes the format, replacing the file extension when it matches the old format
+func setExportFormat(d *ExportDialogState, format output.Format) {
+	oldExt := "." + string(d.Format)
+	if strings.HasSuffix(d.EditPath, oldExt) {
+		d.EditPath = strings.TrimSuffix(d.EditPath, oldExt) + "." + string(format)
+		d.EditCursorPos = ui.RuneLen(d.EditPath)
+	}
+	d.Format = format
+}
+
+// startExport creates the file and writes the rows already loaded,
+// then keeps fetching pages until the result set is exhausted.
+// An existing file is only overwritten after confirmation.
+func startExport(m Model) (Model, tea.Cmd) {
+	d := &m.ExportDialog
+	tableName := m.SelectedTableName()
+	data := m.GetSelectedTableData()
+	if tableName == "" || data == nil {
+		d.Visible = false
+		return m, nil
+	}
+
+	path := strings.TrimSpace(d.EditPath)
+	if path == "" {
+		d.Message = "File path is required"
+		return m, nil
+	}
+
+	flags := os.O_WRONLY | os.O_CREATE | os.O_EXCL
+	if d.ConfirmOverwrite {
+		flags = os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	}
+	file, err := os.OpenFile(path, flags, 0o666)
+	if os.IsExist(err) {
+		d.ConfirmOverwrite = true
+		d.Message = ""
+		return m, nil
+	}
+	d.ConfirmOverwrite = false
+	if err != nil {
+		d.Message = err.Error()
+		return m, nil
+	}
+
+	// Same column order as the Data pane
+	columns := getColumnsInSchemaOrder(m, tableName, data.Rows)
+	writer, err := output.NewWriter(file, d.Format, columns)
+	if err != nil {
+		file.Close()
+		os.Remove(path)
+		d.Message = err.Error()
+		return m, nil
+	}
+
+	primaryKeys := tablePrimaryKeys(m, m.SelectedTableName())
+
+	job := &exportJob{
+		path:        path,
+		file:        file,
+		writer:      writer,
+		client:      m.Connection.NosqlClient,
+		tableName:   tableName,
+		primaryKeys: primaryKeys,
+	}
+	d.Job = job
+	d.Rows = 0
+	d.Cancelling = false
+	d.Message = ""
+	return m, writeExportPage(job, data.Rows, *data)
+}
+
+// writeExportPage returns a command that writes rows and reports progress
+func writeExportPage(job *exportJob, rows []map[string]interface{}, cursor db.TableDataResult) tea.Cmd {
+	return func() tea.Msg {
+		for _, row := range rows {
+			if err := job.writer.WriteRow(row); err != nil {
+				return exportProgressMsg{Job: job, Rows: job.rows, Err: err}
+			}
+			job.rows++
+		}
+		return exportProgressMsg{Job: job, Rows: job.rows, Cursor: cursor}
+	}
+}
+
+// fetchExportPage returns a command that fetches the page after cursor and writes it.
+// It continues the Data pane's pagination: OFFSET for custom SQL, PRIMARY KEY cursor otherwise.
+func fetchExportPage(job *exportJob, cursor db.TableDataResult) tea.Cmd {
+	var fetch tea.Cmd
+	if cursor.IsCustomSQL {
+		fetch = db.FetchMoreCustomSQL(job.client, job.tableName, cursor.CurrentSQL, ui.ExportFetchSize, cursor.Offset)
+	} else {
+		fetch = db.FetchMoreTableData(job.client, job.tableName, ui.ExportFetchSize, job.primaryKeys, cursor.LastPKValues)
+	}
+	return func() tea.Msg {
+		result, _ := fetch().(db.TableDataResult)
+		if result.Err != nil {
+			return exportProgressMsg{Job: job, Rows: job.rows, Err: result.Err}
+		}
+		return writeExportPage(job, result.Rows, result)()
+	}
+}
+
+// exportHasMore reports whether another page can be fetched after cursor
+func exportHasMore(cursor db.TableDataResult) bool {
+	if !cursor.HasMore {
+		return false
+	}
+	if cursor.IsCustomSQL {
+		return cursor.CurrentSQL != ""
+	}
+	return cursor.LastPKValues != nil
+}
+
+func handleExportProgress(m Model, msg exportProgressMsg) (Model, tea.Cmd) {
+	d := &m.ExportDialog
+	if msg.Job == nil || msg.Job != d.Job {
+		return m, nil
+	}
+	d.Rows = msg.Rows
+
+	switch {
+	case msg.Err != nil:
+		msg.Job.close()
+		os.Remove(msg.Job.path)
+		d.Job = nil
+		d.Message = "Export failed: " + msg.Err.Error()
+		return m, nil
+
+	case d.Cancelling:
+		msg.Job.close()
+		os.Remove(msg.Job.path)
+		d.Job = nil
+		d.Visible = false
+		return showFooterMessage(m, "Export cancelled")
+
+	case exportHasMore(msg.Cursor):
+		return m, fetchExportPage(msg.Job, msg.Cursor)
+	}
+
+	d.Job = nil
+	if err := msg.Job.close(); err != nil {
+		d.Message = "Export failed: " + err.Error()
+		return m, nil
+	}
+	d.Visible = false
+	return showFooterMessage(m, fmt.Sprintf("Exported %d rows to %s", msg.Rows, msg.Job.path))
+}
+
+// showFooterMessage shows a temporary message in the footer
+func showFooterMessage(m Model, message string) (Model, tea.Cmd) {
+	m.UI.CopyMessage = message
+	return m, tea.Tick(ui.CopyMessageDuration, func(_ time.Time) tea.Msg {
+		return clearCopyMessageMsg{}
+	})
+}
@@ -0,0 +1,254 @@
+package app
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/oracle/nosql-go-sdk/nosqldb"
+
+	"github.com/camikura/dito/internal/db"
+	"github.com/camikura/dito/internal/importer"
+	"github.com/camikura/dito/internal/ui"
+)
+
+// importFieldCount is the number of fields in the import dialog
+const importFieldCount = 3
+
+// importJob is an import in progress.
+// It is shared by pointer between the model and the command importing the next
+// chunk; only one such command runs at a time.
+type importJob struct {
+	file        *os.File
+	reader      importer.Reader
+	importer    *importer.Importer // Created by the first command, once the schema is loaded
+	client      *nosqldb.Client
+	tableName   string
+	mode        importer.Mode
+	rejectsPath string
+}
+
+// close closes the input and rejects files
+func (j *importJob) close() error {
+	var err error
+	if j.importer != nil {
+		err = j.importer.Close()
+	}
+	if closeErr := j.file.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// importProgressMsg is sent after each chunk of records has been imported
+type importProgressMsg struct {
+	Job   *importJob
+	Stats importer.Stats
+	Done  bool
+	Err   error
+}
+
+// openImportDialog shows the import dialog for the table under cursor
+func openImportDialog(m Model) Model {
+	tableName := m.CursorTableName()
+	if tableName == "" || m.Connection.NosqlClient == nil {
+		return m
+	}
+	mode := m.ImportDialog.Mode
+	if mode == "" {
+		mode = importer.ModeUpsert
+	}
+	m.ImportDialog = ImportDialogState{
+		Visible:   true,
+		TableName: tableName,
+		Mode:      mode,
+	}
+	setImportPath(&m.ImportDialog, tableName+".csv")
+	return m
+}
+
+// setImportPath sets the input path; the rejects path follows it until edited
+func setImportPath(d *ImportDialogState, path string) {
+	d.EditPath = path
+	d.EditCursorPos = ui.RuneLen(path)
+	if !d.RejectsEdited {
+		d.EditRejects = importer.DefaultRejectsPath(path)
+		d.RejectsCursorPos = ui.RuneLen(d.EditRejects)
+	}
+}
+
+func handleImportDialogKeys(m Model, msg tea.KeyMsg) (Model, tea.Cmd) {
+	d := &m.ImportDialog
+
+	// While importing only cancellation is possible
+	if d.Job != nil {
+		if msg.Type == tea.KeyEscape {
+			d.Cancelling = true
+		}
+		return m, nil
+	}
+
+	switch msg.Type {
+	case tea.KeyEscape:
+		d.Visible = false
+		return m, nil
+
+	case tea.KeyTab, tea.KeyDown:
+		d.Field = (d.Field + 1) % importFieldCount
+		return m, nil
+
+	case tea.KeyShiftTab, tea.KeyUp:
+		d.Field = (d.Field + importFieldCount - 1) % importFieldCount
+		return m, nil
+
+	case tea.KeyEnter:
+		return startImport(m)
+	}
+
+	d.Message = ""
+	switch d.Field {
+	case 0:
+		path, cursorPos := editTextInput(d.EditPath, d.EditCursorPos, msg)
+		setImportPath(d, path)
+		d.EditCursorPos = cursorPos
+	case 1:
+		// Mode selector: toggle with space or left/right
+		switch msg.Type {
+		case tea.KeySpace, tea.KeyLeft, tea.KeyRight:
+			if d.Mode == importer.ModeInsert {
+				d.Mode = importer.ModeUpsert
+			} else {
+				d.Mode = importer.ModeInsert
+			}
+		}
+	case 2:
+		rejects, cursorPos := editTextInput(d.EditRejects, d.RejectsCursorPos, msg)
+		if rejects != d.EditRejects {
+			d.RejectsEdited = true
+		}
+		d.EditRejects, d.RejectsCursorPos = rejects, cursorPos
+	}
+	return m, nil
+}
+
+// startImport opens the input file and starts importing it in chunks
+func startImport(m Model) (Model, tea.Cmd) {
+	d := &m.ImportDialog
+	path := strings.TrimSpace(d.EditPath)
+	if path == "" {
+		d.Message = "File path is required"
+		return m, nil
+	}
+	format, err := importer.FormatForPath(path)
+	if err != nil {
+		d.Message = err.Error()
+		return m, nil
+	}
+	rejectsPath := strings.TrimSpace(d.EditRejects)
+	if rejectsPath == "" {
+		rejectsPath = importer.DefaultRejectsPath(path)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		d.Message = err.Error()
+		return m, nil
+	}
+	reader, err := importer.NewReader(file, format)
+	if err != nil {
+		file.Close()
+		d.Message = err.Error()
+		return m, nil
+	}
+
+	job := &importJob{
+		file:        file,
+		reader:      reader,
+		client:      m.Connection.NosqlClient,
+		tableName:   d.TableName,
+		mode:        d.Mode,
+		rejectsPath: rejectsPath,
+	}
+	d.Job = job
+	d.Stats = importer.Stats{}
+	d.Cancelling = false
+	d.Message = ""
+	return m, importStep(job)
+}
+
+// importStep returns a command that imports the next chunk of records.
+// The first call loads the table schema and creates the importer.
+func importStep(job *importJob) tea.Cmd {
+	return func() tea.Msg {
+		if job.importer == nil {
+			table, ancestors, err := db.GetTableSchemas(job.client, job.tableName)
+			if err != nil {
+				return importProgressMsg{Job: job, Err: err}
+			}
+			schema, err := importer.NewSchemaFromTables(job.tableName, table, ancestors)
+			if err != nil {
+				return importProgressMsg{Job: job, Err: err}
+			}
+			job.importer = importer.New(schema, job.reader, importer.ClientWriter(job.client, job.tableName, job.mode),
+				importer.Options{RejectsPath: job.rejectsPath})
+		}
+		done, err := job.importer.Step(ui.ImportStepRecords)
+		return importProgressMsg{Job: job, Stats: job.importer.Stats(), Done: done, Err: err}
+	}
+}
+
+func handleImportProgress(m Model, msg importProgressMsg) (Model, tea.Cmd) {
+	d := &m.ImportDialog
+	if msg.Job == nil || msg.Job != d.Job {
+		return m, nil
+	}
+	d.Stats = msg.Stats
+
+	switch {
+	case msg.Err != nil:
+		msg.Job.close()
+		d.Job = nil
+		d.Message = "Import failed: " + msg.Err.Error()
+		return m, nil
+
+	case d.Cancelling:
+		// Rows waiting for a full batch go to the rejects file
+		var err error
+		if msg.Job.importer != nil {
+			err = msg.Job.importer.Cancel()
+			d.Stats = msg.Job.importer.Stats()
+		}
+		if closeErr := msg.Job.close(); err == nil {
+			err = closeErr
+		}
+		d.Job = nil
+		if err != nil {
+			d.Message = "Import failed: " + err.Error()
+			return m, nil
+		}
+		d.Visible = false
+		message := fmt.Sprintf("Import cancelled after %d rows", d.Stats.Written)
+		if d.Stats.Rejected > 0 {
+			message += fmt.Sprintf(", %d rejected (see %s)", d.Stats.Rejected, msg.Job.rejectsPath)
+		}
+		m, cmd := showFooterMessage(m, message)
+		return m, tea.Batch(cmd, reloadTableData(&m, msg.Job.tableName))
+
+	case !msg.Done:
+		return m, importStep(msg.Job)
+	}
+
+	d.Job = nil
+	if err := msg.Job.close(); err != nil {
+		d.Message = "Import failed: " + err.Error()
+		return m, nil
+	}
+	d.Visible = false
+	message := fmt.Sprintf("Imported %d rows into %s", msg.Stats.Written, msg.Job.tableName)
+	if msg.Stats.Rejected > 0 {
+		message += fmt.Sprintf(", %d rejected (see %s)", msg.Stats.Rejected, msg.Job.rejectsPath)
+	}
+	m, cmd := showFooterMessage(m, message)
+	return m, tea.Batch(cmd, reloadTableData(&m, msg.Job.tableName))
+}
@@ -0,0 +1,248 @@
+package app
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/oracle/nosql-go-sdk/nosqldb"
+
+	"github.com/camikura/dito/internal/ui"
+)
+
+// Inputs of an index field row
+const (
+	indexCellPath = iota
+	indexCellType
+)
+
+// schemaCursorIndex returns the index under the Schema pane cursor
+func schemaCursorIndex(m Model, tableName string) (nosqldb.IndexInfo, bool) {
+	nodes, indexes, cursor := schemaItems(m, tableName)
+	if cursor < len(nodes) || cursor-len(nodes) >= len(indexes) {
+		return nosqldb.IndexInfo{}, false
+	}
+	return indexes[cursor-len(nodes)], true
+}
+
+// schemaFooterHelp returns the footer help of the Schema pane
+func schemaFooterHelp(m Model) string {
+	tableName := schemaPaneTable(m)
+	if tableName == "" {
+		return ""
+	}
+	var help []string
+	if node, _, ok := schemaCursorNode(m, tableName); ok {
+		switch {
+		case node.Expanded:
+			help = append(help, "Collapse: <enter>")
+		case node.Expandable():
+			help = append(help, "Expand: <enter>")
+		}
+		help = append(help, "Insert path: i")
+	}
+	_, hasIndex := schemaCursorIndex(m, tableName)
+	if hasIndex {
+		help = append(help, "Inspect index: <enter>")
+	}
+	if !m.Connection.ReadOnly {
+		help = append(help, "New index: n")
+		if hasIndex {
+			help = append(help, "Drop index: d")
+		}
+	}
+	help = append(help, "Table info: t")
+	if m.Schema.InfoExpanded {
+		help = append(help, "Usage: u", "Auto refresh: a")
+	}
+	help = append(help, "Refresh: r")
+	return strings.Join(help, " | ")
+}
+
+// openIndexDialog shows the create index dialog for tableName.
+// The first field is set to the first non-key column of the table.
+func openIndexDialog(m Model, tableName string) (Model, tea.Cmd) {
+	if m.Connection.NosqlClient == nil {
+		return m, nil
+	}
+
+	field := ui.IndexField{}
+	for _, col := range ui.ColumnsFromTable(tableSchema(m, tableName)) {
+		if !col.IsPrimaryKey {
+			field.Path = col.Name
+			break
+		}
+	}
+
+	m.IndexDialog = IndexDialogState{
+		Visible: true,
+		Design:  ui.IndexDesign{TableName: tableName, Fields: []ui.IndexField{field}},
+	}
+	return m, nil
+}
+
+// cellCount returns the number of inputs of a row
+func (s IndexDialogState) cellCount(row int) int {
+	if row == 0 {
+		return 1
+	}
+	return 2
+}
+
+// cellText returns a pointer to the text of the focused input, or nil for the type picker
+func (s *IndexDialogState) cellText() *string {
+	if s.Row == 0 {
+		return &s.Design.Name
+	}
+	if s.Cell == indexCellPath {
+		return &s.Design.Fields[s.Row-1].Path
+	}
+	return nil
+}
+
+// focus moves the focus to a row and cell, placing the cursor at the end of text inputs
+func (s *IndexDialogState) focus(row, cell int) {
+	s.Row = row
+	s.Cell = min(cell, s.cellCount(row)-1)
+	if text := s.cellText(); text != nil {
+		s.CursorPos = ui.RuneLen(*text)
+	}
+}
+
+func handleIndexDialogKeys(m Model, msg tea.KeyMsg) (Model, tea.Cmd) {
+	s := &m.IndexDialog
+	rows := 1 + len(s.Design.Fields)
+
+	switch msg.String() {
+	case "esc":
+		s.Visible = false
+		return m, nil
+
+	case "ctrl+s":
+		ddl, err := s.Design.DDL()
+		if err != nil {
+			return m, nil
+		}
+		s.Visible = false
+		return executeDDL(m, ddl)
+
+	case "tab":
+		if s.Cell+1 < s.cellCount(s.Row) {
+			s.focus(s.Row, s.Cell+1)
+		} else {
+			s.focus((s.Row+1)%rows, 0)
+		}
+		return m, nil
+
+	case "shift+tab":
+		if s.Cell > 0 {
+			s.focus(s.Row, s.Cell-1)
+		} else {
+			row := (s.Row + rows - 1) % rows
+			s.focus(row, s.cellCount(row)-1)
+		}
+		return m, nil
+
+	case "up":
+		if s.Row > 0 {
+			s.focus(s.Row-1, s.Cell)
+		}
+		return m, nil
+
+	case "down":
+		if s.Row+1 < rows {
+			s.focus(s.Row+1, s.Cell)
+		}
+		return m, nil
+
+	case "enter", "ctrl+n":
+		// Insert an empty field after the focused one
+		at := len(s.Design.Fields)
+		if s.Row > 0 {
+			at = s.Row
+		}
+		s.Design.Fields = slices.Insert(s.Design.Fields, at, ui.IndexField{})
+		s.focus(at+1, indexCellPath)
+		return m, nil
+
+	case "ctrl+d":
+		// Delete the focused field
+		if s.Row > 0 && len(s.Design.Fields) > 1 {
+			s.Design.Fields = slices.Delete(s.Design.Fields, s.Row-1, s.Row)
+			s.focus(min(s.Row, rows-2), s.Cell)
+		}
+		return m, nil
+
+	case "alt+up", "alt+down":
+		// Move the focused field; the index is ordered by its fields
+		i := s.Row - 1
+		j := i - 1
+		if msg.String() == "alt+down" {
+			j = i + 1
+		}
+		if i >= 0 && j >= 0 && j < len(s.Design.Fields) {
+			s.Design.Fields[i], s.Design.Fields[j] = s.Design.Fields[j], s.Design.Fields[i]
+			s.Row = j + 1
+		}
+		return m, nil
+	}
+
+	if text := s.cellText(); text != nil {
+		*text, s.CursorPos = editTextInput(*text, s.CursorPos, msg)
+		return m, nil
+	}
+
+	field := &s.Design.Fields[s.Row-1]
+	switch msg.Type {
+	case tea.KeySpace, tea.KeyRight:
+		field.Type = cycle(ui.IndexFieldTypes, field.Type, 1)
+	case tea.KeyLeft:
+		field.Type = cycle(ui.IndexFieldTypes, field.Type, -1)
+	}
+	return m, nil
+}
+
+func handleIndexDetailKeys(m Model, msg tea.KeyMsg) (Model, tea.Cmd) {
+	d := &m.IndexDetail
+	switch msg.String() {
+	case "esc":
+		m.IndexDetail = IndexDetailState{}
+		return m, nil
+
+	case "d":
+		if !m.Connection.ReadOnly {
+			d.ConfirmDrop = true
+		}
+		return m, nil
+
+	case "y":
+		if !d.ConfirmDrop {
+			return m, nil
+		}
+		sql := fmt.Sprintf("DROP INDEX %s ON %s", d.Index.IndexName, d.TableName)
+		m.IndexDetail = IndexDetailState{}
+		return executeDDL(m, sql)
+	}
+	return m, nil
+}
+
+// indexFieldTypes returns the type of each field of an index: the declared type
+// of JSON fields, otherwise the type of the indexed column ("" when unknown)
+func indexFieldTypes(m Model, tableName string, index nosqldb.IndexInfo) []string {
+	columnTypes := make(map[string]string)
+	columns := append(inheritedKeyColumns(m, tableName), ui.ColumnsFromTable(tableSchema(m, tableName))...)
+	for _, col := range columns {
+		columnTypes[strings.ToLower(col.Name)] = col.Type
+	}
+
+	types := make([]string, len(index.FieldNames))
+	for i, field := range index.FieldNames {
+		if i < len(index.FieldTypes) && index.FieldTypes[i] != "" {
+			types[i] = strings.ToUpper(index.FieldTypes[i])
+			continue
+		}
+		types[i] = columnTypes[strings.ToLower(field)]
+	}
+	return types
+}
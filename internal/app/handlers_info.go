@@ -0,0 +1,141 @@
+package app
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/oracle/nosql-go-sdk/nosqldb"
+	"github.com/oracle/nosql-go-sdk/nosqldb/types"
+
+	"github.com/camikura/dito/internal/db"
+)
+
+// usageRefreshInterval is how often table usage is fetched while auto refresh is on
+const usageRefreshInterval = 10 * time.Second
+
+// usageTickMsg asks for the next periodic usage refresh
+type usageTickMsg struct {
+	Ticker int // UsageTicker at the time the tick was scheduled
+}
+
+// toggleTableInfo expands or collapses the Table info section of the Schema pane.
+// Usage is fetched the first time the section is shown for a table.
+func toggleTableInfo(m Model, tableName string) (Model, tea.Cmd) {
+	m.Schema.InfoExpanded = !m.Schema.InfoExpanded
+	if m.Schema.InfoExpanded && m.Schema.Usage[tableName] == nil {
+		return m, fetchTableUsage(m, tableName)
+	}
+	return m, nil
+}
+
+// refreshTableUsage fetches the usage of tableName now, showing the Table info section
+func refreshTableUsage(m Model, tableName string) (Model, tea.Cmd) {
+	m.Schema.InfoExpanded = true
+	return m, fetchTableUsage(m, tableName)
+}
+
+// toggleUsageRefresh starts or stops refreshing the usage of the table shown
+// in the Schema pane every usageRefreshInterval
+func toggleUsageRefresh(m Model, tableName string) (Model, tea.Cmd) {
+	m.Schema.UsageRefresh = !m.Schema.UsageRefresh
+	m.Schema.UsageTicker++
+	if !m.Schema.UsageRefresh {
+		return showFooterMessage(m, "Usage auto refresh off")
+	}
+	m.Schema.InfoExpanded = true
+	m, tick := showFooterMessage(m, fmt.Sprintf("Refreshing usage every %s", usageRefreshInterval))
+	return m, tea.Batch(tick, usageTick(m.Schema.UsageTicker), fetchTableUsage(m, tableName))
+}
+
+// fetchTableUsage returns a command that fetches the usage of tableName, or nil when not connected
+func fetchTableUsage(m Model, tableName string) tea.Cmd {
+	if m.Connection.NosqlClient == nil {
+		return nil
+	}
+	return db.FetchTableUsage(m.Connection.NosqlClient, tableName)
+}
+
+// usageTick schedules the next usage refresh of ticker
+func usageTick(ticker int) tea.Cmd {
+	return tea.Tick(usageRefreshInterval, func(_ time.Time) tea.Msg {
+		return usageTickMsg{Ticker: ticker}
+	})
+}
+
+func handleUsageTick(m Model, msg usageTickMsg) (Model, tea.Cmd) {
+	if !m.Schema.UsageRefresh || msg.Ticker != m.Schema.UsageTicker || m.Connection.NosqlClient == nil {
+		return m, nil
+	}
+	cmds := []tea.Cmd{usageTick(msg.Ticker)}
+	if tableName := schemaPaneTable(m); tableName != "" {
+		cmds = append(cmds, fetchTableUsage(m, tableName))
+	}
+	return m, tea.Batch(cmds...)
+}
+
+func handleTableUsageResult(m Model, msg db.TableUsageResult) (Model, tea.Cmd) {
+	if m.Schema.Usage == nil {
+		m.Schema.Usage = make(map[string]*db.TableUsageResult)
+	}
+	m.Schema.Usage[msg.TableName] = &msg
+	return m, nil
+}
+
+// tableInfoLines returns the label and value of each line of the Table info section
+func tableInfoLines(m Model, tableName string) [][2]string {
+	details := m.Schema.TableDetails[tableName]
+	if details == nil || details.Schema == nil {
+		return nil
+	}
+
+	state := tableOperation(m, tableName)
+	if state == "" {
+		state = db.TableStateLabel(details.Schema.State)
+	}
+	ttl := "none"
+	if t := details.Table().TTL; t != nil {
+		ttl = fmt.Sprintf("%d %s", t.Value, t.Unit)
+	}
+	lines := [][2]string{
+		{"State", state},
+		{"TTL", ttl},
+		{"Limits", formatTableLimits(details.Schema.Limits)},
+	}
+
+	usage := m.Schema.Usage[tableName]
+	switch {
+	case usage == nil:
+		lines = append(lines, [2]string{"Usage", "not loaded (u to load)"})
+	case usage.Err != nil:
+		lines = append(lines, [2]string{"Usage", "unavailable: " + usage.Err.Error()})
+	case usage.Usage == nil:
+		lines = append(lines, [2]string{"Usage", "no usage records"})
+	default:
+		u := usage.Usage
+		lines = append(lines,
+			[2]string{"Usage", fmt.Sprintf("%d RU, %d WU, %d GB (%s-%s)", u.ReadUnits, u.WriteUnits, u.StorageGB,
+				u.StartTime.Local().Format("15:04:05"), u.EndTime.Local().Format("15:04:05"))},
+			[2]string{"Throttled", fmt.Sprintf("read %d, write %d, storage %d",
+				u.ReadThrottleCount, u.WriteThrottleCount, u.StorageThrottleCount)},
+		)
+	}
+
+	refresh := "off"
+	if m.Schema.UsageRefresh {
+		refresh = "every " + usageRefreshInterval.String()
+	}
+	return append(lines, [2]string{"Refresh", refresh})
+}
+
+// formatTableLimits describes the throughput and storage limits of a table.
+// On-premise tables have no limits.
+func formatTableLimits(limits nosqldb.TableLimits) string {
+	switch {
+	case limits.CapacityMode == types.OnDemand:
+		return fmt.Sprintf("on demand, %d GB", limits.StorageGB)
+	case limits == nosqldb.TableLimits{}:
+		return "none"
+	}
+	return fmt.Sprintf("%d RU, %d WU, %d GB", limits.ReadUnits, limits.WriteUnits, limits.StorageGB)
+}
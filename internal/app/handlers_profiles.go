@@ -0,0 +1,292 @@
+package app
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/camikura/dito/internal/config"
+	"github.com/camikura/dito/internal/db"
+	"github.com/camikura/dito/internal/ui"
+)
+
+// profilesSavedMsg is sent after the profiles file has been written
+type profilesSavedMsg struct {
+	Err error
+}
+
+// saveProfiles returns a command that writes the profiles file
+func saveProfiles(path string, profiles []config.Profile) tea.Cmd {
+	if path == "" {
+		return nil
+	}
+	// Copy so later edits to the model do not race with the write
+	snapshot := append([]config.Profile(nil), profiles...)
+	return func() tea.Msg {
+		return profilesSavedMsg{Err: config.SaveProfiles(path, snapshot)}
+	}
+}
+
+// openProfilePicker shows the profile picker with the cursor on the active profile
+func openProfilePicker(m Model) Model {
+	cursor := config.FindProfile(m.Profiles.Items, m.Profiles.Active)
+	if cursor < 0 {
+		cursor = 0
+	}
+	m.ProfilePicker = ProfilePickerState{Visible: true, Cursor: cursor}
+	return m
+}
+
+func handleProfilePickerKeys(m Model, msg tea.KeyMsg) (Model, tea.Cmd) {
+	picker := &m.ProfilePicker
+
+	if picker.Renaming {
+		return handleProfileRenameKeys(m, msg)
+	}
+
+	if picker.ConfirmDelete {
+		picker.ConfirmDelete = false
+		if msg.String() != "y" || picker.Cursor >= len(m.Profiles.Items) {
+			return m, nil
+		}
+		name := m.Profiles.Items[picker.Cursor].Name
+		items := append([]config.Profile(nil), m.Profiles.Items[:picker.Cursor]...)
+		m.Profiles.Items = append(items, m.Profiles.Items[picker.Cursor+1:]...)
+		if m.Profiles.Active == name {
+			m.Profiles.Active = ""
+		}
+		if picker.Cursor >= len(m.Profiles.Items) && picker.Cursor > 0 {
+			picker.Cursor--
+		}
+		picker.Message = "Deleted " + name
+		return m, saveProfiles(m.Profiles.Path, m.Profiles.Items)
+	}
+
+	hasProfile := picker.Cursor >= 0 && picker.Cursor < len(m.Profiles.Items)
+	picker.Message = ""
+
+	switch msg.String() {
+	case "esc":
+		picker.Visible = false
+		return m, nil
+
+	case "up", "ctrl+p":
+		if picker.Cursor > 0 {
+			picker.Cursor--
+		}
+		return m, nil
+
+	case "down", "ctrl+n":
+		if picker.Cursor < len(m.Profiles.Items)-1 {
+			picker.Cursor++
+		}
+		return m, nil
+
+	case "enter":
+		if hasProfile {
+			return connectWithProfile(m, m.Profiles.Items[picker.Cursor])
+		}
+		return m, nil
+
+	case "n":
+		return openProfileEditor(m, -1), nil
+
+	case "e":
+		if hasProfile {
+			return openProfileEditor(m, picker.Cursor), nil
+		}
+		return m, nil
+
+	case "r":
+		if hasProfile {
+			picker.Renaming = true
+			picker.EditName = m.Profiles.Items[picker.Cursor].Name
+			picker.EditCursorPos = ui.RuneLen(picker.EditName)
+		}
+		return m, nil
+
+	case "d":
+		if hasProfile {
+			picker.ConfirmDelete = true
+		}
+		return m, nil
+	}
+
+	return m, nil
+}
+
+func handleProfileRenameKeys(m Model, msg tea.KeyMsg) (Model, tea.Cmd) {
+	picker := &m.ProfilePicker
+
+	switch msg.Type {
+	case tea.KeyEsc:
+		picker.Renaming = false
+		picker.Message = ""
+		return m, nil
+
+	case tea.KeyEnter:
+		name := strings.TrimSpace(picker.EditName)
+		if err := config.ValidateName(m.Profiles.Items, name, picker.Cursor); err != nil {
+			picker.Message = err.Error()
+			return m, nil
+		}
+		picker.Renaming = false
+		picker.Message = ""
+		oldName := m.Profiles.Items[picker.Cursor].Name
+		m.Profiles.Items = append([]config.Profile(nil), m.Profiles.Items...)
+		m.Profiles.Items[picker.Cursor].Name = name
+		if m.Profiles.Active == oldName {
+			m.Profiles.Active = name
+		}
+		return m, saveProfiles(m.Profiles.Path, m.Profiles.Items)
+	}
+
+	picker.EditName, picker.EditCursorPos = editTextInput(picker.EditName, picker.EditCursorPos, msg)
+	return m, nil
+}
+
+// editTextInput applies a single-line editing key to value and returns the new value and cursor position
+func editTextInput(value string, cursorPos int, msg tea.KeyMsg) (string, int) {
+	switch msg.Type {
+	case tea.KeyBackspace:
+		return ui.Backspace(value, cursorPos)
+	case tea.KeyDelete:
+		return ui.DeleteAt(value, cursorPos), cursorPos
+	case tea.KeyLeft:
+		if cursorPos > 0 {
+			cursorPos--
+		}
+	case tea.KeyRight:
+		if cursorPos < ui.RuneLen(value) {
+			cursorPos++
+		}
+	case tea.KeyHome:
+		cursorPos = 0
+	case tea.KeyEnd:
+		cursorPos = ui.RuneLen(value)
+	case tea.KeySpace:
+		return ui.InsertWithCursor(value, cursorPos, " ")
+	case tea.KeyRunes:
+		return ui.InsertWithCursor(value, cursorPos, string(msg.Runes))
+	}
+	return value, cursorPos
+}
+
+// connectWithProfile resolves the profile secrets and connects
+func connectWithProfile(m Model, profile config.Profile) (Model, tea.Cmd) {
+	cfg, err := profile.ConnectionConfig()
+	if err != nil {
+		m.ProfilePicker.Message = err.Error()
+		return m, nil
+	}
+	m.ProfilePicker.Visible = false
+	m.Profiles.Active = profile.Name
+	m.Connection.Endpoint = cfg.DisplayEndpoint()
+	m.Connection.Edition = cfg.EffectiveEdition()
+	m.Connection.Message = ""
+	return m, db.Connect(cfg, false)
+}
+
+// openProfileEditor opens the connection dialog to edit the profile at index (-1 creates a new profile)
+func openProfileEditor(m Model, index int) Model {
+	d := ConnectionDialogState{Edition: db.EditionOnPremise}
+	if index >= 0 && index < len(m.Profiles.Items) {
+		d = dialogFromProfile(m.Profiles.Items[index])
+	} else {
+		index = -1
+	}
+	d.Visible = true
+	d.ProfileMode = true
+	d.ProfileIndex = index
+	initConnectionDialogDefaults(&d)
+	d.EditCursorPos = ui.RuneLen(d.EditProfileName)
+
+	m.ProfilePicker.Visible = false
+	m.ConnectionDialog = d
+	return m
+}
+
+// dialogFromProfile fills the connection dialog from a saved profile
+func dialogFromProfile(p config.Profile) ConnectionDialogState {
+	edition := p.Edition
+	if edition == "" {
+		edition = db.EditionOnPremise
+	}
+	return ConnectionDialogState{
+		Edition:           edition,
+		EditEndpoint:      p.Endpoint,
+		EditPort:          p.Port,
+		UseHTTPS:          p.UseHTTPS,
+		EditCertPath:      p.CertPath,
+		SkipVerify:        p.InsecureSkipVerify,
+		EditUsername:      p.Username,
+		EditOCIConfigFile: p.OCIConfigFile,
+		EditOCIProfile:    p.OCIProfile,
+		EditRegion:        p.Region,
+		EditCompartment:   p.Compartment,
+		EditTenantID:      p.TenantID,
+		EditNamespace:     p.Namespace,
+		ReadOnly:          p.ReadOnly,
+		EditProfileName:   p.Name,
+		EditPasswordEnv:   p.PasswordEnv,
+		EditPasswordFile:  p.PasswordFile,
+	}
+}
+
+// Profile builds a saved profile from the dialog values.
+// Only fields of the selected edition are kept, and the password itself is never stored.
+func (d ConnectionDialogState) Profile() config.Profile {
+	p := config.Profile{Name: strings.TrimSpace(d.EditProfileName), Edition: d.Edition, ReadOnly: d.ReadOnly}
+	switch d.Edition {
+	case db.EditionCloud:
+		p.OCIConfigFile = d.EditOCIConfigFile
+		p.OCIProfile = d.EditOCIProfile
+		p.Region = d.EditRegion
+		p.Compartment = d.EditCompartment
+	case db.EditionCloudSim:
+		p.Endpoint = d.EditEndpoint
+		p.Port = d.EditPort
+		p.TenantID = d.EditTenantID
+	default:
+		p.Edition = db.EditionOnPremise
+		p.Endpoint = d.EditEndpoint
+		p.Port = d.EditPort
+		p.UseHTTPS = d.UseHTTPS
+		p.CertPath = d.EditCertPath
+		p.InsecureSkipVerify = d.SkipVerify
+		p.Username = d.EditUsername
+		p.PasswordEnv = d.EditPasswordEnv
+		p.PasswordFile = d.EditPasswordFile
+		p.Namespace = d.EditNamespace
+	}
+	return p
+}
+
+// saveProfileFromDialog validates the profile being edited, stores it and returns to the picker
+func saveProfileFromDialog(m Model) (Model, tea.Cmd) {
+	d := &m.ConnectionDialog
+	profile := d.Profile()
+	if err := config.ValidateName(m.Profiles.Items, profile.Name, d.ProfileIndex); err != nil {
+		d.Message = err.Error()
+		return m, nil
+	}
+
+	items := append([]config.Profile(nil), m.Profiles.Items...)
+	index := d.ProfileIndex
+	if index >= 0 && index < len(items) {
+		if m.Profiles.Active == items[index].Name {
+			m.Profiles.Active = profile.Name
+		}
+		items[index] = profile
+	} else {
+		items = append(items, profile)
+		index = len(items) - 1
+	}
+	m.Profiles.Items = items
+
+	m.ConnectionDialog = ConnectionDialogState{}
+	m = openProfilePicker(m)
+	m.ProfilePicker.Cursor = index
+	m.ProfilePicker.Message = "Saved " + profile.Name
+	return m, saveProfiles(m.Profiles.Path, m.Profiles.Items)
+}
@@ -0,0 +1,125 @@
+package app
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/camikura/dito/internal/db"
+	"github.com/camikura/dito/internal/ui"
+)
+
+// refreshTickMsg asks for the next automatic refresh of the table list
+type refreshTickMsg struct {
+	Ticker int // RefreshTicker at the time the tick was scheduled
+}
+
+// refreshTables fetches the table list again, showing a footer message
+func refreshTables(m Model) (Model, tea.Cmd) {
+	if m.Connection.NosqlClient == nil {
+		return m, nil
+	}
+	m, cmd := requestTableRefresh(m)
+	m, tick := showFooterMessage(m, "Refreshing tables")
+	return m, tea.Batch(tick, cmd)
+}
+
+// requestTableRefresh fetches the table list and the namespaces again. Tables
+// created or dropped by other tools show up once the list arrives, when the
+// cached schemas are also fetched again (see invalidateTableCaches).
+func requestTableRefresh(m Model) (Model, tea.Cmd) {
+	m.Tables.Refreshing = true
+	return m, tea.Batch(fetchTables(m), db.FetchNamespaces(m.Connection.NosqlClient))
+}
+
+// invalidateTableCaches drops the cached schema, rows and usage of the tables of
+// previous that are no longer listed, and returns a command fetching the cached
+// schemas of the listed tables again since they may have been altered
+func invalidateTableCaches(m Model, previous []string) (Model, tea.Cmd) {
+	for _, tableName := range previous {
+		if m.FindTableIndex(tableName) >= 0 {
+			continue
+		}
+		delete(m.Schema.TableDetails, tableName)
+		delete(m.Schema.Usage, tableName)
+		delete(m.Data.TableData, tableName)
+		if m.Data.MarkedTable == tableName {
+			m = clearRowMarks(m)
+		}
+	}
+
+	// The cached details stay shown until the new ones arrive
+	var cmds []tea.Cmd
+	for _, tableName := range m.Tables.Tables {
+		if _, exists := m.Schema.TableDetails[tableName]; exists {
+			cmds = append(cmds, db.FetchTableDetails(m.Connection.NosqlClient, tableName))
+		}
+	}
+	return m, tea.Batch(cmds...)
+}
+
+// refreshSchema fetches the schema of tableName and its ancestor tables again,
+// and its usage when the Table info section is expanded
+func refreshSchema(m Model, tableName string) (Model, tea.Cmd) {
+	if m.Connection.NosqlClient == nil {
+		return m, nil
+	}
+	var cmds []tea.Cmd
+	for _, name := range append(ui.GetAncestorTableNames(tableName), tableName) {
+		cmds = append(cmds, db.FetchTableDetails(m.Connection.NosqlClient, name))
+	}
+	if m.Schema.InfoExpanded {
+		cmds = append(cmds, fetchTableUsage(m, tableName))
+	}
+	m, tick := showFooterMessage(m, "Refreshing schema of "+tableName)
+	return m, tea.Batch(append(cmds, tick)...)
+}
+
+// refreshTableData fetches the rows of the Data pane again from the first row:
+// the selected table's rows, or the result of the custom SQL query
+func refreshTableData(m Model) (Model, tea.Cmd) {
+	tableName := m.SelectedTableName()
+	if tableName == "" || m.Connection.NosqlClient == nil {
+		return m, nil
+	}
+	if !m.SQL.CustomSQL {
+		cmd := reloadTableData(&m, tableName)
+		m, tick := showFooterMessage(m, "Refreshing rows of "+tableName)
+		return m, tea.Batch(tick, cmd)
+	}
+
+	// Only queries are run again: DML statements leave no rows behind
+	data := m.Data.TableData[tableName]
+	if data == nil || data.CurrentSQL == "" {
+		return m, nil
+	}
+	m.Data.SelectedDataRow = 0
+	m.Data.ViewportOffset = 0
+	m, tick := showFooterMessage(m, "Running the query again")
+	return m, tea.Batch(tick, db.ExecuteCustomSQL(m.Connection.NosqlClient, tableName, data.CurrentSQL, ui.DefaultFetchSize))
+}
+
+// startAutoRefresh starts refreshing the table list every RefreshInterval, if
+// set. The ticker of an earlier connection stops.
+func startAutoRefresh(m Model) (Model, tea.Cmd) {
+	m.Tables.RefreshTicker++
+	if m.Tables.RefreshInterval <= 0 {
+		return m, nil
+	}
+	return m, refreshTick(m.Tables.RefreshInterval, m.Tables.RefreshTicker)
+}
+
+// refreshTick schedules the next table list refresh of ticker
+func refreshTick(interval time.Duration, ticker int) tea.Cmd {
+	return tea.Tick(interval, func(_ time.Time) tea.Msg {
+		return refreshTickMsg{Ticker: ticker}
+	})
+}
+
+func handleRefreshTick(m Model, msg refreshTickMsg) (Model, tea.Cmd) {
+	if msg.Ticker != m.Tables.RefreshTicker || m.Connection.NosqlClient == nil {
+		return m, nil
+	}
+	m, cmd := requestTableRefresh(m)
+	return m, tea.Batch(refreshTick(m.Tables.RefreshInterval, msg.Ticker), cmd)
+}
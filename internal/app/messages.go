@@ -1,6 +1,7 @@
 package app
 
 import (
+	"slices"
 	"sort"
 	"strings"
 
@@ -15,6 +16,7 @@ func handleConnectionResult(m Model, msg db.ConnectionResult) (Model, tea.Cmd) {
 		// Connection failed
 		m.Connection.Connected = false
 		m.Connection.Message = msg.Err.Error()
+		m.Startup = StartupState{}
 		return m, nil
 	}
 
@@ -22,72 +24,99 @@ func handleConnectionResult(m Model, msg db.ConnectionResult) (Model, tea.Cmd) {
 	m.Connection.Connected = true
 	m.Connection.NosqlClient = msg.Client
 	m.Connection.Endpoint = msg.Endpoint
+	m.Connection.Edition = msg.Edition
+	m.Connection.ReadOnly = msg.ReadOnly
+	m.Connection.Namespace = msg.Namespace
 	m.Connection.Message = ""
 
-	// Fetch table list
-	return m, db.FetchTables(msg.Client)
+	// Fetch table list and the namespaces for the namespace selector
+	m, refresh := startAutoRefresh(m)
+	return m, tea.Batch(fetchTables(m), db.FetchNamespaces(msg.Client), refresh)
+}
+
+func handleProfilesSaved(m Model, msg profilesSavedMsg) (Model, tea.Cmd) {
+	if msg.Err != nil {
+		m.ProfilePicker.Message = msg.Err.Error()
+	}
+	return m, nil
 }
 
 func handleTableListResult(m Model, msg db.TableListResult) (Model, tea.Cmd) {
+	refreshing := m.Tables.Refreshing
+	m.Tables.Refreshing = false
 	if msg.Err != nil {
+		if refreshing {
+			return showFooterMessage(m, "Refresh failed: "+msg.Err.Error())
+		}
 		// TODO: Show error
 		return m, nil
 	}
 
+	// Keep the selection and cursor on the same tables when the list is refreshed
+	previous := m.Tables.Tables
+	selected := m.SelectedTableName()
+	cursor, cursorIndex, cursorGroup := m.CursorTableName(), m.Tables.CursorTable, m.Tables.CursorGroup
+
 	// Sort tables for tree display (parents before children)
 	m.Tables.Tables = sortTablesForTree(msg.Tables)
-	if len(m.Tables.Tables) > 0 {
-		m.Tables.CursorTable = 0
-		// SelectedTable stays at -1 until user presses Enter
+	// SelectedTable stays at -1 until user presses Enter
+	m = setTableCursor(m, tableLine{Table: 0})
+	if selected != "" {
+		m.Tables.SelectedTable = m.FindTableIndex(selected)
+	}
+	isHeader := func(l tableLine) bool { return l.Table < 0 && l.Group == cursorGroup }
+	if i := m.FindTableIndex(cursor); i >= 0 {
+		m.Tables.CursorTable = i
+	} else if cursor != "" && cursorIndex < len(m.Tables.Tables) {
+		// The table under the cursor was dropped
+		m.Tables.CursorTable = cursorIndex
+	} else if cursorIndex < 0 && slices.ContainsFunc(tableLines(m), isHeader) {
+		m = setTableCursor(m, tableLine{Group: cursorGroup, Table: -1})
 	}
+	m = revealTableCursor(m)
 
-	return m, nil
+	var refresh tea.Cmd
+	if refreshing {
+		m, refresh = invalidateTableCaches(m, previous)
+	}
+
+	// Select the table requested on the command line
+	m, cmd := applyStartupTable(m)
+	return m, tea.Batch(refresh, cmd)
 }
 
 // sortTablesForTree sorts table names so parent tables appear before their children
-// e.g., ["users.phones", "users", "products", "users.addresses"] ->
+// and tables are grouped by namespace, the default namespace first and system
+// tables last
+// e.g., ["users.phones", "ns1:users", "SYS$Stats", "users", "products", "users.addresses"] ->
 //
-//	["products", "users", "users.addresses", "users.phones"]
+//	["products", "users", "users.addresses", "users.phones", "ns1:users", "SYS$Stats"]
 func sortTablesForTree(tables []string) []string {
 	sorted := make([]string, len(tables))
 	copy(sorted, tables)
 
-	sort.Slice(sorted, func(i, j int) bool {
-		a, b := sorted[i], sorted[j]
-
-		// Get parent names
-		parentA := a
-		if dotIndex := strings.LastIndex(a, "."); dotIndex != -1 {
-			parentA = a[:dotIndex]
-		}
-		parentB := b
-		if dotIndex := strings.LastIndex(b, "."); dotIndex != -1 {
-			parentB = b[:dotIndex]
-		}
-
-		// If one is parent of the other, parent comes first
-		if a == parentB {
-			return true // a is parent of b
-		}
-		if b == parentA {
-			return false // b is parent of a
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sysA, sysB := db.IsSystemTable(sorted[i]), db.IsSystemTable(sorted[j]); sysA != sysB {
+			return sysB
 		}
-
-		// If they have the same parent, sort alphabetically
-		if parentA == parentB {
-			return a < b
+		nsA, a := splitTableNamespace(sorted[i])
+		nsB, b := splitTableNamespace(sorted[j])
+		if nsA != nsB {
+			if nsA == db.DefaultNamespace || nsB == db.DefaultNamespace {
+				return nsA == db.DefaultNamespace
+			}
+			return nsA < nsB
 		}
 
-		// Different parents - sort by parent name, then by full name
-		if parentA != a && parentB != b {
-			// Both are children - compare parents first
-			if parentA != parentB {
-				return parentA < parentB
+		// Compare the names part by part so a parent (a prefix of its
+		// children) comes before its children, and siblings sort by name
+		partsA, partsB := strings.Split(a, "."), strings.Split(b, ".")
+		for k := 0; k < len(partsA) && k < len(partsB); k++ {
+			if partsA[k] != partsB[k] {
+				return partsA[k] < partsB[k]
 			}
 		}
-
-		// One is parent, one is not - sort by full name
-		return a < b
+		return len(partsA) < len(partsB)
 	})
 
 	return sorted
@@ -97,7 +126,7 @@ func handleTableDetailsResult(m Model, msg db.TableDetailsResult) (Model, tea.Cm
 	if msg.Err != nil {
 		m.Schema.ErrorMsg = msg.Err.Error()
 		m.Data.LoadingData = false
-		return m, nil
+		return applyStartupSQL(m), nil
 	}
 
 	// Clear any previous error
@@ -109,8 +138,8 @@ func handleTableDetailsResult(m Model, msg db.TableDetailsResult) (Model, tea.Cm
 		tableName := m.Tables.Tables[m.Tables.SelectedTable]
 		if tableName == msg.TableName && msg.Schema != nil {
 			// Update SQL with ORDER BY
-			primaryKeys := ui.ParsePrimaryKeysFromDDL(msg.Schema.DDL)
-			m.SQL.CurrentSQL = buildDefaultSQL(tableName, msg.Schema.DDL)
+			primaryKeys := msg.Table().PrimaryKey
+			m.SQL.CurrentSQL = buildDefaultSQL(tableName, m.Connection.Namespace, primaryKeys)
 			m.SQL.CursorPos = ui.RuneLen(m.SQL.CurrentSQL)
 			// Now fetch data with proper ORDER BY
 			return m, db.FetchTableData(m.Connection.NosqlClient, tableName, ui.DefaultFetchSize, primaryKeys)
@@ -124,7 +153,7 @@ func handleTableDataResult(m Model, msg db.TableDataResult) (Model, tea.Cmd) {
 	if msg.Err != nil {
 		m.Data.LoadingData = false
 		m.Data.ErrorMsg = msg.Err.Error()
-		return m, nil
+		return applyStartupSQL(m), nil
 	}
 
 	// Clear any previous error
@@ -143,6 +172,11 @@ func handleTableDataResult(m Model, msg db.TableDataResult) (Model, tea.Cmd) {
 			// and new data appears below in the previously empty space
 		}
 	} else {
+		m.Data.Statement = nil
+		// Marks refer to the replaced rows
+		if m.Data.MarkedTable == msg.TableName {
+			m = clearRowMarks(m)
+		}
 		// Store new data
 		m.Data.TableData[msg.TableName] = &db.TableDataResult{
 			TableName:    msg.TableName,
@@ -157,5 +191,24 @@ func handleTableDataResult(m Model, msg db.TableDataResult) (Model, tea.Cmd) {
 	}
 
 	m.Data.LoadingData = false
+	return applyStartupSQL(m), nil
+}
+
+func handleStatementResult(m Model, msg db.StatementResult) (Model, tea.Cmd) {
+	m.Data.Running = false
+	if msg.Err != nil {
+		m.Data.ErrorMsg = msg.Err.Error()
+		return m, nil
+	}
+
+	m.Data.ErrorMsg = ""
+	m.Data.Statement = &msg
+	// The loaded rows may no longer match the table
+	if m.Data.MarkedTable == msg.TableName {
+		m = clearRowMarks(m)
+	}
+	delete(m.Data.TableData, msg.TableName)
+	m.Data.SelectedDataRow = 0
+	m.Data.ViewportOffset = 0
 	return m, nil
 }
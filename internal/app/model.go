@@ -1,11 +1,16 @@
 package app
 
 import (
-	"strings"
+	"time"
 
 	"github.com/oracle/nosql-go-sdk/nosqldb"
+	"github.com/oracle/nosql-go-sdk/nosqldb/types"
 
+	"github.com/camikura/dito/internal/config"
 	"github.com/camikura/dito/internal/db"
+	"github.com/camikura/dito/internal/importer"
+	"github.com/camikura/dito/internal/output"
+	"github.com/camikura/dito/internal/ui"
 )
 
 // FocusPane represents which pane is currently focused
@@ -34,6 +39,9 @@ type WindowState struct {
 // ConnectionState holds connection-related state
 type ConnectionState struct {
 	Endpoint    string
+	Edition     db.Edition // Edition of the configured connection
+	ReadOnly    bool       // Whether statements and actions that change data or schema are refused
+	Namespace   string     // Default namespace of the connection (on-premise only)
 	Connected   bool
 	Message     string // Connection status message
 	NosqlClient *nosqldb.Client
@@ -41,10 +49,23 @@ type ConnectionState struct {
 
 // TablesState holds tables pane state
 type TablesState struct {
-	Tables        []string
-	SelectedTable int // Index of selected table (marked with *)
-	CursorTable   int // Index of table under cursor
-	ScrollOffset  int // Scroll offset for tables pane
+	Tables          []string
+	SelectedTable   int               // Index of selected table (marked with *)
+	CursorTable     int               // Index of table under cursor, -1 on a group header
+	CursorGroup     string            // Group whose header is under the cursor when CursorTable is -1
+	ScrollOffset    int               // Scroll offset for tables pane
+	Operations      map[string]string // Table name -> state of its DDL operation, e.g. "CREATING"
+	Namespace       string            // Namespace the list is filtered to, "" for the connection's default scope
+	Namespaces      []string          // Namespaces of the store, nil until fetched
+	NamespaceErr    error             // Why the namespaces could not be fetched (e.g. on cloud services)
+	ShowSystem      bool              // Whether system tables (SYS$*) are listed
+	CollapsedGroups map[string]bool   // Group (namespace or system tables) -> collapsed
+	Collapsed       map[string]bool   // Parent table name -> its child tables are collapsed
+	Filter          string            // Fuzzy filter of the table list ("" shows all tables)
+	Filtering       bool              // Whether keys edit the filter
+	Refreshing      bool              // Whether the list being fetched refreshes the cached schemas and rows
+	RefreshInterval time.Duration     // Interval of the automatic refresh, 0 for off
+	RefreshTicker   int               // Identifies the current refresh ticker; stale ticks are ignored
 }
 
 // SchemaState holds schema pane state
@@ -53,6 +74,12 @@ type SchemaState struct {
 	LoadingDetails bool
 	ErrorMsg       string // Error message from schema fetch
 	ScrollOffset   int    // Scroll offset for schema pane
+	Cursor         int    // Column tree node or index under cursor (nodes first, then indexes)
+	Expanded       map[schemaNodeKey]bool
+	InfoExpanded   bool                            // Whether the Table info section is expanded
+	Usage          map[string]*db.TableUsageResult // Latest usage record per table
+	UsageRefresh   bool                            // Whether usage is refreshed periodically
+	UsageTicker    int                             // Identifies the current refresh ticker; stale ticks are ignored
 }
 
 // SQLState holds SQL pane state
@@ -73,15 +100,79 @@ type DataState struct {
 	SelectedDataRow  int
 	ViewportOffset   int
 	HorizontalOffset int
+	MarkedTable      string              // Table whose rows are marked
+	Marked           map[int]bool        // Row indexes marked for deletion
+	Undo             []DeletedRows       // Deleted rows that can be restored, most recent last
+	Restoring        bool                // Whether deleted rows are being written back
+	Running          bool                // Whether a DML statement is running
+	Statement        *db.StatementResult // Result of the last DML statement, shown instead of the grid
+}
+
+// DeletedRows is a batch of deleted rows kept for undo during the session
+type DeletedRows struct {
+	TableName string
+	Rows      []*types.MapValue
 }
 
 // ConnectionDialogState holds connection setup dialog state
 type ConnectionDialogState struct {
-	Visible      bool
-	Field        int    // 0: Endpoint, 1: Port, 2: Connect button
-	EditEndpoint string // Endpoint being edited
-	EditPort     string // Port being edited
-	EditCursorPos int   // Cursor position in current field
+	Visible       bool
+	Edition       db.Edition // Selected edition (empty means On-Premise)
+	Field         int        // Index into Fields()
+	EditEndpoint  string     // Endpoint being edited
+	EditPort      string     // Port being edited
+	EditCursorPos int        // Cursor position in current field
+
+	// Secure on-premise fields
+	UseHTTPS     bool   // Connect over HTTPS
+	EditCertPath string // CA bundle path being edited
+	SkipVerify   bool   // Skip server certificate verification
+	EditUsername string // KVStore username being edited
+	EditPassword string // KVStore password being edited (masked in the dialog)
+
+	// Cloud Service fields
+	EditOCIConfigFile string // OCI config file path being edited
+	EditOCIProfile    string // OCI config profile being edited
+	EditRegion        string // Region being edited
+	EditCompartment   string // Compartment OCID or path being edited
+
+	// Cloud Simulator fields
+	EditTenantID string // Tenant ID being edited
+
+	EditNamespace string // Default namespace being edited (on-premise only)
+	ReadOnly      bool   // Refuse changes on this connection
+
+	// Saved profile editing (opened from the profile picker)
+	ProfileMode      bool   // Dialog edits a saved profile instead of connecting
+	ProfileIndex     int    // Index of the profile being edited (-1 for a new profile)
+	EditProfileName  string // Profile name being edited
+	EditPasswordEnv  string // Environment variable holding the password
+	EditPasswordFile string // File containing the password
+	Message          string // Validation error shown in the dialog
+}
+
+// ProfilesState holds saved connection profiles
+type ProfilesState struct {
+	Path   string           // Profiles file path (empty disables saving)
+	Items  []config.Profile // Saved profiles
+	Active string           // Name of the profile used for the current connection
+}
+
+// ProfilePickerState holds profile picker dialog state
+type ProfilePickerState struct {
+	Visible       bool
+	Cursor        int    // Index of the profile under cursor
+	Renaming      bool   // Whether the profile under cursor is being renamed
+	EditName      string // New name being edited
+	EditCursorPos int    // Cursor position in the name being edited
+	ConfirmDelete bool   // Whether deletion of the profile under cursor is pending
+	Message       string // Error or status message
+}
+
+// StartupState holds actions requested on the command line that run once connected
+type StartupState struct {
+	Table string // Table to select when the table list arrives
+	SQL   string // SQL to prefill in the SQL pane once the initial data is shown
 }
 
 // RecordDetailDialogState holds record detail dialog state
@@ -90,6 +181,100 @@ type RecordDetailDialogState struct {
 	ScrollOffset int
 }
 
+// RowEditorField is one column in the row editor
+type RowEditorField struct {
+	Column   importer.Column
+	Text     string      // Value being edited
+	Null     bool        // Whether the value is set to null
+	Original interface{} // Value read from the table (nil for null)
+}
+
+// RowEditorState holds row editor dialog state
+type RowEditorState struct {
+	Visible     bool
+	Insert      bool // New row form: primary key columns are editable and the row is written only if absent
+	TableName   string
+	RowIndex    int    // Row being edited in the Data pane
+	FocusColumn string // Column to focus once the row is loaded
+	Loading     bool   // Reading the current row and its version
+	Saving      bool   // Writing the row
+	Schema      *importer.Schema
+	Fields      []RowEditorField
+	Field       int           // Focused field
+	CursorPos   int           // Cursor position in the focused field
+	Version     types.Version // Version of the row when it was read
+	Message     string        // Validation or error message
+	Conflict    bool          // Whether the last save failed because the row changed
+}
+
+// DeleteDialogState holds row deletion confirmation dialog state
+type DeleteDialogState struct {
+	Visible      bool
+	TableName    string
+	Rows         []int             // Data pane row indexes to delete
+	Keys         []*types.MapValue // Primary keys of the rows
+	Labels       []string          // Primary keys as shown, e.g. "id=1"
+	ScrollOffset int               // Scroll offset of the key list
+	Deleting     bool              // Deleting the rows
+}
+
+// TableDesignerState holds table designer dialog state
+type TableDesignerState struct {
+	Visible   bool
+	Design    ui.TableDesign
+	Row       int // Focused row: table name, TTL, then one row per column
+	Cell      int // Focused input of the row
+	CursorPos int // Cursor position in the focused text input
+}
+
+// IndexDialogState holds create index dialog state
+type IndexDialogState struct {
+	Visible   bool
+	Design    ui.IndexDesign
+	Row       int // Focused row: index name, then one row per field
+	Cell      int // Focused input of a field row: 0: path, 1: type
+	CursorPos int // Cursor position in the focused text input
+}
+
+// IndexDetailState holds index inspection and drop confirmation dialog state
+type IndexDetailState struct {
+	Visible     bool
+	TableName   string
+	Index       nosqldb.IndexInfo
+	ConfirmDrop bool // Whether the drop confirmation is shown
+}
+
+// ExportDialogState holds export dialog state
+type ExportDialogState struct {
+	Visible          bool
+	Field            int           // 0: file path, 1: format
+	EditPath         string        // Destination file path being edited
+	EditCursorPos    int           // Cursor position in the path
+	Format           output.Format // Selected file format
+	Message          string        // Validation or error message
+	ConfirmOverwrite bool          // Whether the file exists and Enter overwrites it
+	Job              *exportJob    // Export in progress (nil when idle)
+	Rows             int           // Rows written so far
+	Cancelling       bool          // Whether cancellation was requested
+}
+
+// ImportDialogState holds import dialog state
+type ImportDialogState struct {
+	Visible          bool
+	TableName        string         // Table to import into
+	Field            int            // 0: file path, 1: mode, 2: rejects path
+	EditPath         string         // Input file path being edited
+	EditCursorPos    int            // Cursor position in the path
+	Mode             importer.Mode  // Selected write mode
+	EditRejects      string         // Rejects file path being edited
+	RejectsCursorPos int            // Cursor position in the rejects path
+	RejectsEdited    bool           // Whether the rejects path was edited (otherwise it follows the file path)
+	Message          string         // Validation or error message
+	Job              *importJob     // Import in progress (nil when idle)
+	Stats            importer.Stats // Records processed so far
+	Cancelling       bool           // Whether cancellation was requested
+}
+
 // UIState holds temporary UI state (messages, confirmations)
 type UIState struct {
 	CopyMessage      string // Temporary message shown after copy operation
@@ -105,8 +290,18 @@ type Model struct {
 	SQL              SQLState
 	Data             DataState
 	ConnectionDialog ConnectionDialogState
+	Profiles         ProfilesState
+	ProfilePicker    ProfilePickerState
 	RecordDetail     RecordDetailDialogState
+	RowEditor        RowEditorState
+	DeleteDialog     DeleteDialogState
+	TableDesigner    TableDesignerState
+	IndexDialog      IndexDialogState
+	IndexDetail      IndexDetailState
+	ExportDialog     ExportDialogState
+	ImportDialog     ImportDialogState
 	UI               UIState
+	Startup          StartupState
 
 	// Focus management
 	CurrentPane FocusPane
@@ -123,6 +318,8 @@ func InitialModel() Model {
 		},
 		Schema: SchemaState{
 			TableDetails: make(map[string]*db.TableDetailsResult),
+			Expanded:     make(map[schemaNodeKey]bool),
+			Usage:        make(map[string]*db.TableUsageResult),
 		},
 		SQL: SQLState{
 			PreviousSelectedTable: -1,
@@ -169,30 +366,30 @@ func (m Model) PrevPane() Model {
 	return m
 }
 
-// FindTableName finds the actual table name from the tables list using case-insensitive matching.
+// FindTableName finds the actual table name from the tables list using case-insensitive matching
+// (see sameTableName).
 // Returns the matched table name from the list, or empty string if not found.
 func (m Model) FindTableName(name string) string {
 	if name == "" {
 		return ""
 	}
-	nameLower := strings.ToLower(name)
 	for _, t := range m.Tables.Tables {
-		if strings.ToLower(t) == nameLower {
+		if sameTableName(t, name) {
 			return t
 		}
 	}
 	return ""
 }
 
-// FindTableIndex finds the index of a table name in the tables list using case-insensitive matching.
+// FindTableIndex finds the index of a table name in the tables list using case-insensitive matching
+// (see sameTableName).
 // Returns the index, or -1 if not found.
 func (m Model) FindTableIndex(name string) int {
 	if name == "" {
 		return -1
 	}
-	nameLower := strings.ToLower(name)
 	for i, t := range m.Tables.Tables {
-		if strings.ToLower(t) == nameLower {
+		if sameTableName(t, name) {
 			return i
 		}
 	}
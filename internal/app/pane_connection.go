@@ -3,9 +3,14 @@ package app
 import (
 	"strings"
 
+	"github.com/charmbracelet/lipgloss"
+
 	"github.com/camikura/dito/internal/ui"
 )
 
+// readOnlyBadge is shown in the Connection pane title for read-only connections
+const readOnlyBadge = " READ-ONLY "
+
 func renderConnectionPane(m Model, width int) string {
 	borderStyle := ui.StyleBorderInactive
 	titleStyle := ui.StyleTitleInactive
@@ -14,15 +19,30 @@ func renderConnectionPane(m Model, width int) string {
 		titleStyle = ui.StyleTitleActive
 	}
 
+	// Show the active profile name in the title, e.g. " Connection: prod "
+	titleLabel := " Connection "
+	readOnly := m.Connection.Connected && m.Connection.ReadOnly
+	if m.Profiles.Active != "" {
+		nameWidth := width - 20
+		if readOnly {
+			nameWidth -= len(readOnlyBadge) + 1
+		}
+		titleLabel = " Connection: " + ui.TruncateString(m.Profiles.Active, nameWidth) + " "
+	}
+
 	var titleText string
 	var titleDisplayWidth int
 	if m.Connection.Connected {
 		checkmark := ui.StyleCheckmark.Render("✓")
-		titleText = titleStyle.Render(" Connection ") + checkmark + " "
-		titleDisplayWidth = 14
+		titleText = titleStyle.Render(titleLabel) + checkmark + " "
+		titleDisplayWidth = lipgloss.Width(titleLabel) + 2
+		if readOnly {
+			titleText += ui.StyleReadOnly.Render(readOnlyBadge) + " "
+			titleDisplayWidth += lipgloss.Width(readOnlyBadge) + 1
+		}
 	} else {
-		titleText = titleStyle.Render(" Connection ")
-		titleDisplayWidth = 12
+		titleText = titleStyle.Render(titleLabel)
+		titleDisplayWidth = lipgloss.Width(titleLabel)
 	}
 
 	// Title line: ╭─ + titleText + ─...─ + ╮
@@ -42,6 +62,13 @@ func renderConnectionPane(m Model, width int) string {
 		}
 	} else if m.Connection.Endpoint != "" {
 		content = m.Connection.Endpoint
+		// Label the active edition, e.g. "Cloud Simulator: localhost:8080"
+		if m.Connection.Edition != "" {
+			content = m.Connection.Edition.Label() + ": " + content
+		}
+		if len(content) > width-2 {
+			content = content[:width-5] + "..."
+		}
 	}
 
 	// Pad content to width (no left/right padding)
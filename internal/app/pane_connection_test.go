@@ -3,6 +3,10 @@ package app
 import (
 	"strings"
 	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/camikura/dito/internal/db"
 )
 
 func TestRenderConnectionPane(t *testing.T) {
@@ -33,6 +37,67 @@ func TestRenderConnectionPane(t *testing.T) {
 		}
 	})
 
+	t.Run("labels the active edition", func(t *testing.T) {
+		m := InitialModel()
+		m.Connection.Connected = true
+		m.Connection.Endpoint = "localhost:8080"
+		m.Connection.Edition = db.EditionCloudSim
+
+		result := renderConnectionPane(m, 40)
+
+		if !strings.Contains(result, "Cloud Simulator: localhost:8080") {
+			t.Error("Expected edition label in output")
+		}
+	})
+
+	t.Run("truncates long edition label", func(t *testing.T) {
+		m := InitialModel()
+		m.Connection.Connected = true
+		m.Connection.Endpoint = "nosql.us-ashburn-1.oci.oraclecloud.com"
+		m.Connection.Edition = db.EditionCloud
+
+		result := renderConnectionPane(m, 30)
+
+		if !strings.Contains(result, "...") {
+			t.Error("Expected truncated endpoint with ellipsis")
+		}
+	})
+
+	t.Run("shows active profile in title", func(t *testing.T) {
+		m := InitialModel()
+		m.Connection.Connected = true
+		m.Connection.Endpoint = "localhost:8080"
+		m.Profiles.Active = "local"
+
+		result := renderConnectionPane(m, 40)
+		title := strings.Split(result, "\n")[0]
+
+		if !strings.Contains(title, "Connection: local") {
+			t.Errorf("Expected profile name in title, got %q", title)
+		}
+		if w := lipgloss.Width(title); w != 40 {
+			t.Errorf("title width = %d, want 40", w)
+		}
+	})
+
+	t.Run("shows read-only badge", func(t *testing.T) {
+		m := InitialModel()
+		m.Connection.Connected = true
+		m.Connection.ReadOnly = true
+		m.Connection.Endpoint = "prod.example.com:8080"
+		m.Profiles.Active = "production-cluster-eu-west"
+
+		result := renderConnectionPane(m, 50)
+		title := strings.Split(result, "\n")[0]
+
+		if !strings.Contains(title, "READ-ONLY") {
+			t.Errorf("Expected read-only badge in title, got %q", title)
+		}
+		if w := lipgloss.Width(title); w != 50 {
+			t.Errorf("title width = %d, want 50", w)
+		}
+	})
+
 	t.Run("shows error message when present", func(t *testing.T) {
 		m := InitialModel()
 		m.Connection.Message = "Connection failed"
@@ -122,6 +122,9 @@ func renderDataPane(m Model, width int, totalHeight int) string {
 				}
 				result.WriteString(leftBorder + styledLine + strings.Repeat(" ", paddingLen) + rightBorder + "\n")
 			}
+		} else if m.Data.Running || m.Data.Statement != nil {
+			// DML statements show their outcome instead of rows
+			result.WriteString(renderStatementStatus(m, width, contentLines, leftBorder, rightBorder))
 		} else if !exists || data == nil {
 			// No data loaded yet
 			message := "No data"
@@ -172,6 +175,61 @@ func renderDataPane(m Model, width int, totalHeight int) string {
 	return result.String()
 }
 
+// statementVerbs describe what a DML statement did to the affected rows
+var statementVerbs = map[string]string{
+	"INSERT": "inserted",
+	"UPSERT": "upserted",
+	"UPDATE": "updated",
+	"DELETE": "deleted",
+}
+
+// statementSummary describes the outcome of a DML statement, e.g. "UPDATE: 3 rows updated"
+func statementSummary(res db.StatementResult) string {
+	noun := "rows"
+	if res.Affected == 1 {
+		noun = "row"
+	}
+	verb, ok := statementVerbs[res.Keyword]
+	if !ok {
+		verb = "affected"
+	}
+	return fmt.Sprintf("%s: %d %s %s", res.Keyword, res.Affected, noun, verb)
+}
+
+// renderStatementStatus renders the status area of a running or finished DML statement:
+// the summary followed by the executed statement
+func renderStatementStatus(m Model, width int, contentLines int, leftBorder string, rightBorder string) string {
+	contentWidth := width - 2
+	var lines, styled []string
+	if m.Data.Running {
+		lines = append(lines, "Running...")
+		styled = append(styled, ui.StyleGrayText.Render("Running..."))
+	} else {
+		summary := ui.TruncateString(statementSummary(*m.Data.Statement), contentWidth)
+		lines = append(lines, summary, "")
+		styled = append(styled, ui.StyleSuccess.Render(summary), "")
+		for _, sqlLine := range strings.Split(m.Data.Statement.SQL, "\n") {
+			sqlLine = ui.TruncateString(strings.TrimRight(sqlLine, " \t"), contentWidth)
+			lines = append(lines, sqlLine)
+			styled = append(styled, ui.StyleGrayText.Render(sqlLine))
+		}
+	}
+
+	var result strings.Builder
+	for i := 0; i < contentLines; i++ {
+		line, styledLine := "", ""
+		if i < len(lines) {
+			line, styledLine = lines[i], styled[i]
+		}
+		paddingLen := contentWidth - ui.RuneLen(line)
+		if paddingLen < 0 {
+			paddingLen = 0
+		}
+		result.WriteString(leftBorder + styledLine + strings.Repeat(" ", paddingLen) + rightBorder + "\n")
+	}
+	return result.String()
+}
+
 // scrollInfo holds scroll-related information for the grid
 type scrollInfo struct {
 	totalWidth     int
@@ -213,6 +271,7 @@ func renderGridViewWithScrollInfo(m Model, tableName string, data *db.TableDataR
 	grid.HorizontalOffset = m.Data.HorizontalOffset
 	grid.VerticalOffset = m.Data.ViewportOffset
 	grid.SelectedRow = m.Data.SelectedDataRow
+	grid.Marked = markedRowSet(m)
 	grid.ShowLoading = m.Data.LoadingData
 	grid.HasMore = data.HasMore
 	grid.IsFocused = m.CurrentPane == FocusPaneData
@@ -260,11 +319,5 @@ func renderGridViewWithScrollInfo(m Model, tableName string, data *db.TableDataR
 
 // getColumnTypes extracts column types from schema information
 func getColumnTypes(m Model, tableName string, columns []string) map[string]string {
-	// Get DDL from table details
-	var ddl string
-	if details, exists := m.Schema.TableDetails[tableName]; exists && details != nil && details.Schema != nil {
-		ddl = details.Schema.DDL
-	}
-
-	return ui.GetColumnTypes(ddl)
+	return ui.ColumnTypes(tableSchema(m, tableName))
 }
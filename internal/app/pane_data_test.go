@@ -63,6 +63,24 @@ func TestRenderDataPane(t *testing.T) {
 		}
 	})
 
+	t.Run("shows DML statement result", func(t *testing.T) {
+		m := InitialModel()
+		m.Tables.Tables = []string{"users"}
+		m.Tables.SelectedTable = 0
+		m.Data.Statement = &db.StatementResult{TableName: "users", SQL: "UPDATE users\nSET age = 1", Keyword: "UPDATE", Affected: 3}
+
+		result := renderDataPane(m, 60, 20)
+
+		if !strings.Contains(result, "UPDATE: 3 rows updated") || !strings.Contains(result, "SET age = 1") {
+			t.Errorf("Expected statement summary in output:\n%s", result)
+		}
+		for _, line := range strings.Split(result, "\n") {
+			if w := lipgloss.Width(line); w != 60 {
+				t.Fatalf("line width = %d, want 60: %q", w, line)
+			}
+		}
+	})
+
 	t.Run("shows table name in title", func(t *testing.T) {
 		m := InitialModel()
 		m.Tables.Tables = []string{"users"}
@@ -5,6 +5,9 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/camikura/dito/internal/db"
 	"github.com/camikura/dito/internal/ui"
 )
 
@@ -17,7 +20,21 @@ func renderTablesPaneWithHeight(m Model, width int, height int) string {
 	}
 
 	titleText := " Tables"
-	if len(m.Tables.Tables) > 0 {
+	if m.Tables.Namespace != "" {
+		titleText += " [" + m.Tables.Namespace + "]"
+	}
+	if m.Tables.Filter != "" || m.Tables.Filtering {
+		titleText += " /" + m.Tables.Filter
+	}
+	if shown := filteredTables(m); shown != nil {
+		matched := 0
+		for _, positions := range shown {
+			if positions != nil {
+				matched++
+			}
+		}
+		titleText += fmt.Sprintf(" (%d/%d)", matched, len(m.Tables.Tables))
+	} else if len(m.Tables.Tables) > 0 {
 		titleText += fmt.Sprintf(" (%d)", len(m.Tables.Tables))
 	}
 	titleText += " "
@@ -32,8 +49,11 @@ func renderTablesPaneWithHeight(m Model, width int, height int) string {
 	// Prepare content lines with tree structure
 	type tableLineInfo struct {
 		text       string
-		isSelected bool // * marker (Enter pressed)
-		isCursor   bool // cursor position (up/down navigation)
+		state      string // state of a running DDL operation
+		isSelected bool   // * marker (Enter pressed)
+		isCursor   bool   // cursor position (up/down navigation)
+		header     bool   // group header (namespace or system tables)
+		matches    []int  // rune positions of text matched by the filter
 	}
 
 	// Determine if selection marker should be shown
@@ -55,15 +75,31 @@ func renderTablesPaneWithHeight(m Model, width int, height int) string {
 		// Calculate available width for table name (excluding borders)
 		availableWidth := width - 2 // -2 for left and right borders
 
-		for i, tableName := range m.Tables.Tables {
-			// Determine indentation based on nesting level (count of '.' separators)
-			nestLevel := strings.Count(tableName, ".")
-			indent := strings.Repeat(" ", nestLevel)
-			displayName := tableName
-			if dotIndex := strings.LastIndex(tableName, "."); dotIndex != -1 {
-				// Child table - show only the last part of the name
-				displayName = tableName[dotIndex+1:]
+		lines := tableLines(m)
+		if len(lines) == 0 {
+			contentLines = []tableLineInfo{{text: "No matching tables"}}
+		}
+		for _, tl := range lines {
+			if tl.Table < 0 {
+				// Group header, aligned with the tree markers of top-level tables
+				contentLines = append(contentLines, tableLineInfo{
+					text:     ui.TruncateString("  "+tableTreeMarker(tl)+tableGroupLabel(tl.Group)+collapsedCount(tl), availableWidth),
+					isCursor: m.Tables.CursorTable < 0 && tl.Group == m.Tables.CursorGroup,
+					header:   true,
+				})
+				continue
 			}
+			i, tableName := tl.Table, m.Tables.Tables[tl.Table]
+
+			// Indent by tree depth; child tables show only the last part of their name
+			indent := strings.Repeat("  ", tl.Depth)
+			_, displayName := db.SplitTableName(tableName)
+			if dotIndex := strings.LastIndex(displayName, "."); dotIndex != -1 {
+				displayName = displayName[dotIndex+1:]
+			}
+			// Matches in the shown part of the name, which ends the full name
+			nameOffset := ui.RuneLen(tableName) - ui.RuneLen(displayName)
+			displayName = tableTreeMarker(tl) + displayName + collapsedCount(tl)
 
 			// Add selection marker (* for selected table via Enter)
 			var prefix string
@@ -74,18 +110,33 @@ func renderTablesPaneWithHeight(m Model, width int, height int) string {
 				prefix = "  "
 			}
 
-			// Truncate if too long
+			// Truncate if too long (keeping room for the operation state)
 			fullText := prefix + indent + displayName
+			state := tableOperation(m, tableName)
 			maxTextWidth := availableWidth
-			if ui.RuneLen(fullText) > maxTextWidth {
+			if state != "" {
+				maxTextWidth -= ui.RuneLen(state) + 1
+			}
+			visibleLen := ui.RuneLen(fullText)
+			if visibleLen > maxTextWidth {
 				// Truncate with ellipsis
 				fullText = ui.TruncateString(fullText, maxTextWidth)
+				visibleLen = ui.RuneLen(fullText) - 1
+			}
+			var matches []int
+			nameStart := ui.RuneLen(prefix+indent) + ui.RuneLen(tableTreeMarker(tl))
+			for _, p := range tl.Matches {
+				if pos := nameStart + p - nameOffset; p >= nameOffset && pos < visibleLen {
+					matches = append(matches, pos)
+				}
 			}
 
 			contentLines = append(contentLines, tableLineInfo{
 				text:       fullText,
+				state:      state,
 				isSelected: isSelected,
 				isCursor:   i == m.Tables.CursorTable,
+				matches:    matches,
 			})
 		}
 	}
@@ -107,16 +158,21 @@ func renderTablesPaneWithHeight(m Model, width int, height int) string {
 		if contentIndex < len(contentLines) {
 			lineInfo := contentLines[contentIndex]
 			// Apply color based on state
-			var styledText string
+			style := ui.StyleTableNormal
 			if isFocused && lineInfo.isCursor {
-				styledText = ui.StyleTableCursor.Render(lineInfo.text)
+				style = ui.StyleTableCursor
+			} else if lineInfo.header {
+				style = ui.StyleSchemaLabel
 			} else if lineInfo.isSelected {
-				styledText = ui.StyleTableSelected.Render(lineInfo.text)
-			} else {
-				styledText = ui.StyleTableNormal.Render(lineInfo.text)
+				style = ui.StyleTableSelected
 			}
+			styledText := renderMatches(lineInfo.text, lineInfo.matches, style)
 			// Calculate padding (based on rune length for correct display width)
 			paddingLen := width - ui.RuneLen(lineInfo.text) - 2
+			if lineInfo.state != "" {
+				paddingLen -= ui.RuneLen(lineInfo.state) + 1
+				styledText += " " + ui.StyleTableState.Render(lineInfo.state)
+			}
 			if paddingLen < 0 {
 				paddingLen = 0
 			}
@@ -137,6 +193,16 @@ func renderTablesPaneWithHeight(m Model, width int, height int) string {
 	return result.String()
 }
 
+// tableOperation returns the state of a running DDL operation on tableName, or ""
+func tableOperation(m Model, tableName string) string {
+	for name, state := range m.Tables.Operations {
+		if strings.EqualFold(name, tableName) {
+			return state
+		}
+	}
+	return ""
+}
+
 func renderSchemaPaneWithHeight(m Model, width int, height int) string {
 	// Determine which table to show schema for
 	// Use SelectedTable, or extract from custom SQL if applicable
@@ -188,6 +254,7 @@ func renderSchemaPaneWithHeight(m Model, width int, height int) string {
 	// Prepare content lines
 	var contentLines []string
 	var schemaError string
+	cursor := -1 // Position of the highlighted column tree node or index
 	if m.Schema.ErrorMsg != "" {
 		schemaError = m.Schema.ErrorMsg
 	}
@@ -209,68 +276,62 @@ func renderSchemaPaneWithHeight(m Model, width int, height int) string {
 			// Render schema information
 			contentLines = append(contentLines, "Columns:")
 
-			// Collect all columns including inherited from ancestors
-			var allColumns []ui.ColumnInfo
-
-			// Get ancestor table names (root to immediate parent)
-			ancestors := ui.GetAncestorTableNames(schemaTableName)
-
-			// Add inherited primary key columns from ancestors
-			for _, ancestorName := range ancestors {
-				ancestorDetails, ancestorExists := m.Schema.TableDetails[ancestorName]
-				if ancestorExists && ancestorDetails != nil && ancestorDetails.Schema != nil && ancestorDetails.Schema.DDL != "" {
-					ancestorPKs := ui.ParsePrimaryKeysFromDDL(ancestorDetails.Schema.DDL)
-					ancestorCols := ui.ParseColumnsFromDDL(ancestorDetails.Schema.DDL, ancestorPKs)
-					// Only add primary key columns from ancestors
-					for _, col := range ancestorCols {
-						if col.IsPrimaryKey {
-							col.IsInherited = true
-							allColumns = append(allColumns, col)
-						}
-					}
-				}
-			}
-
-			// Add this table's own columns
-			if details.Schema.DDL != "" {
-				primaryKeys := ui.ParsePrimaryKeysFromDDL(details.Schema.DDL)
-				columns := ui.ParseColumnsFromDDL(details.Schema.DDL, primaryKeys)
-				allColumns = append(allColumns, columns...)
+			// Columns (with the inherited key columns first) and the expanded
+			// RECORD fields and ARRAY/MAP elements below them
+			nodes := schemaTreeNodes(m, schemaTableName)
+			if m.CurrentPane == FocusPaneSchema {
+				_, _, cursor = schemaItems(m, schemaTableName)
 			}
 
-			// Find the longest column name
+			// Find the longest column name, including the indentation of nested nodes
 			maxColNameLen := 0
-			for _, col := range allColumns {
-				if len(col.Name) > maxColNameLen {
-					maxColNameLen = len(col.Name)
-				}
+			for _, node := range nodes {
+				maxColNameLen = max(maxColNameLen, 2*node.Depth+len(node.Name))
 			}
 
-			// Format each column: PK|||Name|||Type|||maxLen|||IsInherited (use ||| as separator)
-			for _, col := range allColumns {
+			// Format each node: PK|||Name|||Type|||maxLen|||IsInherited|||Attributes|||Depth|||Marker|||Position (use ||| as separator)
+			for i, node := range nodes {
 				pkMarker := " " // Single space when not PK
-				if col.IsPrimaryKey {
+				if node.PrimaryKey {
 					pkMarker = "P" // Single "P" for primary key
 				}
 				inherited := ""
-				if col.IsInherited {
-					inherited = "|||inherited"
+				if node.Inherited {
+					inherited = "inherited"
+				}
+				marker := ""
+				switch {
+				case node.Expanded:
+					marker = "▾"
+				case node.Expandable():
+					marker = "▸"
 				}
-				contentLines = append(contentLines, fmt.Sprintf("%s|||%s|||%s|||%d%s", pkMarker, col.Name, col.Type, maxColNameLen, inherited))
+				contentLines = append(contentLines, fmt.Sprintf("%s|||%s|||%s|||%d|||%s|||%s|||%d|||%s|||%d",
+					pkMarker, node.Name, node.TypeText(), maxColNameLen, inherited, strings.Join(node.Attributes, " "), node.Depth, marker, i))
 			}
 
 			// Add indexes section
 			contentLines = append(contentLines, "")
 			contentLines = append(contentLines, "Indexes:")
 			if len(details.Indexes) > 0 {
-				for _, index := range details.Indexes {
+				for i, index := range details.Indexes {
 					fields := strings.Join(index.FieldNames, ", ")
-					// Format: IndexName|||Fields (use ||| as separator to apply color in rendering)
-					contentLines = append(contentLines, fmt.Sprintf("IDX|||%s|||%s", index.IndexName, fields))
+					// Format: IDX|||Position|||IndexName|||Fields (use ||| as separator to apply color in rendering)
+					contentLines = append(contentLines, fmt.Sprintf("IDX|||%d|||%s|||%s", len(nodes)+i, index.IndexName, fields))
 				}
 			} else {
 				contentLines = append(contentLines, "  (none)")
 			}
+
+			// Add table info section
+			contentLines = append(contentLines, "")
+			contentLines = append(contentLines, "Table info:")
+			if m.Schema.InfoExpanded {
+				for _, info := range tableInfoLines(m, schemaTableName) {
+					// Format: INFO|||Label|||Value
+					contentLines = append(contentLines, "INFO|||"+info[0]+"|||"+info[1])
+				}
+			}
 		}
 	}
 
@@ -297,12 +358,33 @@ func renderSchemaPaneWithHeight(m Model, width int, height int) string {
 					paddingLen = 0
 				}
 				line = ui.StyleSchemaLabel.Render(content) + strings.Repeat(" ", paddingLen)
+			} else if content == "Table info:" {
+				// Collapsible section label with its expand marker
+				marker := "▸ "
+				if m.Schema.InfoExpanded {
+					marker = "▾ "
+				}
+				paddingLen := max(width-2-ui.RuneLen(marker)-len(content), 0)
+				line = ui.StyleDim.Render(marker) + ui.StyleSchemaLabel.Render(content) + strings.Repeat(" ", paddingLen)
+			} else if strings.HasPrefix(content, "INFO|||") {
+				// Table info line: INFO|||Label|||Value, cut at the pane border
+				parts := strings.SplitN(content, "|||", 3)
+				const labelWidth = 12
+				label := fmt.Sprintf("  %-*s", labelWidth-2, parts[1])
+				value := ui.TruncateString(parts[2], max(width-2-labelWidth, 1))
+				paddingLen := max(width-2-labelWidth-ui.RuneLen(value), 0)
+				line = ui.StyleDim.Render(label) + value + strings.Repeat(" ", paddingLen)
 			} else if strings.HasPrefix(content, "IDX|||") {
-				// Index line: IDX|||IndexName|||Fields
+				// Index line: IDX|||Position|||IndexName|||Fields
 				parts := strings.Split(content, "|||")
-				if len(parts) >= 3 {
-					indexName := parts[1]
-					fields := parts[2]
+				if len(parts) >= 4 {
+					position, _ := strconv.Atoi(parts[1])
+					indexName := parts[2]
+					fields := parts[3]
+					// The index under cursor is highlighted while the pane is focused
+					if position == cursor {
+						indexName = ui.StyleTableCursor.Render(indexName)
+					}
 
 					// Format: "  indexName fields" with field names in index color and commas in white
 					var fieldsDisplay string
@@ -321,7 +403,7 @@ func renderSchemaPaneWithHeight(m Model, width int, height int) string {
 					}
 
 					displayText := "  " + indexName + " " + fieldsDisplay
-					displayLen := 2 + len(indexName) + 1 + len(fields)
+					displayLen := 2 + len(parts[2]) + 1 + len(fields)
 
 					availableWidth := width - 2
 					rightPadding := availableWidth - displayLen
@@ -338,8 +420,8 @@ func renderSchemaPaneWithHeight(m Model, width int, height int) string {
 					line = content + strings.Repeat(" ", paddingLen)
 				}
 			} else if strings.Contains(content, "|||") {
-				// Column line with PK, name, type, and maxColNameLen separated by |||
-				// Format: PK|||Name|||Type|||maxLen or PK|||Name|||Type|||maxLen|||inherited
+				// Column tree line with PK, name, type, and maxColNameLen separated by |||
+				// Format: PK|||Name|||Type|||maxLen|||inherited|||Attributes|||Depth|||Marker|||Position
 				parts := strings.Split(content, "|||")
 				if len(parts) >= 4 {
 					pkMarker := parts[0] // "P" or " "
@@ -347,34 +429,67 @@ func renderSchemaPaneWithHeight(m Model, width int, height int) string {
 					colType := parts[2]
 					maxColNameLen, _ := strconv.Atoi(parts[3])
 					isInherited := len(parts) >= 5 && parts[4] == "inherited"
+					attributes := ""
+					if len(parts) >= 6 {
+						attributes = parts[5]
+					}
+					depth, expandMarker, position := 0, "", -1
+					if len(parts) >= 9 {
+						depth, _ = strconv.Atoi(parts[6])
+						expandMarker = parts[7]
+						position, _ = strconv.Atoi(parts[8])
+					}
 
 					// Fixed column widths for alignment
 					const pkColWidth = 2              // Fixed width for PK marker (1 char + 1 space)
 					nameColWidth := maxColNameLen + 1 // Use actual max column name length + 1 space
 
-					// PK marker with fixed width
+					// PK marker with fixed width, followed by the indentation of nested nodes.
+					// The expand marker takes the last two cells before the name (key columns are never complex).
 					var pkField string
 					if pkMarker == "P" {
 						pkField = ui.StyleSchemaPK.Render(pkMarker) + " "
 					} else {
 						pkField = strings.Repeat(" ", pkColWidth)
 					}
+					indent := 2 * depth
+					if expandMarker != "" {
+						if depth == 0 {
+							pkField = ui.StyleDim.Render(expandMarker) + " "
+						} else {
+							pkField += strings.Repeat(" ", indent-2) + ui.StyleDim.Render(expandMarker) + " "
+							indent = 0
+						}
+					}
+					pkField += strings.Repeat(" ", indent)
 
 					// Pad column name to fixed width
-					namePadding := nameColWidth - len(colName)
+					namePadding := nameColWidth - 2*depth - len(colName)
 					if namePadding < 0 {
 						namePadding = 0
 					}
-					nameField := colName + strings.Repeat(" ", namePadding)
+					nameField := colName
+					if position == cursor {
+						// The node under cursor is highlighted while the pane is focused
+						nameField = ui.StyleTableCursor.Render(colName)
+					}
+					nameField += strings.Repeat(" ", namePadding)
 
 					// Type field with inherited marker if applicable
-					typeDisplay := colType
-					typeDisplayWidth := len(colType)
+					marker := ""
 					if isInherited {
-						typeDisplay = colType + " (↑)"
-						typeDisplayWidth = len(colType) + 4 // " (↑)" is 4 display chars (space + parens + arrow)
+						marker = " (↑)"
 					}
+					// Long types (e.g. RECORD fields) and attributes are cut at the pane border
+					typeWidth := max(width-2-pkColWidth-nameColWidth, 1)
+					typeDisplay := ui.TruncateString(colType, max(typeWidth-ui.RuneLen(marker), 1)) + marker
+					typeDisplayWidth := ui.RuneLen(typeDisplay)
 					typeField := ui.StyleSchemaType.Render(typeDisplay)
+					if attributes != "" && typeDisplayWidth+2 < typeWidth {
+						attributes = ui.TruncateString(" "+attributes, typeWidth-typeDisplayWidth)
+						typeDisplayWidth += ui.RuneLen(attributes)
+						typeField += ui.StyleDim.Render(attributes)
+					}
 
 					// Build line with fixed-width columns: PK + Name + Type
 					alignedLine := pkField + nameField + typeField
@@ -426,3 +541,45 @@ func renderSchemaPaneWithHeight(m Model, width int, height int) string {
 
 	return result.String()
 }
+
+// tableTreeMarker returns the marker of a Tables pane line: ▾ above expanded
+// child tables, ▸ when they are collapsed, blank for tables without children
+func tableTreeMarker(line tableLine) string {
+	switch {
+	case line.Collapsed:
+		return "▸ "
+	case line.Children > 0 || line.Table < 0:
+		return "▾ "
+	}
+	return "  "
+}
+
+// collapsedCount returns the number of tables hidden below a collapsed line, e.g. " (3)"
+func collapsedCount(line tableLine) string {
+	if !line.Collapsed {
+		return ""
+	}
+	return fmt.Sprintf(" (%d)", line.Children)
+}
+
+// renderMatches renders text with style, highlighting the runes at positions
+// (in increasing order) matched by the filter
+func renderMatches(text string, positions []int, style lipgloss.Style) string {
+	if len(positions) == 0 {
+		return style.Render(text)
+	}
+	runes := []rune(text)
+	var b strings.Builder
+	start := 0
+	for _, p := range positions {
+		if start < p {
+			b.WriteString(style.Render(string(runes[start:p])))
+		}
+		b.WriteString(ui.StyleTableMatch.Render(string(runes[p])))
+		start = p + 1
+	}
+	if start < len(runes) {
+		b.WriteString(style.Render(string(runes[start:])))
+	}
+	return b.String()
+}
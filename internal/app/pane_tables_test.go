@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/camikura/dito/internal/db"
+	"github.com/charmbracelet/lipgloss"
 	"github.com/oracle/nosql-go-sdk/nosqldb"
 )
 
@@ -70,6 +71,17 @@ func TestRenderTablesPane(t *testing.T) {
 	})
 }
 
+func TestRenderMatches(t *testing.T) {
+	upper := lipgloss.NewStyle().Transform(strings.ToUpper)
+
+	if got := renderMatches("users", []int{0, 2}, upper); got != "uSeRS" {
+		t.Errorf("renderMatches() = %q, want matched runes left to the match style", got)
+	}
+	if got := renderMatches("users", nil, upper); got != "USERS" {
+		t.Errorf("renderMatches() = %q", got)
+	}
+}
+
 func TestRenderSchemaPane(t *testing.T) {
 	t.Run("no table selected shows message", func(t *testing.T) {
 		m := InitialModel()
@@ -151,4 +163,64 @@ func TestRenderSchemaPane(t *testing.T) {
 			t.Error("Expected table name in schema title")
 		}
 	})
+
+	t.Run("shows complex types and column attributes within the pane", func(t *testing.T) {
+		m := InitialModel()
+		m.Tables.Tables = []string{"users"}
+		m.Tables.SelectedTable = 0
+		m.Schema.TableDetails = map[string]*db.TableDetailsResult{
+			"users": {
+				TableName: "users",
+				Schema: &nosqldb.TableResult{
+					DDL: "CREATE TABLE users (id INTEGER, qty INTEGER NOT NULL DEFAULT 1, " +
+						"address RECORD(street STRING, city STRING, zip STRING), PRIMARY KEY(id))",
+				},
+			},
+		}
+
+		result := renderSchemaPaneWithHeight(m, 40, 10)
+
+		for _, want := range []string{"qty     INTEGER DEFAULT 1 NOT NULL", "address RECORD(street STRING, city …"} {
+			if !strings.Contains(result, want) {
+				t.Errorf("Expected %q in output:\n%s", want, result)
+			}
+		}
+		for _, line := range strings.Split(result, "\n") {
+			if w := lipgloss.Width(line); w != 40 {
+				t.Errorf("line width = %d, want 40: %q", w, line)
+			}
+		}
+	})
+
+	t.Run("reads column types from the JSON schema", func(t *testing.T) {
+		m := InitialModel()
+		m.Tables.Tables = []string{"users"}
+		m.Tables.SelectedTable = 0
+		m.Schema.TableDetails = map[string]*db.TableDetailsResult{
+			"users": {
+				TableName: "users",
+				Schema: &nosqldb.TableResult{
+					Schema: `{"name": "users", "primaryKey": ["id"], "shardKey": ["id"], "fields": [
+						{"name": "id", "type": "INTEGER", "nullable": false},
+						{"name": "scores", "type": "MAP", "collection": {"type": "ARRAY", "collection": {"type": "LONG"}}},
+						{"name": "created", "type": "TIMESTAMP", "precision": 3}]}`,
+				},
+			},
+		}
+
+		result := renderSchemaPaneWithHeight(m, 60, 10)
+
+		for _, want := range []string{"id      INTEGER", "scores  MAP(ARRAY(LONG))", "created TIMESTAMP(3)"} {
+			if !strings.Contains(result, want) {
+				t.Errorf("Expected %q in output:\n%s", want, result)
+			}
+		}
+		types := getColumnTypes(m, "users", nil)
+		if types["scores"] != "MAP(ARRAY(LONG))" || types["created"] != "TIMESTAMP(3)" {
+			t.Errorf("getColumnTypes() = %v", types)
+		}
+		if sql := buildDefaultSQL("users", "", tablePrimaryKeys(m, "users")); sql != "SELECT * FROM users ORDER BY id" {
+			t.Errorf("buildDefaultSQL() = %q", sql)
+		}
+	})
 }
@@ -0,0 +1,176 @@
+package app
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/oracle/nosql-go-sdk/nosqldb"
+
+	"github.com/camikura/dito/internal/ddl"
+	"github.com/camikura/dito/internal/ui"
+)
+
+// schemaNodeKey identifies a node of a table's column tree for its expansion state
+type schemaNodeKey struct {
+	Table string
+	Path  string
+}
+
+// schemaNode is a line of the Schema pane's column tree: a column, a RECORD
+// field, or the element of an ARRAY or MAP
+type schemaNode struct {
+	Name       string // Column or field name, "[]" for array elements, "values()" for map values
+	Path       string // Path from the column, e.g. "address.city", "tags[]" or "props.values()"
+	Depth      int    // 0 for columns
+	Type       *ddl.Type
+	Attributes []string // Clauses after the type, e.g. "NOT NULL"
+	PrimaryKey bool
+	Inherited  bool // Primary key column inherited from an ancestor table
+	Expanded   bool
+}
+
+// Expandable reports whether the node has child nodes
+func (n schemaNode) Expandable() bool {
+	if n.Type == nil {
+		return false
+	}
+	return n.Type.Elem != nil || (n.Type.Name == "RECORD" && len(n.Type.Fields) > 0)
+}
+
+// TypeText returns the type shown next to the node: the type name of an
+// expanded node (its children show the rest), otherwise the full type
+func (n schemaNode) TypeText() string {
+	if n.Expanded {
+		return n.Type.Name
+	}
+	return n.Type.String()
+}
+
+// schemaTreeNodes returns the visible nodes of tableName's column tree: the
+// inherited key columns, then the table's own columns, each followed by the
+// nodes below it when expanded
+func schemaTreeNodes(m Model, tableName string) []schemaNode {
+	var nodes []schemaNode
+	for _, ancestorName := range ui.GetAncestorTableNames(tableName) {
+		ancestor := tableSchema(m, ancestorName)
+		if ancestor == nil {
+			continue
+		}
+		for _, col := range ancestor.Columns {
+			if col.PrimaryKey {
+				nodes = append(nodes, schemaNode{Name: col.Name, Path: col.Name, Type: col.Type,
+					Attributes: col.Attributes(), PrimaryKey: true, Inherited: true})
+			}
+		}
+	}
+	if table := tableSchema(m, tableName); table != nil {
+		for _, col := range table.Columns {
+			node := schemaNode{Name: col.Name, Path: col.Name, Type: col.Type,
+				Attributes: col.Attributes(), PrimaryKey: col.PrimaryKey}
+			nodes = appendSchemaNode(m, tableName, nodes, node)
+		}
+	}
+	return nodes
+}
+
+// appendSchemaNode appends node and, when it is expanded, the nodes below it
+func appendSchemaNode(m Model, tableName string, nodes []schemaNode, node schemaNode) []schemaNode {
+	node.Expanded = node.Expandable() && m.Schema.Expanded[schemaNodeKey{Table: tableName, Path: node.Path}]
+	nodes = append(nodes, node)
+	if !node.Expanded {
+		return nodes
+	}
+
+	switch typ := node.Type; {
+	case typ.Name == "ARRAY":
+		child := schemaNode{Name: "[]", Path: node.Path + "[]", Depth: node.Depth + 1, Type: typ.Elem}
+		nodes = appendSchemaNode(m, tableName, nodes, child)
+	case typ.Name == "MAP":
+		child := schemaNode{Name: "values()", Path: node.Path + ".values()", Depth: node.Depth + 1, Type: typ.Elem}
+		nodes = appendSchemaNode(m, tableName, nodes, child)
+	default:
+		for _, field := range typ.Fields {
+			child := schemaNode{Name: field.Name, Path: node.Path + "." + field.Name, Depth: node.Depth + 1,
+				Type: field.Type, Attributes: field.Attributes()}
+			nodes = appendSchemaNode(m, tableName, nodes, child)
+		}
+	}
+	return nodes
+}
+
+// schemaParentNode returns the position of the node above nodes[pos] in the
+// tree, or -1 for columns
+func schemaParentNode(nodes []schemaNode, pos int) int {
+	for i := pos - 1; i >= 0; i-- {
+		if nodes[i].Depth < nodes[pos].Depth {
+			return i
+		}
+	}
+	return -1
+}
+
+// schemaPaneTable returns the table shown in the Schema pane
+func schemaPaneTable(m Model) string {
+	if tableName := m.SelectedTableName(); tableName != "" {
+		return tableName
+	}
+	return m.CursorTableName()
+}
+
+// schemaItems returns the items the Schema pane cursor moves over (the visible
+// column tree nodes, then the indexes) and the cursor position among them,
+// clamped to the items
+func schemaItems(m Model, tableName string) (nodes []schemaNode, indexes []nosqldb.IndexInfo, cursor int) {
+	details := m.Schema.TableDetails[tableName]
+	if details == nil {
+		return nil, nil, 0
+	}
+	nodes = schemaTreeNodes(m, tableName)
+	indexes = details.Indexes
+	cursor = max(min(m.Schema.Cursor, len(nodes)+len(indexes)-1), 0)
+	return nodes, indexes, cursor
+}
+
+// schemaItemLine returns the content line of the item at pos: nodes follow
+// "Columns:", indexes follow the empty line and "Indexes:" after the nodes
+func schemaItemLine(nodeCount, pos int) int {
+	if pos < nodeCount {
+		return 1 + pos
+	}
+	return 3 + pos
+}
+
+// schemaCursorNode returns the column tree node under the Schema pane cursor
+// and its position
+func schemaCursorNode(m Model, tableName string) (schemaNode, int, bool) {
+	nodes, _, cursor := schemaItems(m, tableName)
+	if cursor >= len(nodes) {
+		return schemaNode{}, 0, false
+	}
+	return nodes[cursor], cursor, true
+}
+
+// toggleSchemaNode expands or collapses the node at path
+func toggleSchemaNode(m Model, tableName, path string, expanded bool) Model {
+	key := schemaNodeKey{Table: tableName, Path: path}
+	if m.Schema.Expanded == nil {
+		m.Schema.Expanded = make(map[schemaNodeKey]bool)
+	}
+	if expanded {
+		m.Schema.Expanded[key] = true
+	} else {
+		delete(m.Schema.Expanded, key)
+	}
+	return m
+}
+
+// insertSchemaPath inserts the path of node, qualified with the table alias of
+// the SQL pane's statement (or the table name), at the SQL cursor
+func insertSchemaPath(m Model, tableName string, node schemaNode) (Model, tea.Cmd) {
+	qualifier := ui.ExtractTableAliasFromSQL(m.SQL.CurrentSQL, tableName)
+	if qualifier == "" {
+		qualifier = tableName
+	}
+	path := qualifier + "." + node.Path
+	m.SQL.CurrentSQL = ui.InsertAt(m.SQL.CurrentSQL, m.SQL.CursorPos, path)
+	m.SQL.CursorPos += ui.RuneLen(path)
+	return showFooterMessage(m, "Inserted "+path+" into the SQL pane")
+}
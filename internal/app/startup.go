@@ -0,0 +1,68 @@
+package app
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/camikura/dito/internal/db"
+	"github.com/camikura/dito/internal/ui"
+)
+
+// StartupOptions describes what the program does right after launch
+type StartupOptions struct {
+	Connection *db.ConnectionConfig // Connect immediately when set
+	Profile    string               // Name of the saved profile Connection came from
+	Table      string               // Table to select once connected
+	SQL        string               // SQL to prefill in the SQL pane once connected
+	Refresh    time.Duration        // Interval of the automatic table list refresh, 0 for off
+}
+
+// ApplyStartup applies command-line startup options to the model and
+// returns the command to run when the program starts.
+func ApplyStartup(m Model, opts StartupOptions) (Model, tea.Cmd) {
+	// The refresh interval also applies to connections made from the dialog
+	m.Tables.RefreshInterval = opts.Refresh
+	if opts.Connection == nil {
+		return m, nil
+	}
+	cfg := *opts.Connection
+	m.Connection.Endpoint = cfg.DisplayEndpoint()
+	m.Connection.Edition = cfg.EffectiveEdition()
+	m.Profiles.Active = opts.Profile
+	m.Startup = StartupState{Table: opts.Table, SQL: opts.SQL}
+	return m, db.Connect(cfg, false)
+}
+
+// applyStartupTable selects the table requested on the command line, if any
+func applyStartupTable(m Model) (Model, tea.Cmd) {
+	name := m.Startup.Table
+	if name == "" {
+		return applyStartupSQL(m), nil
+	}
+	m.Startup.Table = ""
+
+	index := m.FindTableIndex(name)
+	if index < 0 {
+		m.Data.ErrorMsg = "Table not found: " + name
+		return applyStartupSQL(m), nil
+	}
+	m.Tables.CursorTable = index
+	m.Tables.ScrollOffset = 0
+	m = scrollTablesToCursor(m)
+	// The SQL prefill waits for the table data so the default query does not replace it
+	return selectCursorTable(m)
+}
+
+// applyStartupSQL prefills the SQL pane with the SQL requested on the command line, if any
+func applyStartupSQL(m Model) Model {
+	if m.Startup.SQL == "" {
+		return m
+	}
+	m.SQL.CurrentSQL = m.Startup.SQL
+	m.SQL.CursorPos = ui.RuneLen(m.SQL.CurrentSQL)
+	m.SQL.ScrollOffset = updateSQLScrollOffset(m)
+	m.CurrentPane = FocusPaneSQL
+	m.Startup.SQL = ""
+	return m
+}
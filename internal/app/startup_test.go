@@ -0,0 +1,149 @@
+package app
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/oracle/nosql-go-sdk/nosqldb"
+
+	"github.com/camikura/dito/internal/db"
+)
+
+func TestApplyStartup(t *testing.T) {
+	t.Run("no connection does nothing", func(t *testing.T) {
+		m, cmd := ApplyStartup(InitialModel(), StartupOptions{})
+
+		if cmd != nil {
+			t.Error("Expected no command")
+		}
+		if m.Connection.Endpoint != "" {
+			t.Errorf("Endpoint = %q, want empty", m.Connection.Endpoint)
+		}
+	})
+
+	t.Run("refresh interval applies without a connection", func(t *testing.T) {
+		m, _ := ApplyStartup(InitialModel(), StartupOptions{Refresh: time.Minute})
+
+		if m.Tables.RefreshInterval != time.Minute {
+			t.Errorf("RefreshInterval = %s, want 1m0s", m.Tables.RefreshInterval)
+		}
+	})
+
+	t.Run("connection starts connecting", func(t *testing.T) {
+		cfg := db.ConnectionConfig{Endpoint: "kvhost", Port: "9090"}
+
+		m, cmd := ApplyStartup(InitialModel(), StartupOptions{Connection: &cfg, Profile: "local", Table: "users"})
+
+		if cmd == nil {
+			t.Error("Expected connect command")
+		}
+		if m.Connection.Endpoint != "kvhost:9090" {
+			t.Errorf("Endpoint = %q, want %q", m.Connection.Endpoint, "kvhost:9090")
+		}
+		if m.Profiles.Active != "local" {
+			t.Errorf("Active = %q, want %q", m.Profiles.Active, "local")
+		}
+		if m.Startup.Table != "users" {
+			t.Errorf("Startup.Table = %q, want %q", m.Startup.Table, "users")
+		}
+	})
+}
+
+func TestStartupTableSelection(t *testing.T) {
+	newModel := func() Model {
+		m := InitialModel()
+		m.Window.Width = 120
+		m.Window.Height = 40
+		m.Connection.Connected = true
+		return m
+	}
+
+	t.Run("selects table when list arrives", func(t *testing.T) {
+		m := newModel()
+		m.Startup = StartupState{Table: "Orders"}
+
+		result, cmd := handleTableListResult(m, db.TableListResult{Tables: []string{"users", "orders"}})
+
+		if result.SelectedTableName() != "orders" {
+			t.Errorf("SelectedTable = %q, want %q", result.SelectedTableName(), "orders")
+		}
+		if result.CurrentPane != FocusPaneData {
+			t.Errorf("CurrentPane = %v, want FocusPaneData", result.CurrentPane)
+		}
+		if result.Startup.Table != "" {
+			t.Error("Expected startup table to be consumed")
+		}
+		if cmd == nil {
+			t.Error("Expected schema fetch command")
+		}
+	})
+
+	t.Run("unknown table shows error", func(t *testing.T) {
+		m := newModel()
+		m.Startup = StartupState{Table: "missing"}
+
+		result, _ := handleTableListResult(m, db.TableListResult{Tables: []string{"users"}})
+
+		if result.Tables.SelectedTable != -1 {
+			t.Errorf("SelectedTable = %d, want -1", result.Tables.SelectedTable)
+		}
+		if result.Data.ErrorMsg != "Table not found: missing" {
+			t.Errorf("ErrorMsg = %q", result.Data.ErrorMsg)
+		}
+	})
+
+	t.Run("SQL without table is prefilled immediately", func(t *testing.T) {
+		m := newModel()
+		m.Startup = StartupState{SQL: "SELECT * FROM users"}
+
+		result, _ := handleTableListResult(m, db.TableListResult{Tables: []string{"users"}})
+
+		if result.SQL.CurrentSQL != "SELECT * FROM users" {
+			t.Errorf("CurrentSQL = %q", result.SQL.CurrentSQL)
+		}
+		if result.SQL.CursorPos != len("SELECT * FROM users") {
+			t.Errorf("CursorPos = %d", result.SQL.CursorPos)
+		}
+		if result.CurrentPane != FocusPaneSQL {
+			t.Errorf("CurrentPane = %v, want FocusPaneSQL", result.CurrentPane)
+		}
+	})
+
+	t.Run("SQL with table waits for table data", func(t *testing.T) {
+		m := newModel()
+		m.Startup = StartupState{Table: "users", SQL: "SELECT id FROM users"}
+
+		m, _ = handleTableListResult(m, db.TableListResult{Tables: []string{"users"}})
+		m, _ = handleTableDetailsResult(m, db.TableDetailsResult{
+			TableName: "users",
+			Schema:    &nosqldb.TableResult{DDL: "CREATE TABLE users (id INTEGER, PRIMARY KEY(id))"},
+		})
+		if m.SQL.CurrentSQL == "SELECT id FROM users" {
+			t.Fatal("SQL should not be prefilled before data arrives")
+		}
+
+		m, _ = handleTableDataResult(m, db.TableDataResult{TableName: "users"})
+
+		if m.SQL.CurrentSQL != "SELECT id FROM users" {
+			t.Errorf("CurrentSQL = %q, want prefilled SQL", m.SQL.CurrentSQL)
+		}
+		if m.CurrentPane != FocusPaneSQL {
+			t.Errorf("CurrentPane = %v, want FocusPaneSQL", m.CurrentPane)
+		}
+		if m.Startup.SQL != "" {
+			t.Error("Expected startup SQL to be consumed")
+		}
+	})
+
+	t.Run("connection failure clears startup actions", func(t *testing.T) {
+		m := newModel()
+		m.Startup = StartupState{Table: "users", SQL: "SELECT 1"}
+
+		result, _ := handleConnectionResult(m, db.ConnectionResult{Err: errors.New("connection refused")})
+
+		if result.Startup != (StartupState{}) {
+			t.Errorf("Startup = %+v, want cleared", result.Startup)
+		}
+	})
+}
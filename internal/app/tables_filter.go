@@ -0,0 +1,78 @@
+package app
+
+import (
+	"slices"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// startTableFilter starts editing the Tables pane filter, keeping the current filter
+func startTableFilter(m Model) Model {
+	m.Tables.Filtering = true
+	return m
+}
+
+// clearTableFilter stops filtering the Tables pane, keeping the cursor on its table
+func clearTableFilter(m Model) Model {
+	m.Tables.Filter = ""
+	m.Tables.Filtering = false
+	return revealTableCursor(m)
+}
+
+// handleTableFilterKeys edits the Tables pane filter. ↑/↓ move over the
+// filtered tables, Enter selects the table under the cursor and Esc clears the
+// filter.
+func handleTableFilterKeys(m Model, msg tea.KeyMsg) (Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyRunes:
+		if msg.Alt {
+			return m, nil
+		}
+		m.Tables.Filter += string(msg.Runes)
+		return revealTableCursor(m), nil
+
+	case tea.KeyBackspace:
+		runes := []rune(m.Tables.Filter)
+		if len(runes) == 0 {
+			m.Tables.Filtering = false
+			return m, nil
+		}
+		m.Tables.Filter = string(runes[:len(runes)-1])
+		return revealTableCursor(m), nil
+
+	case tea.KeyEsc:
+		return clearTableFilter(m), nil
+
+	case tea.KeyEnter:
+		m.Tables.Filtering = false
+		if m.HasValidCursorTable() && tableCursorLine(m, tableLines(m)) >= 0 {
+			return selectCursorTable(m)
+		}
+		return m, nil
+
+	case tea.KeyUp, tea.KeyCtrlP:
+		return moveTableCursor(m, -1), nil
+
+	case tea.KeyDown, tea.KeyCtrlN:
+		return moveTableCursor(m, 1), nil
+	}
+	return m, nil
+}
+
+// revealTableCursor moves the cursor to a shown line when the line under it is
+// hidden, or does not match the filter: the first table matching the filter,
+// or the first line. The cursor is on no table when no line is shown.
+func revealTableCursor(m Model) Model {
+	lines := tableLines(m)
+	if pos := tableCursorLine(m, lines); pos >= 0 && (m.Tables.Filter == "" || lines[pos].Matches != nil) {
+		return scrollTablesToCursor(m)
+	}
+	if len(lines) == 0 {
+		return setTableCursor(m, tableLine{Table: -1})
+	}
+	line := lines[0]
+	if i := slices.IndexFunc(lines, func(l tableLine) bool { return l.Matches != nil }); i >= 0 {
+		line = lines[i]
+	}
+	return scrollTablesToCursor(setTableCursor(m, line))
+}
@@ -0,0 +1,357 @@
+package app
+
+import (
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/camikura/dito/internal/db"
+	"github.com/camikura/dito/internal/ui"
+)
+
+// systemTablesGroup is the group of the system tables (SYS$*), listed after the
+// other tables when shown
+const systemTablesGroup = "SYS$"
+
+// tableLine is a line of the Tables pane: a group header or a table
+type tableLine struct {
+	Group     string // Group of the table or the header: a namespace or systemTablesGroup, "" when not grouped
+	Table     int    // Index in m.Tables.Tables, -1 for group header lines
+	Depth     int    // Tree depth: 0 for headers and top-level tables, +1 per parent table and group
+	Children  int    // Number of tables below the line (descendants, or the tables of a group)
+	Collapsed bool   // Whether the tables below the line are hidden
+	Matches   []int  // Rune positions of the table name matched by the filter
+}
+
+// splitTableNamespace splits a table name into its namespace (DefaultNamespace
+// for unqualified names) and its name within the namespace
+func splitTableNamespace(name string) (namespace, table string) {
+	namespace, table = db.SplitTableName(name)
+	if namespace == "" {
+		namespace = db.DefaultNamespace
+	}
+	return namespace, table
+}
+
+// sameTableName reports whether two table names name the same table: names are
+// case-insensitive and "sysdefault:users" is the same table as "users"
+func sameTableName(a, b string) bool {
+	nsA, tableA := splitTableNamespace(a)
+	nsB, tableB := splitTableNamespace(b)
+	return strings.EqualFold(nsA, nsB) && strings.EqualFold(tableA, tableB)
+}
+
+// tableGroup returns the group tableName is listed under: system tables are
+// grouped together, other tables by namespace when namespaced is set
+func tableGroup(tableName string, namespaced bool) string {
+	switch {
+	case db.IsSystemTable(tableName):
+		return systemTablesGroup
+	case namespaced:
+		namespace, _ := splitTableNamespace(tableName)
+		return namespace
+	}
+	return ""
+}
+
+// tableGroupLabel returns the text of a group header line
+func tableGroupLabel(group string) string {
+	if group == systemTablesGroup {
+		return "System tables"
+	}
+	return group
+}
+
+// tableLines returns the visible lines of the Tables pane: the tables as a tree
+// of parent and child tables. When a table is qualified with a namespace, each
+// namespace is shown as a header line above its tables, and system tables are
+// shown under their own header. The tables below a collapsed group or parent
+// table are hidden. While the list is filtered only the matching tables and
+// their ancestors are shown, whether collapsed or not.
+func tableLines(m Model) []tableLine {
+	tables := m.Tables.Tables
+	shown := filteredTables(m)
+	namespaced := slices.ContainsFunc(tables, func(name string) bool {
+		namespace, _ := db.SplitTableName(name)
+		return namespace != "" && !db.IsSystemTable(name)
+	})
+
+	lines := make([]tableLine, 0, len(tables))
+	header := -1      // Line of the current group header
+	hiddenUnder := "" // Collapsed table whose descendants are skipped
+	for i, name := range tables {
+		matches, ok := shown[i]
+		if shown != nil && !ok {
+			continue
+		}
+		group := tableGroup(name, namespaced)
+		if group != "" && (header < 0 || lines[header].Group != group) {
+			header = len(lines)
+			lines = append(lines, tableLine{Group: group, Table: -1, Collapsed: shown == nil && m.Tables.CollapsedGroups[group]})
+		}
+		if group != "" {
+			lines[header].Children++
+			if lines[header].Collapsed {
+				continue
+			}
+		}
+		if hiddenUnder != "" && strings.HasPrefix(name, hiddenUnder+".") {
+			continue
+		}
+		hiddenUnder = ""
+
+		// Tables are sorted with the descendants of a table right after it
+		_, table := db.SplitTableName(name)
+		line := tableLine{Group: group, Table: i, Depth: strings.Count(table, "."), Matches: matches}
+		if group != "" {
+			line.Depth++
+		}
+		for _, next := range tables[i+1:] {
+			if !strings.HasPrefix(next, name+".") {
+				break
+			}
+			line.Children++
+		}
+		if line.Children > 0 && shown == nil && m.Tables.Collapsed[name] {
+			line.Collapsed = true
+			hiddenUnder = name
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// filteredTables returns the tables shown while the Tables pane is filtered:
+// the tables whose full name (e.g. "ns1:users.addresses") fuzzy matches the
+// filter, with the positions of the matched characters, and their ancestor
+// tables, without positions. Returns nil when the list is not filtered.
+func filteredTables(m Model) map[int][]int {
+	if m.Tables.Filter == "" {
+		return nil
+	}
+	shown := make(map[int][]int)
+	for i, name := range m.Tables.Tables {
+		matches, ok := ui.FuzzyMatch(m.Tables.Filter, name)
+		if !ok {
+			continue
+		}
+		shown[i] = matches
+		for _, ancestor := range ui.GetAncestorTableNames(name) {
+			if j := slices.Index(m.Tables.Tables, ancestor); j >= 0 {
+				if _, ok := shown[j]; !ok {
+					shown[j] = nil
+				}
+			}
+		}
+	}
+	return shown
+}
+
+// tableCursorLine returns the line under the cursor: the header of CursorGroup
+// when the cursor is on a group header, otherwise the line of the cursor table.
+// Returns -1 when the line is not shown.
+func tableCursorLine(m Model, lines []tableLine) int {
+	return slices.IndexFunc(lines, func(l tableLine) bool {
+		if m.Tables.CursorTable < 0 {
+			return l.Table < 0 && l.Group == m.Tables.CursorGroup
+		}
+		return l.Table == m.Tables.CursorTable
+	})
+}
+
+// setTableCursor puts the cursor on line
+func setTableCursor(m Model, line tableLine) Model {
+	m.Tables.CursorTable = line.Table
+	m.Tables.CursorGroup = ""
+	if line.Table < 0 {
+		m.Tables.CursorGroup = line.Group
+	}
+	return m
+}
+
+// moveTableCursor moves the cursor to the previous (step -1) or next (step 1)
+// line and scrolls it into view
+func moveTableCursor(m Model, step int) Model {
+	lines := tableLines(m)
+	if line := tableCursorLine(m, lines) + step; line >= 0 && line < len(lines) {
+		m = setTableCursor(m, lines[line])
+	}
+	return scrollTablesToCursor(m)
+}
+
+// toggleTableGroup collapses or expands group. The cursor moves to the group
+// header when its table is hidden.
+func toggleTableGroup(m Model, group string, collapsed bool) Model {
+	if m.Tables.CollapsedGroups == nil {
+		m.Tables.CollapsedGroups = make(map[string]bool)
+	}
+	if collapsed {
+		m.Tables.CollapsedGroups[group] = true
+	} else {
+		delete(m.Tables.CollapsedGroups, group)
+	}
+	if lines := tableLines(m); tableCursorLine(m, lines) < 0 {
+		m = setTableCursor(m, tableLine{Group: group, Table: -1})
+	}
+	return scrollTablesToCursor(m)
+}
+
+// toggleTableNode collapses or expands the child tables of tableName. The
+// cursor moves to tableName when its table is hidden.
+func toggleTableNode(m Model, tableName string, collapsed bool) Model {
+	if m.Tables.Collapsed == nil {
+		m.Tables.Collapsed = make(map[string]bool)
+	}
+	if collapsed {
+		m.Tables.Collapsed[tableName] = true
+	} else {
+		delete(m.Tables.Collapsed, tableName)
+	}
+	if lines := tableLines(m); tableCursorLine(m, lines) < 0 {
+		m = setTableCursor(m, tableLine{Table: m.FindTableIndex(tableName)})
+	}
+	return scrollTablesToCursor(m)
+}
+
+// handleTableTreeKeys expands and collapses the line under the cursor: Enter
+// toggles a group, →/Ctrl+F expand and ←/Ctrl+B collapse or move to the line
+// above in the tree. Returns false for other keys.
+func handleTableTreeKeys(m Model, msg tea.KeyMsg) (Model, bool) {
+	lines := tableLines(m)
+	pos := tableCursorLine(m, lines)
+	if pos < 0 {
+		return m, false
+	}
+	line := lines[pos]
+
+	switch msg.Type {
+	case tea.KeyEnter:
+		// Enter selects tables
+		if line.Table >= 0 {
+			return m, false
+		}
+		return toggleTableGroup(m, line.Group, !line.Collapsed), true
+
+	case tea.KeyRight, tea.KeyCtrlF:
+		if line.Table < 0 {
+			return toggleTableGroup(m, line.Group, false), true
+		}
+		return toggleTableNode(m, m.Tables.Tables[line.Table], false), true
+
+	case tea.KeyLeft, tea.KeyCtrlB:
+		switch {
+		case line.Table < 0:
+			return toggleTableGroup(m, line.Group, true), true
+		case line.Children > 0 && !line.Collapsed:
+			return toggleTableNode(m, m.Tables.Tables[line.Table], true), true
+		}
+		// Move to the parent table or the group header
+		for i := pos - 1; i >= 0; i-- {
+			if lines[i].Depth < line.Depth {
+				return scrollTablesToCursor(setTableCursor(m, lines[i])), true
+			}
+		}
+		return m, true
+	}
+	return m, false
+}
+
+// scrollTablesToCursor adjusts the Tables pane scroll offset so the line under
+// the cursor, and the group header right above it, are visible
+func scrollTablesToCursor(m Model) Model {
+	lines := tableLines(m)
+	line := tableCursorLine(m, lines)
+	if line < 0 {
+		m.Tables.ScrollOffset = 0
+		return m
+	}
+	top := line
+	if top > 0 && lines[top-1].Table < 0 {
+		top--
+	}
+	visible := calculateTablesHeight(m)
+	switch {
+	case top < m.Tables.ScrollOffset:
+		m.Tables.ScrollOffset = top
+	case visible > 0 && line >= m.Tables.ScrollOffset+visible:
+		m.Tables.ScrollOffset = line - visible + 1
+	}
+	m.Tables.ScrollOffset = max(min(m.Tables.ScrollOffset, len(lines)-visible), 0)
+	return m
+}
+
+// toggleSystemTables shows or hides the system tables (SYS$*) and fetches the table list again
+func toggleSystemTables(m Model) (Model, tea.Cmd) {
+	m.Tables.ShowSystem = !m.Tables.ShowSystem
+	message := "System tables hidden"
+	if m.Tables.ShowSystem {
+		message = "System tables shown"
+	}
+	m, tick := showFooterMessage(m, message)
+	return m, tea.Batch(tick, fetchTables(m))
+}
+
+// fetchTables returns a command that fetches the table list of the selected
+// namespace, or of the connection's default scope
+func fetchTables(m Model) tea.Cmd {
+	if m.Connection.NosqlClient == nil {
+		return nil
+	}
+	namespace := m.Tables.Namespace
+	if namespace == "" {
+		namespace = m.Connection.Namespace
+	}
+	return db.FetchTables(m.Connection.NosqlClient, namespace, m.Tables.ShowSystem)
+}
+
+// namespaceChoices returns the namespaces the Tables pane can be filtered to.
+// "" lists the tables of all namespaces and is offered only when the connection
+// has no default namespace, since requests are otherwise scoped to it.
+func namespaceChoices(m Model) []string {
+	if m.Connection.Namespace != "" {
+		return m.Tables.Namespaces
+	}
+	return append([]string{""}, m.Tables.Namespaces...)
+}
+
+// namespaceLabel describes a namespace filter of the Tables pane
+func namespaceLabel(namespace string) string {
+	if namespace == "" {
+		return "all namespaces"
+	}
+	return namespace
+}
+
+// cycleNamespace filters the Tables pane to the next namespace of the store
+// and fetches its tables
+func cycleNamespace(m Model) (Model, tea.Cmd) {
+	if m.Tables.NamespaceErr != nil {
+		return showFooterMessage(m, "Namespaces unavailable: "+m.Tables.NamespaceErr.Error())
+	}
+	if len(m.Tables.Namespaces) == 0 {
+		return showFooterMessage(m, "No namespaces")
+	}
+	choices := namespaceChoices(m)
+
+	current := m.Tables.Namespace
+	if current == "" {
+		current = m.Connection.Namespace
+	}
+	next := choices[(slices.Index(choices, current)+1)%len(choices)]
+	m.Tables.Namespace = next
+	if next == m.Connection.Namespace {
+		m.Tables.Namespace = ""
+	}
+	m, tick := showFooterMessage(m, "Namespace: "+namespaceLabel(next))
+	return m, tea.Batch(tick, fetchTables(m))
+}
+
+func handleNamespaceListResult(m Model, msg db.NamespaceListResult) (Model, tea.Cmd) {
+	m.Tables.NamespaceErr = msg.Err
+	m.Tables.Namespaces = nil
+	if msg.Err == nil {
+		m.Tables.Namespaces = slices.Sorted(slices.Values(msg.Namespaces))
+	}
+	return m, nil
+}
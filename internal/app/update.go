@@ -30,12 +30,60 @@ func Update(m Model, msg tea.Msg) (Model, tea.Cmd) {
 	case db.TableListResult:
 		return handleTableListResult(m, msg)
 
+	case db.NamespaceListResult:
+		return handleNamespaceListResult(m, msg)
+
 	case db.TableDetailsResult:
 		return handleTableDetailsResult(m, msg)
 
 	case db.TableDataResult:
 		return handleTableDataResult(m, msg)
 
+	case db.TableUsageResult:
+		return handleTableUsageResult(m, msg)
+
+	case usageTickMsg:
+		return handleUsageTick(m, msg)
+
+	case refreshTickMsg:
+		return handleRefreshTick(m, msg)
+
+	case db.StatementResult:
+		return handleStatementResult(m, msg)
+
+	case db.DDLResult:
+		return handleDDLResult(m, msg)
+
+	case db.SystemResult:
+		return handleSystemResult(m, msg)
+
+	case ddlStateExpiredMsg:
+		return handleDDLStateExpired(m, msg)
+
+	case exportProgressMsg:
+		return handleExportProgress(m, msg)
+
+	case rowLoadedMsg:
+		return handleRowLoaded(m, msg)
+
+	case rowSavedMsg:
+		return handleRowSaved(m, msg)
+
+	case rowInsertedMsg:
+		return handleRowInserted(m, msg)
+
+	case rowsDeletedMsg:
+		return handleRowsDeleted(m, msg)
+
+	case rowsRestoredMsg:
+		return handleRowsRestored(m, msg)
+
+	case importProgressMsg:
+		return handleImportProgress(m, msg)
+
+	case profilesSavedMsg:
+		return handleProfilesSaved(m, msg)
+
 	case clearCopyMessageMsg:
 		m.UI.CopyMessage = ""
 		return m, nil
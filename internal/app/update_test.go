@@ -2,12 +2,24 @@ package app
 
 import (
 	"errors"
+	"os"
+	"path/filepath"
 	"reflect"
+	"slices"
+	"strings"
 	"testing"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/oracle/nosql-go-sdk/nosqldb"
+	"github.com/oracle/nosql-go-sdk/nosqldb/types"
 
+	"github.com/camikura/dito/internal/config"
 	"github.com/camikura/dito/internal/db"
+	"github.com/camikura/dito/internal/importer"
+	"github.com/camikura/dito/internal/output"
+	"github.com/camikura/dito/internal/ui"
 )
 
 func TestSortTablesForTree(t *testing.T) {
@@ -56,6 +68,16 @@ func TestSortTablesForTree(t *testing.T) {
 			input:    []string{"a.b", "c", "a", "b.c", "b"},
 			expected: []string{"a", "a.b", "b", "b.c", "c"},
 		},
+		{
+			name:     "child before a longer sibling name",
+			input:    []string{"users_x", "users.phones", "users"},
+			expected: []string{"users", "users.phones", "users_x"},
+		},
+		{
+			name:     "grouped by namespace, default namespace first",
+			input:    []string{"ns2:a", "ns1:users.phones", "users", "ns1:users", "sysdefault:orders"},
+			expected: []string{"sysdefault:orders", "users", "ns1:users", "ns1:users.phones", "ns2:a"},
+		},
 	}
 
 	for _, tt := range tests {
@@ -90,6 +112,7 @@ func TestBuildDefaultSQL(t *testing.T) {
 	tests := []struct {
 		name      string
 		tableName string
+		namespace string // default namespace of the connection
 		ddl       string
 		expected  string
 	}{
@@ -123,11 +146,40 @@ func TestBuildDefaultSQL(t *testing.T) {
 			ddl:       "CREATE TABLE users.addresses (id INTEGER, street STRING, PRIMARY KEY(id))",
 			expected:  "SELECT * FROM users.addresses ORDER BY id",
 		},
+		{
+			name:      "key sizes, records and defaults",
+			tableName: "t",
+			ddl:       "CREATE TABLE t (a INTEGER, r RECORD(x INTEGER, y STRING), b STRING DEFAULT 'PRIMARY KEY(z)', PRIMARY KEY(SHARD(a(3)), b))",
+			expected:  "SELECT * FROM t ORDER BY a, b",
+		},
+		{
+			name:      "namespace",
+			tableName: "ns1:users.phones",
+			ddl:       "CREATE TABLE ns1:users.phones (id INTEGER, PRIMARY KEY(id))",
+			expected:  "SELECT * FROM ns1:users.phones ORDER BY id",
+		},
+		{
+			name:      "default namespace",
+			tableName: "sysdefault:users",
+			expected:  "SELECT * FROM users",
+		},
+		{
+			name:      "default namespace with a connection namespace",
+			tableName: "users",
+			namespace: "ns1",
+			expected:  "SELECT * FROM sysdefault:users",
+		},
+		{
+			name:      "connection namespace",
+			tableName: "ns1:users",
+			namespace: "ns1",
+			expected:  "SELECT * FROM ns1:users",
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := buildDefaultSQL(tt.tableName, tt.ddl)
+			result := buildDefaultSQL(tt.tableName, tt.namespace, ui.ParsePrimaryKeysFromDDL(tt.ddl))
 			if result != tt.expected {
 				t.Errorf("buildDefaultSQL(%q, %q) = %q, want %q", tt.tableName, tt.ddl, result, tt.expected)
 			}
@@ -946,6 +998,26 @@ func TestHandleTableListResult(t *testing.T) {
 		}
 	})
 
+	t.Run("refresh keeps selection and cursor", func(t *testing.T) {
+		m := InitialModel()
+		m.Tables.Tables = []string{"products", "users"}
+		m.Tables.SelectedTable = 1
+		m.Tables.CursorTable = 0
+
+		newModel, _ := handleTableListResult(m, db.TableListResult{
+			Tables: []string{"users", "products", "orders"},
+		})
+
+		if newModel.SelectedTableName() != "users" || newModel.CursorTableName() != "products" {
+			t.Errorf("selected = %q, cursor = %q", newModel.SelectedTableName(), newModel.CursorTableName())
+		}
+
+		newModel, _ = handleTableListResult(newModel, db.TableListResult{Tables: []string{"orders"}})
+		if newModel.Tables.SelectedTable != -1 || newModel.CursorTableName() != "orders" {
+			t.Errorf("SelectedTable = %d, cursor = %q after drop", newModel.Tables.SelectedTable, newModel.CursorTableName())
+		}
+	})
+
 	t.Run("error returns unchanged model", func(t *testing.T) {
 		m := InitialModel()
 
@@ -1181,7 +1253,7 @@ func TestHandleConnectionDialogKeysAdditional(t *testing.T) {
 	t.Run("Tab wraps around from last field", func(t *testing.T) {
 		m := InitialModel()
 		m.ConnectionDialog.Visible = true
-		m.ConnectionDialog.Field = 1 // Port field (last)
+		m.ConnectionDialog.Field = len(m.ConnectionDialog.Fields()) - 1 // Edition field (last)
 
 		newModel, _ := handleConnectionDialogKeys(m, tea.KeyMsg{Type: tea.KeyTab})
 
@@ -1219,6 +1291,408 @@ func TestHandleConnectionDialogKeysAdditional(t *testing.T) {
 	})
 }
 
+func TestConnectionDialogEdition(t *testing.T) {
+	t.Run("Space on edition field switches to Cloud", func(t *testing.T) {
+		m := InitialModel()
+		m.ConnectionDialog.Visible = true
+		m.ConnectionDialog.Field = len(m.ConnectionDialog.Fields()) - 1 // Edition field
+
+		newModel, _ := handleConnectionDialogKeys(m, tea.KeyMsg{Type: tea.KeySpace})
+
+		if newModel.ConnectionDialog.Edition != db.EditionCloud {
+			t.Errorf("Edition = %q, want %q", newModel.ConnectionDialog.Edition, db.EditionCloud)
+		}
+		if newModel.ConnectionDialog.CurrentField() != ConnFieldEdition {
+			t.Errorf("CurrentField = %d, want edition field", newModel.ConnectionDialog.CurrentField())
+		}
+		if newModel.ConnectionDialog.EditOCIProfile != db.DefaultOCIProfile {
+			t.Errorf("EditOCIProfile = %q, want %q", newModel.ConnectionDialog.EditOCIProfile, db.DefaultOCIProfile)
+		}
+	})
+
+	t.Run("Left on edition field wraps around", func(t *testing.T) {
+		m := InitialModel()
+		m.ConnectionDialog.Visible = true
+		m.ConnectionDialog.Edition = db.EditionOnPremise
+		m.ConnectionDialog.Field = len(m.ConnectionDialog.Fields()) - 1
+
+		newModel, _ := handleConnectionDialogKeys(m, tea.KeyMsg{Type: tea.KeyLeft})
+
+		editions := db.Editions()
+		if newModel.ConnectionDialog.Edition != editions[len(editions)-1] {
+			t.Errorf("Edition = %q, want %q", newModel.ConnectionDialog.Edition, editions[len(editions)-1])
+		}
+	})
+
+	t.Run("Typing in cloud region field", func(t *testing.T) {
+		m := InitialModel()
+		m.ConnectionDialog.Visible = true
+		m.ConnectionDialog.Edition = db.EditionCloud
+		m.ConnectionDialog.Field = 2 // Region field
+		m.ConnectionDialog.EditRegion = "us-"
+		m.ConnectionDialog.EditCursorPos = 3
+
+		newModel, _ := handleConnectionDialogKeys(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("ashburn-1")})
+
+		if newModel.ConnectionDialog.EditRegion != "us-ashburn-1" {
+			t.Errorf("EditRegion = %q, want %q", newModel.ConnectionDialog.EditRegion, "us-ashburn-1")
+		}
+	})
+
+	t.Run("Enter connects with cloud configuration", func(t *testing.T) {
+		m := InitialModel()
+		m.ConnectionDialog.Visible = true
+		m.ConnectionDialog.Edition = db.EditionCloud
+		m.ConnectionDialog.EditRegion = "us-ashburn-1"
+
+		newModel, cmd := handleConnectionDialogKeys(m, tea.KeyMsg{Type: tea.KeyEnter})
+
+		if newModel.ConnectionDialog.Visible {
+			t.Error("Expected dialog to be closed")
+		}
+		if newModel.Connection.Endpoint != "us-ashburn-1" {
+			t.Errorf("Endpoint = %q, want %q", newModel.Connection.Endpoint, "us-ashburn-1")
+		}
+		if cmd == nil {
+			t.Error("Expected connect command")
+		}
+	})
+
+	t.Run("Space toggles HTTPS checkbox", func(t *testing.T) {
+		m := InitialModel()
+		m.ConnectionDialog.Visible = true
+		m.ConnectionDialog.Field = 2 // Secure (HTTPS) field
+
+		newModel, _ := handleConnectionDialogKeys(m, tea.KeyMsg{Type: tea.KeySpace})
+
+		if !newModel.ConnectionDialog.UseHTTPS {
+			t.Error("Expected UseHTTPS to be toggled on")
+		}
+	})
+
+	t.Run("Certificate fields are shown with HTTPS only", func(t *testing.T) {
+		d := ConnectionDialogState{}
+		if slices.Contains(d.Fields(), ConnFieldCertPath) || slices.Contains(d.Fields(), ConnFieldSkipVerify) {
+			t.Errorf("Fields() without HTTPS = %v", d.Fields())
+		}
+		d.UseHTTPS = true
+		if fields := d.Fields(); !slices.Contains(fields, ConnFieldCertPath) || !slices.Contains(fields, ConnFieldSkipVerify) {
+			t.Errorf("Fields() with HTTPS = %v", fields)
+		}
+
+		m := InitialModel()
+		m.ConnectionDialog = d
+		m.ConnectionDialog.Visible = true
+		m.Window.Width, m.Window.Height = 120, 40
+		view := renderConnectionDialog(m)
+		if !strings.Contains(view, "Insecure: [ ] Skip certificate verification") {
+			t.Errorf("Expected insecure checkbox in dialog:\n%s", view)
+		}
+	})
+
+	t.Run("Typing in password field", func(t *testing.T) {
+		m := InitialModel()
+		m.ConnectionDialog.Visible = true
+		m.ConnectionDialog.Field = 4 // Password field (no certificate fields without HTTPS)
+
+		newModel, _ := handleConnectionDialogKeys(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("pw")})
+
+		if newModel.ConnectionDialog.EditPassword != "pw" {
+			t.Errorf("EditPassword = %q, want %q", newModel.ConnectionDialog.EditPassword, "pw")
+		}
+	})
+
+	t.Run("ConnectionConfig uses edition specific fields", func(t *testing.T) {
+		d := ConnectionDialogState{
+			Edition:         db.EditionCloud,
+			EditEndpoint:    "localhost",
+			EditPort:        "8080",
+			EditRegion:      "us-ashburn-1",
+			EditCompartment: "dev",
+		}
+
+		cfg := d.ConnectionConfig()
+
+		if cfg.Endpoint != "" || cfg.Port != "" {
+			t.Errorf("Cloud config should not carry on-premise endpoint, got %q:%q", cfg.Endpoint, cfg.Port)
+		}
+		if cfg.Region != "us-ashburn-1" || cfg.Compartment != "dev" {
+			t.Errorf("Cloud config = %+v", cfg)
+		}
+	})
+
+	t.Run("Switching to Cloud Simulator fills defaults", func(t *testing.T) {
+		m := InitialModel()
+		m.ConnectionDialog.Visible = true
+		m.ConnectionDialog.Edition = db.EditionCloud
+		m.ConnectionDialog.Field = len(m.ConnectionDialog.Fields()) - 1
+
+		newModel, _ := handleConnectionDialogKeys(m, tea.KeyMsg{Type: tea.KeySpace})
+
+		d := newModel.ConnectionDialog
+		if d.Edition != db.EditionCloudSim {
+			t.Errorf("Edition = %q, want %q", d.Edition, db.EditionCloudSim)
+		}
+		if d.EditEndpoint != "localhost" || d.EditPort != "8080" {
+			t.Errorf("Endpoint = %q:%q, want localhost:8080", d.EditEndpoint, d.EditPort)
+		}
+		if d.EditTenantID != db.DefaultCloudSimTenantID {
+			t.Errorf("EditTenantID = %q, want %q", d.EditTenantID, db.DefaultCloudSimTenantID)
+		}
+	})
+
+	t.Run("Enter connects with Cloud Simulator configuration", func(t *testing.T) {
+		m := InitialModel()
+		m.ConnectionDialog.Visible = true
+		m.ConnectionDialog.Edition = db.EditionCloudSim
+		m.ConnectionDialog.EditEndpoint = "localhost"
+		m.ConnectionDialog.EditPort = "8080"
+		m.ConnectionDialog.EditTenantID = "dev"
+
+		cfg := m.ConnectionDialog.ConnectionConfig()
+		if cfg.TenantID != "dev" || cfg.Endpoint != "localhost" || cfg.Port != "8080" {
+			t.Errorf("Cloud Simulator config = %+v", cfg)
+		}
+
+		newModel, cmd := handleConnectionDialogKeys(m, tea.KeyMsg{Type: tea.KeyEnter})
+
+		if newModel.Connection.Edition != db.EditionCloudSim {
+			t.Errorf("Connection.Edition = %q, want %q", newModel.Connection.Edition, db.EditionCloudSim)
+		}
+		if newModel.Connection.Endpoint != "localhost:8080" {
+			t.Errorf("Endpoint = %q, want %q", newModel.Connection.Endpoint, "localhost:8080")
+		}
+		if cmd == nil {
+			t.Error("Expected connect command")
+		}
+	})
+}
+
+func TestProfilePicker(t *testing.T) {
+	newModel := func() Model {
+		m := InitialModel()
+		m.Profiles.Items = []config.Profile{
+			{Name: "local", Edition: db.EditionOnPremise, Endpoint: "localhost", Port: "8080"},
+			{Name: "secure", Edition: db.EditionOnPremise, Endpoint: "kvproxy", Port: "8443", Username: "admin", PasswordEnv: "DITO_TEST_UNSET_PASSWORD"},
+		}
+		return m
+	}
+
+	t.Run("p opens picker on active profile", func(t *testing.T) {
+		m := newModel()
+		m.Profiles.Active = "secure"
+
+		result, _ := handleConnectionKeys(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("p")})
+
+		if !result.ProfilePicker.Visible {
+			t.Error("Expected profile picker to be visible")
+		}
+		if result.ProfilePicker.Cursor != 1 {
+			t.Errorf("Cursor = %d, want 1", result.ProfilePicker.Cursor)
+		}
+	})
+
+	t.Run("Enter connects with selected profile", func(t *testing.T) {
+		m := openProfilePicker(newModel())
+
+		result, cmd := handleKeyPress(m, tea.KeyMsg{Type: tea.KeyEnter})
+
+		if result.ProfilePicker.Visible {
+			t.Error("Expected picker to be closed")
+		}
+		if result.Profiles.Active != "local" {
+			t.Errorf("Active = %q, want %q", result.Profiles.Active, "local")
+		}
+		if result.Connection.Endpoint != "localhost:8080" {
+			t.Errorf("Endpoint = %q, want %q", result.Connection.Endpoint, "localhost:8080")
+		}
+		if cmd == nil {
+			t.Error("Expected connect command")
+		}
+	})
+
+	t.Run("unresolved secret keeps picker open", func(t *testing.T) {
+		m := openProfilePicker(newModel())
+		m.ProfilePicker.Cursor = 1
+
+		result, cmd := handleKeyPress(m, tea.KeyMsg{Type: tea.KeyEnter})
+
+		if !result.ProfilePicker.Visible {
+			t.Error("Expected picker to stay open")
+		}
+		if !strings.Contains(result.ProfilePicker.Message, "DITO_TEST_UNSET_PASSWORD") {
+			t.Errorf("Message = %q, want unset variable error", result.ProfilePicker.Message)
+		}
+		if cmd != nil {
+			t.Error("Expected no command")
+		}
+	})
+
+	t.Run("rename updates name and active profile", func(t *testing.T) {
+		m := openProfilePicker(newModel())
+		m.Profiles.Active = "local"
+
+		m, _ = handleKeyPress(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("r")})
+		if !m.ProfilePicker.Renaming {
+			t.Fatal("Expected rename mode")
+		}
+		m, _ = handleKeyPress(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("-dev")})
+		m, _ = handleKeyPress(m, tea.KeyMsg{Type: tea.KeyEnter})
+
+		if m.Profiles.Items[0].Name != "local-dev" {
+			t.Errorf("Name = %q, want %q", m.Profiles.Items[0].Name, "local-dev")
+		}
+		if m.Profiles.Active != "local-dev" {
+			t.Errorf("Active = %q, want %q", m.Profiles.Active, "local-dev")
+		}
+	})
+
+	t.Run("rename rejects duplicate name", func(t *testing.T) {
+		m := openProfilePicker(newModel())
+		m.ProfilePicker.Renaming = true
+		m.ProfilePicker.EditName = "secure"
+
+		result, _ := handleKeyPress(m, tea.KeyMsg{Type: tea.KeyEnter})
+
+		if !result.ProfilePicker.Renaming {
+			t.Error("Expected to stay in rename mode")
+		}
+		if result.ProfilePicker.Message == "" {
+			t.Error("Expected validation message")
+		}
+		if result.Profiles.Items[0].Name != "local" {
+			t.Errorf("Name = %q, want unchanged", result.Profiles.Items[0].Name)
+		}
+	})
+
+	t.Run("delete requires confirmation", func(t *testing.T) {
+		m := openProfilePicker(newModel())
+		m.ProfilePicker.Cursor = 1
+
+		m, _ = handleKeyPress(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("d")})
+		if !m.ProfilePicker.ConfirmDelete {
+			t.Fatal("Expected delete confirmation")
+		}
+
+		cancelled, _ := handleKeyPress(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("n")})
+		if len(cancelled.Profiles.Items) != 2 {
+			t.Errorf("Expected deletion to be cancelled, got %d profiles", len(cancelled.Profiles.Items))
+		}
+
+		deleted, _ := handleKeyPress(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("y")})
+		if len(deleted.Profiles.Items) != 1 || deleted.Profiles.Items[0].Name != "local" {
+			t.Errorf("Profiles = %+v, want only local", deleted.Profiles.Items)
+		}
+		if deleted.ProfilePicker.Cursor != 0 {
+			t.Errorf("Cursor = %d, want 0", deleted.ProfilePicker.Cursor)
+		}
+	})
+
+	t.Run("edit opens dialog with profile values", func(t *testing.T) {
+		m := openProfilePicker(newModel())
+		m.ProfilePicker.Cursor = 1
+
+		result, _ := handleKeyPress(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("e")})
+
+		d := result.ConnectionDialog
+		if !d.Visible || !d.ProfileMode || d.ProfileIndex != 1 {
+			t.Fatalf("Dialog = %+v, want profile editor for index 1", d)
+		}
+		if result.ProfilePicker.Visible {
+			t.Error("Expected picker to be hidden while editing")
+		}
+		if d.CurrentField() != ConnFieldProfileName || d.EditProfileName != "secure" {
+			t.Errorf("Expected focus on name field with %q, got %d %q", "secure", d.CurrentField(), d.EditProfileName)
+		}
+		if d.EditPasswordEnv != "DITO_TEST_UNSET_PASSWORD" {
+			t.Errorf("EditPasswordEnv = %q", d.EditPasswordEnv)
+		}
+	})
+
+	t.Run("new profile is saved and shown in picker", func(t *testing.T) {
+		m := openProfilePicker(newModel())
+		m.Profiles.Path = filepath.Join(t.TempDir(), "profiles.json")
+
+		m, _ = handleKeyPress(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("n")})
+		m, _ = handleKeyPress(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("staging")})
+		m, cmd := handleKeyPress(m, tea.KeyMsg{Type: tea.KeyEnter})
+
+		if m.ConnectionDialog.Visible || !m.ProfilePicker.Visible {
+			t.Error("Expected to return to the picker")
+		}
+		if len(m.Profiles.Items) != 3 || m.Profiles.Items[2].Name != "staging" {
+			t.Fatalf("Profiles = %+v, want staging appended", m.Profiles.Items)
+		}
+		if m.Profiles.Items[2].Endpoint != "localhost" || m.Profiles.Items[2].Port != "8080" {
+			t.Errorf("Expected default endpoint, got %+v", m.Profiles.Items[2])
+		}
+		if m.ProfilePicker.Cursor != 2 {
+			t.Errorf("Cursor = %d, want 2", m.ProfilePicker.Cursor)
+		}
+		if cmd == nil {
+			t.Fatal("Expected save command")
+		}
+		if msg, ok := cmd().(profilesSavedMsg); !ok || msg.Err != nil {
+			t.Errorf("save result = %+v", msg)
+		}
+		saved, err := config.LoadProfiles(m.Profiles.Path)
+		if err != nil || len(saved) != 3 {
+			t.Errorf("saved profiles = %+v, err = %v", saved, err)
+		}
+	})
+
+	t.Run("saving without a name shows message", func(t *testing.T) {
+		m := openProfileEditor(newModel(), -1)
+
+		result, cmd := handleKeyPress(m, tea.KeyMsg{Type: tea.KeyEnter})
+
+		if !result.ConnectionDialog.Visible {
+			t.Error("Expected dialog to stay open")
+		}
+		if result.ConnectionDialog.Message == "" {
+			t.Error("Expected validation message")
+		}
+		if cmd != nil {
+			t.Error("Expected no command")
+		}
+	})
+
+	t.Run("ctrl+s turns setup into a new profile", func(t *testing.T) {
+		m := InitialModel()
+		m.ConnectionDialog.Visible = true
+		m.ConnectionDialog.EditEndpoint = "kvhost"
+		m.ConnectionDialog.EditPassword = "secret"
+
+		result, _ := handleConnectionDialogKeys(m, tea.KeyMsg{Type: tea.KeyCtrlS})
+
+		d := result.ConnectionDialog
+		if !d.ProfileMode || d.ProfileIndex != -1 {
+			t.Fatalf("Dialog = %+v, want new profile mode", d)
+		}
+		if d.CurrentField() != ConnFieldProfileName {
+			t.Errorf("CurrentField = %d, want name field", d.CurrentField())
+		}
+		for _, field := range d.Fields() {
+			if field == ConnFieldPassword {
+				t.Error("Password field should be replaced by references in profile mode")
+			}
+		}
+		if p := d.Profile(); p.Endpoint != "kvhost" {
+			t.Errorf("Profile endpoint = %q, want %q", p.Endpoint, "kvhost")
+		}
+	})
+
+	t.Run("esc in profile editor returns to picker", func(t *testing.T) {
+		m := openProfileEditor(newModel(), 0)
+
+		result, _ := handleKeyPress(m, tea.KeyMsg{Type: tea.KeyEsc})
+
+		if result.ConnectionDialog.Visible || !result.ProfilePicker.Visible {
+			t.Error("Expected to return to the picker")
+		}
+	})
+}
+
 func TestHandleTablesKeysAdditional(t *testing.T) {
 	t.Run("Empty tables list returns unchanged", func(t *testing.T) {
 		m := InitialModel()
@@ -1560,3 +2034,1786 @@ func TestHandleSQLKeysAdditional(t *testing.T) {
 		}
 	})
 }
+
+func TestExportDialog(t *testing.T) {
+	newModel := func() Model {
+		m := InitialModel()
+		m.Connection.Connected = true
+		m.CurrentPane = FocusPaneData
+		m.Tables.Tables = []string{"users"}
+		m.Tables.SelectedTable = 0
+		m.Schema.TableDetails["users"] = &db.TableDetailsResult{
+			TableName: "users",
+			Schema:    &nosqldb.TableResult{DDL: "CREATE TABLE users (id INTEGER, name STRING, PRIMARY KEY(id))"},
+		}
+		m.Data.TableData["users"] = &db.TableDataResult{
+			TableName: "users",
+			Rows: []map[string]interface{}{
+				{"name": "Alice", "id": 1},
+				{"name": "Bob", "id": 2},
+			},
+		}
+		return m
+	}
+
+	// runExport starts the export and feeds progress messages back until it finishes
+	runExport := func(t *testing.T, m Model) Model {
+		t.Helper()
+		m, cmd := handleKeyPress(m, tea.KeyMsg{Type: tea.KeyEnter})
+		for m.ExportDialog.Job != nil && cmd != nil {
+			m, cmd = Update(m, cmd())
+		}
+		return m
+	}
+
+	t.Run("ctrl+s opens dialog with default path", func(t *testing.T) {
+		m, _ := handleKeyPress(newModel(), tea.KeyMsg{Type: tea.KeyCtrlS})
+
+		if !m.ExportDialog.Visible {
+			t.Fatal("Expected export dialog to be visible")
+		}
+		if m.ExportDialog.EditPath != "users.csv" || m.ExportDialog.Format != output.FormatCSV {
+			t.Errorf("path = %q, format = %q", m.ExportDialog.EditPath, m.ExportDialog.Format)
+		}
+	})
+
+	t.Run("switching format updates extension", func(t *testing.T) {
+		m := openExportDialog(newModel())
+		m, _ = handleKeyPress(m, tea.KeyMsg{Type: tea.KeyTab})
+		m, _ = handleKeyPress(m, tea.KeyMsg{Type: tea.KeySpace})
+
+		if m.ExportDialog.Format != output.FormatJSONL || m.ExportDialog.EditPath != "users.jsonl" {
+			t.Errorf("path = %q, format = %q", m.ExportDialog.EditPath, m.ExportDialog.Format)
+		}
+
+		m, _ = handleKeyPress(m, tea.KeyMsg{Type: tea.KeyLeft})
+		m, _ = handleKeyPress(m, tea.KeyMsg{Type: tea.KeyLeft})
+		if m.ExportDialog.Format != output.FormatJSON || m.ExportDialog.EditPath != "users.json" {
+			t.Errorf("path = %q, format = %q", m.ExportDialog.EditPath, m.ExportDialog.Format)
+		}
+	})
+
+	t.Run("writes rows in schema order", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "users.csv")
+		m := openExportDialog(newModel())
+		m.ExportDialog.EditPath = path
+
+		m = runExport(t, m)
+
+		if m.ExportDialog.Visible {
+			t.Errorf("Expected dialog to close, message = %q", m.ExportDialog.Message)
+		}
+		if !strings.Contains(m.UI.CopyMessage, "Exported 2 rows") {
+			t.Errorf("CopyMessage = %q", m.UI.CopyMessage)
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("ReadFile: %v", err)
+		}
+		if want := "id,name\n1,Alice\n2,Bob\n"; string(data) != want {
+			t.Errorf("file = %q, want %q", data, want)
+		}
+	})
+
+	t.Run("cancel removes partial file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "users.csv")
+		m := openExportDialog(newModel())
+		m.ExportDialog.EditPath = path
+
+		m, cmd := handleKeyPress(m, tea.KeyMsg{Type: tea.KeyEnter})
+		if m.ExportDialog.Job == nil || cmd == nil {
+			t.Fatal("Expected export to start")
+		}
+		m, _ = handleKeyPress(m, tea.KeyMsg{Type: tea.KeyEscape})
+		if !m.ExportDialog.Cancelling {
+			t.Fatal("Expected cancellation to be pending")
+		}
+		m, _ = Update(m, cmd())
+
+		if m.ExportDialog.Visible || m.ExportDialog.Job != nil {
+			t.Error("Expected export to stop")
+		}
+		if m.UI.CopyMessage != "Export cancelled" {
+			t.Errorf("CopyMessage = %q", m.UI.CopyMessage)
+		}
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Errorf("Expected partial file to be removed, Stat error = %v", err)
+		}
+	})
+
+	t.Run("existing file is overwritten after confirmation", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "users.csv")
+		if err := os.WriteFile(path, []byte("keep"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		m := openExportDialog(newModel())
+		m.ExportDialog.EditPath = path
+
+		m, cmd := handleKeyPress(m, tea.KeyMsg{Type: tea.KeyEnter})
+		if !m.ExportDialog.ConfirmOverwrite || m.ExportDialog.Job != nil || cmd != nil {
+			t.Fatal("Expected confirmation before overwriting")
+		}
+		if view := renderExportDialog(m); !strings.Contains(view, "Overwrite: <enter>") {
+			t.Errorf("Expected overwrite prompt:\n%s", view)
+		}
+		// Esc takes back the confirmation and keeps the file
+		m, _ = handleKeyPress(m, tea.KeyMsg{Type: tea.KeyEscape})
+		if !m.ExportDialog.Visible || m.ExportDialog.ConfirmOverwrite {
+			t.Fatal("Expected esc to cancel the confirmation only")
+		}
+		if data, _ := os.ReadFile(path); string(data) != "keep" {
+			t.Errorf("file = %q, want it unchanged", data)
+		}
+
+		// Enter asks again, and Enter once more overwrites
+		m, _ = handleKeyPress(m, tea.KeyMsg{Type: tea.KeyEnter})
+		m = runExport(t, m)
+		if data, _ := os.ReadFile(path); string(data) != "id,name\n1,Alice\n2,Bob\n" {
+			t.Errorf("file = %q, want it overwritten", data)
+		}
+	})
+
+	t.Run("invalid path keeps dialog open", func(t *testing.T) {
+		m := openExportDialog(newModel())
+		m.ExportDialog.EditPath = filepath.Join(t.TempDir(), "missing", "users.csv")
+
+		m, cmd := handleKeyPress(m, tea.KeyMsg{Type: tea.KeyEnter})
+
+		if !m.ExportDialog.Visible || m.ExportDialog.Message == "" || cmd != nil {
+			t.Errorf("Expected error in dialog, got message %q", m.ExportDialog.Message)
+		}
+	})
+}
+
+func TestImportDialog(t *testing.T) {
+	newModel := func() Model {
+		m := InitialModel()
+		m.Connection.Connected = true
+		m.Connection.NosqlClient = &nosqldb.Client{}
+		m.CurrentPane = FocusPaneTables
+		m.Tables.Tables = []string{"users"}
+		return m
+	}
+
+	t.Run("i opens dialog for cursor table", func(t *testing.T) {
+		m, _ := handleKeyPress(newModel(), tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("i")})
+
+		d := m.ImportDialog
+		if !d.Visible || d.TableName != "users" {
+			t.Fatalf("Visible = %v, TableName = %q", d.Visible, d.TableName)
+		}
+		if d.EditPath != "users.csv" || d.EditRejects != "users.rejects.jsonl" || d.Mode != importer.ModeUpsert {
+			t.Errorf("path = %q, rejects = %q, mode = %q", d.EditPath, d.EditRejects, d.Mode)
+		}
+	})
+
+	t.Run("rejects path follows file path until edited", func(t *testing.T) {
+		m := openImportDialog(newModel())
+		m, _ = handleKeyPress(m, tea.KeyMsg{Type: tea.KeyBackspace})
+		m, _ = handleKeyPress(m, tea.KeyMsg{Type: tea.KeyBackspace})
+		m, _ = handleKeyPress(m, tea.KeyMsg{Type: tea.KeyBackspace})
+		m, _ = handleKeyPress(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("jsonl")})
+		if m.ImportDialog.EditPath != "users.jsonl" || m.ImportDialog.EditRejects != "users.rejects.jsonl" {
+			t.Errorf("path = %q, rejects = %q", m.ImportDialog.EditPath, m.ImportDialog.EditRejects)
+		}
+
+		m, _ = handleKeyPress(m, tea.KeyMsg{Type: tea.KeyShiftTab})
+		m, _ = handleKeyPress(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("x")})
+		m, _ = handleKeyPress(m, tea.KeyMsg{Type: tea.KeyTab})
+		m, _ = handleKeyPress(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("y")})
+		if m.ImportDialog.EditPath != "users.jsonly" || m.ImportDialog.EditRejects != "users.rejects.jsonlx" {
+			t.Errorf("path = %q, rejects = %q", m.ImportDialog.EditPath, m.ImportDialog.EditRejects)
+		}
+	})
+
+	t.Run("space toggles mode", func(t *testing.T) {
+		m := openImportDialog(newModel())
+		m, _ = handleKeyPress(m, tea.KeyMsg{Type: tea.KeyTab})
+		m, _ = handleKeyPress(m, tea.KeyMsg{Type: tea.KeySpace})
+		if m.ImportDialog.Mode != importer.ModeInsert {
+			t.Errorf("Mode = %q, want insert", m.ImportDialog.Mode)
+		}
+	})
+
+	t.Run("invalid file keeps dialog open", func(t *testing.T) {
+		dir := t.TempDir()
+		for _, path := range []string{filepath.Join(dir, "users.txt"), filepath.Join(dir, "missing.csv")} {
+			m := openImportDialog(newModel())
+			m.ImportDialog.EditPath = path
+
+			m, cmd := handleKeyPress(m, tea.KeyMsg{Type: tea.KeyEnter})
+
+			if !m.ImportDialog.Visible || m.ImportDialog.Message == "" || cmd != nil {
+				t.Errorf("%s: expected error in dialog, got message %q", path, m.ImportDialog.Message)
+			}
+		}
+	})
+
+	// startedModel returns a model with an import of a JSON Lines file in progress
+	startedModel := func(t *testing.T) Model {
+		t.Helper()
+		path := filepath.Join(t.TempDir(), "users.jsonl")
+		if err := os.WriteFile(path, []byte(`{"id":1}`+"\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		m := openImportDialog(newModel())
+		m.ImportDialog.EditPath = path
+		m, cmd := handleKeyPress(m, tea.KeyMsg{Type: tea.KeyEnter})
+		if m.ImportDialog.Job == nil || cmd == nil {
+			t.Fatalf("Expected import to start, message = %q", m.ImportDialog.Message)
+		}
+		return m
+	}
+
+	t.Run("completion shows summary", func(t *testing.T) {
+		m := startedModel(t)
+		job := m.ImportDialog.Job
+
+		m, _ = Update(m, importProgressMsg{Job: job, Stats: importer.Stats{Read: 3, Written: 2, Rejected: 1}, Done: true})
+
+		if m.ImportDialog.Visible || m.ImportDialog.Job != nil {
+			t.Error("Expected import to finish")
+		}
+		if want := "Imported 2 rows into users, 1 rejected (see " + job.rejectsPath + ")"; m.UI.CopyMessage != want {
+			t.Errorf("CopyMessage = %q, want %q", m.UI.CopyMessage, want)
+		}
+	})
+
+	t.Run("cancel stops after the current chunk", func(t *testing.T) {
+		m := startedModel(t)
+		job := m.ImportDialog.Job
+
+		m, _ = handleKeyPress(m, tea.KeyMsg{Type: tea.KeyEscape})
+		if !m.ImportDialog.Cancelling {
+			t.Fatal("Expected cancellation to be pending")
+		}
+		m, cmd := Update(m, importProgressMsg{Job: job, Stats: importer.Stats{Written: 500}})
+
+		if m.ImportDialog.Visible || m.ImportDialog.Job != nil || cmd == nil {
+			t.Error("Expected import to stop")
+		}
+		if m.UI.CopyMessage != "Import cancelled after 500 rows" {
+			t.Errorf("CopyMessage = %q", m.UI.CopyMessage)
+		}
+	})
+
+	t.Run("error keeps dialog open", func(t *testing.T) {
+		m := startedModel(t)
+		m, _ = Update(m, importProgressMsg{Job: m.ImportDialog.Job, Err: errors.New("table not found")})
+
+		if !m.ImportDialog.Visible || m.ImportDialog.Job != nil {
+			t.Error("Expected dialog to stay open")
+		}
+		if m.ImportDialog.Message != "Import failed: table not found" {
+			t.Errorf("Message = %q", m.ImportDialog.Message)
+		}
+	})
+}
+
+func TestRowEditor(t *testing.T) {
+	const ddl = "CREATE TABLE users (id INTEGER, name STRING, age INTEGER, active BOOLEAN, PRIMARY KEY(id))"
+	schema, err := importer.NewSchema("users", ddl, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	newModel := func() Model {
+		m := InitialModel()
+		m.Connection.Connected = true
+		m.CurrentPane = FocusPaneData
+		m.Tables.Tables = []string{"users"}
+		m.Tables.SelectedTable = 0
+		m.Schema.TableDetails["users"] = &db.TableDetailsResult{
+			TableName: "users",
+			Schema:    &nosqldb.TableResult{DDL: ddl},
+		}
+		m.Data.TableData["users"] = &db.TableDataResult{
+			TableName: "users",
+			Rows: []map[string]interface{}{
+				{"id": float64(1), "name": "Alice", "age": float64(30), "active": true},
+			},
+		}
+		return m
+	}
+
+	// loadedModel opens the editor and delivers the row read from the table
+	loadedModel := func(t *testing.T, focusColumn string) Model {
+		t.Helper()
+		m, _ := openRowEditor(newModel(), focusColumn)
+		row := types.NewOrderedMapValue()
+		row.Put("id", 1)
+		row.Put("name", "Alice")
+		row.Put("age", 30)
+		row.Put("active", true)
+		m, _ = Update(m, rowLoadedMsg{TableName: "users", RowIndex: 0, Schema: schema, Row: row, Version: types.Version("v1")})
+		if m.RowEditor.Loading || len(m.RowEditor.Fields) != 4 {
+			t.Fatalf("Expected row to load, message = %q", m.RowEditor.Message)
+		}
+		return m
+	}
+
+	typeText := func(m Model, text string) Model {
+		m, _ = handleKeyPress(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(text)})
+		return m
+	}
+
+	t.Run("e opens editor and reads the row", func(t *testing.T) {
+		m, cmd := handleKeyPress(newModel(), tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("e")})
+
+		if !m.RowEditor.Visible || !m.RowEditor.Loading || cmd == nil {
+			t.Errorf("Visible = %v, Loading = %v, cmd = %v", m.RowEditor.Visible, m.RowEditor.Loading, cmd != nil)
+		}
+	})
+
+	t.Run("e from record detail", func(t *testing.T) {
+		m := newModel()
+		m.RecordDetail.Visible = true
+		m, _ = handleKeyPress(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("e")})
+
+		if !m.RowEditor.Visible {
+			t.Error("Expected row editor to open from the record detail dialog")
+		}
+	})
+
+	t.Run("custom SQL results are not editable", func(t *testing.T) {
+		m := newModel()
+		m.SQL.CustomSQL = true
+		m.SQL.CurrentSQL = "SELECT name FROM users"
+		m, _ = openRowEditor(m, "")
+
+		if m.RowEditor.Visible || !strings.Contains(m.UI.CopyMessage, "only available for table data") {
+			t.Errorf("Visible = %v, CopyMessage = %q", m.RowEditor.Visible, m.UI.CopyMessage)
+		}
+	})
+
+	t.Run("focuses requested column and skips primary key", func(t *testing.T) {
+		m := loadedModel(t, "age")
+		if got := m.RowEditor.Fields[m.RowEditor.Field].Column.Name; got != "age" {
+			t.Errorf("focused = %q, want age", got)
+		}
+
+		m = loadedModel(t, "id")
+		if got := m.RowEditor.Fields[m.RowEditor.Field].Column.Name; got != "name" {
+			t.Errorf("focused = %q, want name", got)
+		}
+		m, _ = handleKeyPress(m, tea.KeyMsg{Type: tea.KeyShiftTab})
+		if got := m.RowEditor.Fields[m.RowEditor.Field].Column.Name; got != "active" {
+			t.Errorf("focused after shift+tab = %q, want active", got)
+		}
+	})
+
+	t.Run("numeric input ignores letters", func(t *testing.T) {
+		m := loadedModel(t, "age")
+		m = typeText(m, "x")
+		m = typeText(m, "1")
+		if got := m.RowEditor.Fields[2].Text; got != "301" {
+			t.Errorf("age = %q, want 301", got)
+		}
+	})
+
+	t.Run("boolean toggles and null", func(t *testing.T) {
+		m := loadedModel(t, "active")
+		m, _ = handleKeyPress(m, tea.KeyMsg{Type: tea.KeySpace})
+		if got := m.RowEditor.Fields[3].Text; got != "false" {
+			t.Errorf("active = %q, want false", got)
+		}
+		m, _ = handleKeyPress(m, tea.KeyMsg{Type: tea.KeyCtrlD})
+		if !m.RowEditor.Fields[3].Null {
+			t.Error("Expected ctrl+d to set null")
+		}
+	})
+
+	t.Run("enter without changes closes", func(t *testing.T) {
+		m := loadedModel(t, "")
+		m, cmd := handleKeyPress(m, tea.KeyMsg{Type: tea.KeyEnter})
+		if m.RowEditor.Visible || cmd != nil {
+			t.Error("Expected editor to close without writing")
+		}
+	})
+
+	t.Run("invalid value keeps editor open", func(t *testing.T) {
+		m := loadedModel(t, "age")
+		m = typeText(m, "9999999999")
+		m, cmd := handleKeyPress(m, tea.KeyMsg{Type: tea.KeyEnter})
+
+		if cmd != nil || m.RowEditor.Saving {
+			t.Fatal("Expected no write")
+		}
+		if !strings.Contains(m.RowEditor.Message, "out of range") {
+			t.Errorf("Message = %q", m.RowEditor.Message)
+		}
+	})
+
+	t.Run("save writes and updates the row", func(t *testing.T) {
+		m := loadedModel(t, "name")
+		m = typeText(m, "a")
+		m, cmd := handleKeyPress(m, tea.KeyMsg{Type: tea.KeyEnter})
+		if !m.RowEditor.Saving || cmd == nil {
+			t.Fatalf("Expected write to start, message = %q", m.RowEditor.Message)
+		}
+
+		written := map[string]interface{}{"id": float64(1), "name": "Alicea", "age": float64(30), "active": true}
+		m, _ = Update(m, rowSavedMsg{TableName: "users", RowIndex: 0, Row: written, Version: types.Version("v2")})
+
+		if m.RowEditor.Visible || m.UI.CopyMessage != "Row updated" {
+			t.Errorf("Visible = %v, CopyMessage = %q", m.RowEditor.Visible, m.UI.CopyMessage)
+		}
+		if got := m.Data.TableData["users"].Rows[0]["name"]; got != "Alicea" {
+			t.Errorf("name = %v, want Alicea", got)
+		}
+	})
+
+	t.Run("version conflict is reported", func(t *testing.T) {
+		m := loadedModel(t, "name")
+		m = typeText(m, "a")
+		m, _ = handleKeyPress(m, tea.KeyMsg{Type: tea.KeyEnter})
+		m, _ = Update(m, rowSavedMsg{TableName: "users", RowIndex: 0, Err: db.ErrRowChanged})
+
+		if !m.RowEditor.Visible || !m.RowEditor.Conflict || m.RowEditor.Saving {
+			t.Fatalf("Visible = %v, Conflict = %v", m.RowEditor.Visible, m.RowEditor.Conflict)
+		}
+		if !strings.Contains(m.RowEditor.Message, "changed or deleted by someone else") {
+			t.Errorf("Message = %q", m.RowEditor.Message)
+		}
+		if got := m.Data.TableData["users"].Rows[0]["name"]; got != "Alice" {
+			t.Errorf("name = %v, want unchanged Alice", got)
+		}
+
+		m, cmd := handleKeyPress(m, tea.KeyMsg{Type: tea.KeyCtrlR})
+		if !m.RowEditor.Loading || cmd == nil {
+			t.Error("Expected ctrl+r to reload the row")
+		}
+	})
+
+	t.Run("deleted row", func(t *testing.T) {
+		m, _ := openRowEditor(newModel(), "")
+		m, _ = Update(m, rowLoadedMsg{TableName: "users", RowIndex: 0, Schema: schema})
+
+		if m.RowEditor.Message != "The row no longer exists" {
+			t.Errorf("Message = %q", m.RowEditor.Message)
+		}
+	})
+}
+
+func TestNewRowForm(t *testing.T) {
+	const usersDDL = "CREATE TABLE users (id INTEGER, name STRING, PRIMARY KEY(id))"
+	const ordersDDL = "CREATE TABLE users.orders (oid INTEGER GENERATED ALWAYS AS IDENTITY, info JSON, PRIMARY KEY(oid))"
+
+	newModel := func(tableName string) Model {
+		m := InitialModel()
+		m.Connection.Connected = true
+		m.CurrentPane = FocusPaneData
+		m.Tables.Tables = []string{"users", "users.orders"}
+		m.Tables.SelectedTable = 0
+		if tableName == "users.orders" {
+			m.Tables.SelectedTable = 1
+		}
+		m.Schema.TableDetails["users"] = &db.TableDetailsResult{TableName: "users", Schema: &nosqldb.TableResult{DDL: usersDDL}}
+		m.Schema.TableDetails["users.orders"] = &db.TableDetailsResult{TableName: "users.orders", Schema: &nosqldb.TableResult{DDL: ordersDDL}}
+		m.Data.TableData[tableName] = &db.TableDataResult{
+			TableName: tableName,
+			Rows:      []map[string]interface{}{{"id": float64(7), "oid": float64(1)}},
+		}
+		return m
+	}
+
+	typeText := func(m Model, text string) Model {
+		m, _ = handleKeyPress(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(text)})
+		return m
+	}
+
+	t.Run("n opens an empty form", func(t *testing.T) {
+		m, cmd := handleKeyPress(newModel("users"), tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("n")})
+
+		e := m.RowEditor
+		if !e.Visible || !e.Insert || e.Loading || cmd != nil {
+			t.Fatalf("Visible = %v, Insert = %v, Loading = %v", e.Visible, e.Insert, e.Loading)
+		}
+		if len(e.Fields) != 2 || e.Field != 0 || !e.Fields[0].Null || !e.editableField(0) {
+			t.Errorf("Expected editable null fields with the key focused, got %+v", e.Fields)
+		}
+	})
+
+	t.Run("schema not loaded", func(t *testing.T) {
+		m := newModel("users")
+		delete(m.Schema.TableDetails, "users")
+		m, _ = openNewRowForm(m)
+
+		if m.RowEditor.Visible || !strings.Contains(m.UI.CopyMessage, "not loaded yet") {
+			t.Errorf("Visible = %v, CopyMessage = %q", m.RowEditor.Visible, m.UI.CopyMessage)
+		}
+	})
+
+	t.Run("child table inherits the parent key from the selected row", func(t *testing.T) {
+		m, _ := openNewRowForm(newModel("users.orders"))
+
+		e := m.RowEditor
+		if len(e.Fields) != 3 || e.Fields[0].Column.Name != "id" || e.Fields[0].Text != "7" || e.Fields[0].Null {
+			t.Fatalf("Expected prefilled id, got %+v", e.Fields)
+		}
+		if got := e.Fields[e.Field].Column.Name; got != "oid" {
+			t.Errorf("focused = %q, want oid", got)
+		}
+	})
+
+	t.Run("missing primary key is required", func(t *testing.T) {
+		m, _ := openNewRowForm(newModel("users"))
+		m, cmd := handleKeyPress(m, tea.KeyMsg{Type: tea.KeyEnter})
+
+		if cmd != nil || m.RowEditor.Message != "id is required" {
+			t.Errorf("cmd = %v, Message = %q", cmd != nil, m.RowEditor.Message)
+		}
+	})
+
+	t.Run("JSON values are multi-line and validated", func(t *testing.T) {
+		m, _ := openNewRowForm(newModel("users.orders"))
+		m, _ = handleKeyPress(m, tea.KeyMsg{Type: tea.KeyTab}) // oid is generated; tab to info
+		if got := m.RowEditor.Fields[m.RowEditor.Field].Column.Name; got != "info" {
+			t.Fatalf("focused = %q, want info", got)
+		}
+		m = typeText(m, "{")
+		m, _ = handleKeyPress(m, tea.KeyMsg{Type: tea.KeyEnter})
+		m = typeText(m, `"a": 1`)
+		if got := m.RowEditor.Fields[2].Text; got != "{\n\"a\": 1" {
+			t.Fatalf("info = %q", got)
+		}
+
+		m, cmd := handleKeyPress(m, tea.KeyMsg{Type: tea.KeyCtrlS})
+		if cmd != nil || m.RowEditor.Message != "column info: invalid JSON" {
+			t.Fatalf("cmd = %v, Message = %q", cmd != nil, m.RowEditor.Message)
+		}
+
+		m = typeText(m, "}")
+		m, cmd = handleKeyPress(m, tea.KeyMsg{Type: tea.KeyCtrlS})
+		if !m.RowEditor.Saving || cmd == nil {
+			t.Errorf("Expected insert to start, message = %q", m.RowEditor.Message)
+		}
+	})
+
+	t.Run("existing key is reported", func(t *testing.T) {
+		m, _ := openNewRowForm(newModel("users"))
+		m = typeText(m, "7")
+		m, _ = handleKeyPress(m, tea.KeyMsg{Type: tea.KeyEnter})
+		m, _ = Update(m, rowInsertedMsg{TableName: "users", Err: db.ErrRowExists})
+
+		if !m.RowEditor.Visible || m.RowEditor.Message != "A row with this primary key already exists" {
+			t.Errorf("Visible = %v, Message = %q", m.RowEditor.Visible, m.RowEditor.Message)
+		}
+	})
+
+	t.Run("insert reloads the table", func(t *testing.T) {
+		m, _ := openNewRowForm(newModel("users"))
+		m = typeText(m, "8")
+		m, _ = handleKeyPress(m, tea.KeyMsg{Type: tea.KeyEnter})
+		m, cmd := Update(m, rowInsertedMsg{TableName: "users"})
+
+		if m.RowEditor.Visible || m.UI.CopyMessage != "Row inserted" || cmd == nil {
+			t.Errorf("Visible = %v, CopyMessage = %q, cmd = %v", m.RowEditor.Visible, m.UI.CopyMessage, cmd != nil)
+		}
+	})
+}
+
+func TestDeleteRows(t *testing.T) {
+	const ddl = "CREATE TABLE users (id INTEGER, name STRING, PRIMARY KEY(id))"
+
+	newModel := func() Model {
+		m := InitialModel()
+		m.Connection.Connected = true
+		m.Window.Height = 40
+		m.CurrentPane = FocusPaneData
+		m.Tables.Tables = []string{"users"}
+		m.Tables.SelectedTable = 0
+		m.Schema.TableDetails["users"] = &db.TableDetailsResult{TableName: "users", Schema: &nosqldb.TableResult{DDL: ddl}}
+		m.Data.TableData["users"] = &db.TableDataResult{
+			TableName: "users",
+			Rows: []map[string]interface{}{
+				{"id": float64(1), "name": "Alice"},
+				{"id": float64(2), "name": "Bob"},
+				{"id": float64(3), "name": "Carol"},
+				{"id": float64(4), "name": "Dave"},
+			},
+		}
+		return m
+	}
+	press := func(m Model, msg tea.KeyMsg) (Model, tea.Cmd) {
+		return handleKeyPress(m, msg)
+	}
+	space := tea.KeyMsg{Type: tea.KeySpace}
+	runes := func(s string) tea.KeyMsg { return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)} }
+
+	t.Run("space marks rows and moves down", func(t *testing.T) {
+		m, _ := press(newModel(), space)
+		m, _ = press(m, space)
+
+		if !m.Data.Marked[0] || !m.Data.Marked[1] || m.Data.SelectedDataRow != 2 {
+			t.Errorf("Marked = %v, SelectedDataRow = %d", m.Data.Marked, m.Data.SelectedDataRow)
+		}
+		if got := getFooterHelp(m); !strings.Contains(got, "Delete 2 marked: d") {
+			t.Errorf("footer = %q", got)
+		}
+
+		m, _ = press(m, tea.KeyMsg{Type: tea.KeyEscape})
+		if markedRowSet(m) != nil {
+			t.Error("Expected esc to clear marks")
+		}
+	})
+
+	t.Run("d confirms the selected row without marks", func(t *testing.T) {
+		m := newModel()
+		m.Data.SelectedDataRow = 2
+		m, _ = press(m, runes("d"))
+
+		if !m.DeleteDialog.Visible || len(m.DeleteDialog.Rows) != 1 || m.DeleteDialog.Labels[0] != "id=3" {
+			t.Errorf("DeleteDialog = %+v", m.DeleteDialog)
+		}
+		m, _ = press(m, tea.KeyMsg{Type: tea.KeyEscape})
+		if m.DeleteDialog.Visible {
+			t.Error("Expected esc to cancel")
+		}
+	})
+
+	t.Run("custom SQL rows cannot be deleted", func(t *testing.T) {
+		m := newModel()
+		m.SQL.CustomSQL = true
+		m, _ = press(m, runes("d"))
+
+		if m.DeleteDialog.Visible || !strings.Contains(m.UI.CopyMessage, "only be deleted from table data") {
+			t.Errorf("Visible = %v, CopyMessage = %q", m.DeleteDialog.Visible, m.UI.CopyMessage)
+		}
+	})
+
+	t.Run("marked rows are deleted after y", func(t *testing.T) {
+		m := newModel()
+		m.Data.MarkedTable = "users"
+		m.Data.Marked = map[int]bool{2: true, 0: true}
+		m.Data.SelectedDataRow = 3
+		m, _ = press(m, runes("d"))
+		if got := strings.Join(m.DeleteDialog.Labels, "; "); got != "id=1; id=3" {
+			t.Fatalf("Labels = %q", got)
+		}
+
+		m, cmd := press(m, runes("x"))
+		if m.DeleteDialog.Deleting || cmd != nil {
+			t.Fatal("Expected only y to confirm")
+		}
+		m, cmd = press(m, runes("y"))
+		if !m.DeleteDialog.Deleting || cmd == nil {
+			t.Fatal("Expected deletion to start")
+		}
+
+		deleted := []*types.MapValue{types.NewOrderedMapValue(), types.NewOrderedMapValue()}
+		m, _ = Update(m, rowsDeletedMsg{TableName: "users", Rows: []int{0, 2}, Deleted: deleted})
+
+		rows := m.Data.TableData["users"].Rows
+		if len(rows) != 2 || rows[0]["name"] != "Bob" || rows[1]["name"] != "Dave" {
+			t.Errorf("Rows = %v", rows)
+		}
+		if m.Data.SelectedDataRow != 1 {
+			t.Errorf("SelectedDataRow = %d, want 1 (still Dave)", m.Data.SelectedDataRow)
+		}
+		if m.DeleteDialog.Visible || m.Data.Marked != nil || len(m.Data.Undo) != 1 {
+			t.Errorf("Visible = %v, Marked = %v, Undo = %d", m.DeleteDialog.Visible, m.Data.Marked, len(m.Data.Undo))
+		}
+		if m.UI.CopyMessage != "Deleted 2 rows from users (undo: u)" {
+			t.Errorf("CopyMessage = %q", m.UI.CopyMessage)
+		}
+	})
+
+	t.Run("undo restores the last batch", func(t *testing.T) {
+		m := newModel()
+		batch := DeletedRows{TableName: "users", Rows: []*types.MapValue{types.NewOrderedMapValue()}}
+		m.Data.Undo = []DeletedRows{batch}
+
+		m, cmd := press(m, runes("u"))
+		if !m.Data.Restoring || len(m.Data.Undo) != 0 || cmd == nil {
+			t.Fatalf("Restoring = %v, Undo = %d", m.Data.Restoring, len(m.Data.Undo))
+		}
+
+		m, _ = Update(m, rowsRestoredMsg{TableName: "users", Err: errors.New("boom"), Failed: batch.Rows})
+		if m.Data.Restoring || len(m.Data.Undo) != 1 || !strings.Contains(m.UI.CopyMessage, "Restore failed") {
+			t.Errorf("Restoring = %v, Undo = %d, CopyMessage = %q", m.Data.Restoring, len(m.Data.Undo), m.UI.CopyMessage)
+		}
+
+		m, _ = press(m, runes("u"))
+		m, _ = Update(m, rowsRestoredMsg{TableName: "users", Restored: 1})
+		if m.UI.CopyMessage != "Restored 1 rows in users" || len(m.Data.Undo) != 0 {
+			t.Errorf("CopyMessage = %q, Undo = %d", m.UI.CopyMessage, len(m.Data.Undo))
+		}
+	})
+
+	t.Run("nothing to undo", func(t *testing.T) {
+		m, cmd := press(newModel(), runes("u"))
+		if m.Data.Restoring || m.UI.CopyMessage != "Nothing to undo" || cmd == nil {
+			t.Errorf("Restoring = %v, CopyMessage = %q", m.Data.Restoring, m.UI.CopyMessage)
+		}
+	})
+}
+
+func TestExecuteDML(t *testing.T) {
+	newModel := func() Model {
+		m := InitialModel()
+		m.Connection.Connected = true
+		m.Window.Height = 40
+		m.CurrentPane = FocusPaneSQL
+		m.Tables.Tables = []string{"users"}
+		m.Tables.SelectedTable = 0
+		m.Schema.TableDetails["users"] = &db.TableDetailsResult{TableName: "users"}
+		m.Data.TableData["users"] = &db.TableDataResult{
+			TableName: "users",
+			Rows:      []map[string]interface{}{{"id": float64(1)}, {"id": float64(2)}},
+		}
+		m.SQL.CurrentSQL = "UPDATE users SET name = 'x' WHERE id > 0"
+		return m
+	}
+
+	t.Run("reports affected rows", func(t *testing.T) {
+		m, cmd := handleKeyPress(newModel(), tea.KeyMsg{Type: tea.KeyCtrlR})
+		if !m.Data.Running || !m.SQL.CustomSQL || cmd == nil {
+			t.Fatalf("Running = %v, CustomSQL = %v", m.Data.Running, m.SQL.CustomSQL)
+		}
+
+		m, _ = Update(m, db.StatementResult{TableName: "users", SQL: m.SQL.CurrentSQL, Keyword: "UPDATE", Affected: 2})
+		if m.Data.Running || m.Data.Statement == nil || m.Data.Statement.Affected != 2 {
+			t.Fatalf("Running = %v, Statement = %+v", m.Data.Running, m.Data.Statement)
+		}
+		if _, ok := m.Data.TableData["users"]; ok {
+			t.Error("stale rows of users are still cached")
+		}
+		if got := getFooterHelp(m); got != "Reset: esc" {
+			t.Errorf("footer = %q", got)
+		}
+
+		m, _ = handleKeyPress(m, tea.KeyMsg{Type: tea.KeyEscape})
+		if m.Data.Statement != nil || m.SQL.CustomSQL {
+			t.Errorf("Statement = %+v, CustomSQL = %v after reset", m.Data.Statement, m.SQL.CustomSQL)
+		}
+	})
+
+	t.Run("shows errors", func(t *testing.T) {
+		m, _ := handleKeyPress(newModel(), tea.KeyMsg{Type: tea.KeyCtrlR})
+		m, _ = Update(m, db.StatementResult{TableName: "users", Keyword: "UPDATE", Err: errors.New("no such column")})
+		if m.Data.Running || m.Data.Statement != nil || m.Data.ErrorMsg != "no such column" {
+			t.Errorf("Running = %v, Statement = %+v, ErrorMsg = %q", m.Data.Running, m.Data.Statement, m.Data.ErrorMsg)
+		}
+	})
+
+	t.Run("queries still show rows", func(t *testing.T) {
+		m := newModel()
+		m.SQL.CurrentSQL = "SELECT * FROM users"
+		m, _ = handleKeyPress(m, tea.KeyMsg{Type: tea.KeyCtrlR})
+		if m.Data.Running {
+			t.Error("SELECT is treated as DML")
+		}
+	})
+}
+
+func TestExecuteDDL(t *testing.T) {
+	const create = "CREATE TABLE orders (id INTEGER, PRIMARY KEY(id))"
+
+	newModel := func() Model {
+		m := InitialModel()
+		m.Connection.Connected = true
+		m.Window.Width = 120
+		m.Window.Height = 40
+		m.CurrentPane = FocusPaneSQL
+		m.Tables.Tables = []string{"users"}
+		m.Tables.SelectedTable = 0
+		m.SQL.CurrentSQL = create
+		return m
+	}
+
+	t.Run("tracks the operation in the Tables pane", func(t *testing.T) {
+		m, cmd := handleKeyPress(newModel(), tea.KeyMsg{Type: tea.KeyCtrlR})
+		if cmd == nil || m.SQL.CustomSQL || m.CurrentPane != FocusPaneSQL {
+			t.Fatalf("CustomSQL = %v, CurrentPane = %v", m.SQL.CustomSQL, m.CurrentPane)
+		}
+		if m.UI.CopyMessage != "Running CREATE on orders..." {
+			t.Errorf("CopyMessage = %q", m.UI.CopyMessage)
+		}
+
+		m, cmd = Update(m, db.DDLResult{TableName: "orders", SQL: create, Keyword: "CREATE", State: types.Creating})
+		if cmd == nil || m.Tables.Operations["orders"] != "CREATING" {
+			t.Fatalf("Operations = %v", m.Tables.Operations)
+		}
+		m, _ = Update(m, db.TableListResult{Tables: []string{"users", "orders"}})
+		if pane := renderTablesPaneWithHeight(m, 30, 5); !strings.Contains(pane, "orders CREATING") {
+			t.Errorf("Tables pane does not show the state:\n%s", pane)
+		}
+		if m.SelectedTableName() != "users" {
+			t.Errorf("selected table = %q", m.SelectedTableName())
+		}
+
+		m, cmd = Update(m, db.DDLResult{TableName: "orders", Keyword: "CREATE", State: types.Active, Done: true})
+		if cmd == nil || m.Tables.Operations["orders"] != "ACTIVE" {
+			t.Fatalf("Operations = %v", m.Tables.Operations)
+		}
+		if m.UI.CopyMessage != "CREATE completed: orders is ACTIVE" {
+			t.Errorf("CopyMessage = %q", m.UI.CopyMessage)
+		}
+
+		m, _ = Update(m, ddlStateExpiredMsg{TableName: "orders"})
+		if len(m.Tables.Operations) != 0 {
+			t.Errorf("Operations = %v after expiry", m.Tables.Operations)
+		}
+	})
+
+	t.Run("drop clears cached schema and data", func(t *testing.T) {
+		m := newModel()
+		m.Schema.TableDetails["users"] = &db.TableDetailsResult{TableName: "users"}
+		m.Data.TableData["users"] = &db.TableDataResult{TableName: "users"}
+		m = setTableOperation(m, "users", "DROPPING")
+
+		m, _ = Update(m, db.DDLResult{TableName: "users", Keyword: "DROP", State: types.Dropped, Done: true})
+		if m.Schema.TableDetails["users"] != nil || m.Data.TableData["users"] != nil || len(m.Tables.Operations) != 0 {
+			t.Errorf("caches of users were kept: %v", m.Tables.Operations)
+		}
+		if m.UI.CopyMessage != "DROP completed: users dropped" {
+			t.Errorf("CopyMessage = %q", m.UI.CopyMessage)
+		}
+	})
+
+	t.Run("reports errors", func(t *testing.T) {
+		m := setTableOperation(newModel(), "users", "UPDATING")
+		m, _ = Update(m, db.DDLResult{TableName: "users", Keyword: "ALTER", Err: errors.New("boom"), Done: true})
+		if m.UI.CopyMessage != "ALTER failed: boom" || len(m.Tables.Operations) != 0 {
+			t.Errorf("CopyMessage = %q, Operations = %v", m.UI.CopyMessage, m.Tables.Operations)
+		}
+	})
+
+	t.Run("runs system statements without a table operation", func(t *testing.T) {
+		m := newModel()
+		m.SQL.CurrentSQL = "CREATE NAMESPACE ns1"
+		m, cmd := handleKeyPress(m, tea.KeyMsg{Type: tea.KeyCtrlR})
+		if cmd == nil || m.UI.CopyMessage != "Running CREATE..." || len(m.Tables.Operations) != 0 {
+			t.Fatalf("CopyMessage = %q, Operations = %v", m.UI.CopyMessage, m.Tables.Operations)
+		}
+
+		m.Connection.NosqlClient = &nosqldb.Client{}
+		m, cmd = Update(m, db.SystemResult{SQL: m.SQL.CurrentSQL, Keyword: "CREATE"})
+		if cmd == nil || m.UI.CopyMessage != "CREATE completed" || len(m.Tables.Operations) != 0 {
+			t.Errorf("CopyMessage = %q, Operations = %v", m.UI.CopyMessage, m.Tables.Operations)
+		}
+		m, _ = Update(m, db.SystemResult{Keyword: "GRANT", Err: errors.New("not authorized")})
+		if m.UI.CopyMessage != "GRANT failed: not authorized" {
+			t.Errorf("CopyMessage = %q", m.UI.CopyMessage)
+		}
+	})
+}
+
+func TestTableDesigner(t *testing.T) {
+	newModel := func() Model {
+		m := InitialModel()
+		m.Connection.Connected = true
+		m.Connection.NosqlClient = &nosqldb.Client{}
+		m.Window.Width = 120
+		m.Window.Height = 40
+		m.CurrentPane = FocusPaneTables
+		m.Tables.Tables = []string{"users"}
+		m.Schema.TableDetails["users"] = &db.TableDetailsResult{
+			TableName: "users",
+			Schema:    &nosqldb.TableResult{DDL: "CREATE TABLE users (uid INTEGER, name STRING, PRIMARY KEY(uid))"},
+		}
+		return m
+	}
+	press := func(m Model, keys ...tea.KeyMsg) Model {
+		for _, key := range keys {
+			m, _ = handleKeyPress(m, key)
+		}
+		return m
+	}
+	runes := func(s string) tea.KeyMsg { return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)} }
+	tab := tea.KeyMsg{Type: tea.KeyTab}
+	space := tea.KeyMsg{Type: tea.KeySpace}
+
+	t.Run("designs and creates a table", func(t *testing.T) {
+		m := press(newModel(), runes("n"), runes("events"))
+		if !m.TableDesigner.Visible || m.TableDesigner.Design.Name != "events" {
+			t.Fatalf("Design = %+v", m.TableDesigner.Design)
+		}
+
+		// Second column: "at TIMESTAMP(3)", added below the key column
+		m = press(m, tea.KeyMsg{Type: tea.KeyDown}, tea.KeyMsg{Type: tea.KeyDown}, tea.KeyMsg{Type: tea.KeyEnter}, runes("at"), tab)
+		for m.TableDesigner.Design.Columns[1].Type != "TIMESTAMP" {
+			m = press(m, space)
+		}
+		// Default TTL of 7 days
+		m.TableDesigner.focus(designerRowTTL, 0)
+		m = press(m, runes("7"), runes("x"))
+
+		want := "CREATE TABLE events (\n  id INTEGER,\n  at TIMESTAMP(3),\n  PRIMARY KEY(id)\n) USING TTL 7 DAYS"
+		if ddl, err := m.TableDesigner.Design.DDL(); ddl != want {
+			t.Fatalf("DDL() = %q, %v, want %q", ddl, err, want)
+		}
+		if view := renderTableDesigner(m); !strings.Contains(view, "at TIMESTAMP(3),") {
+			t.Errorf("preview is missing:\n%s", view)
+		}
+
+		m, cmd := handleKeyPress(m, tea.KeyMsg{Type: tea.KeyCtrlS})
+		if m.TableDesigner.Visible || cmd == nil || m.SQL.CurrentSQL != want {
+			t.Errorf("Visible = %v, SQL = %q", m.TableDesigner.Visible, m.SQL.CurrentSQL)
+		}
+	})
+
+	t.Run("invalid design is not created", func(t *testing.T) {
+		m := press(newModel(), runes("n"))
+		m, cmd := handleKeyPress(m, tea.KeyMsg{Type: tea.KeyCtrlS})
+		if !m.TableDesigner.Visible || cmd != nil {
+			t.Error("a table without a name was created")
+		}
+		if view := renderTableDesigner(m); !strings.Contains(view, "table name is required") {
+			t.Errorf("validation error is not shown:\n%s", view)
+		}
+	})
+
+	t.Run("child table shows inherited keys", func(t *testing.T) {
+		m := press(newModel(), runes("c"), runes("logins"))
+		s := m.TableDesigner
+		if s.Design.Name != "users.logins" || s.Design.Columns[0].Name != "" {
+			t.Fatalf("Design = %+v", s.Design)
+		}
+		if cells := designerCells(s.Design, designerRowColumns); slices.Contains(cells, cellShardKey) {
+			t.Error("child tables cannot choose a shard key")
+		}
+		view := renderTableDesigner(m)
+		for _, want := range []string{"New child table of users", "uid", "INTEGER (↑)"} {
+			if !strings.Contains(view, want) {
+				t.Errorf("Expected %q in designer:\n%s", want, view)
+			}
+		}
+		for _, line := range strings.Split(view, "\n") {
+			if w := lipgloss.Width(line); w != m.Window.Width {
+				t.Fatalf("line width = %d, want %d: %q", w, m.Window.Width, line)
+			}
+		}
+	})
+
+	t.Run("read-only connection", func(t *testing.T) {
+		m := newModel()
+		m.Connection.ReadOnly = true
+		m = press(m, runes("n"))
+		if m.TableDesigner.Visible || m.UI.CopyMessage != readOnlyRefusal {
+			t.Errorf("Visible = %v, CopyMessage = %q", m.TableDesigner.Visible, m.UI.CopyMessage)
+		}
+	})
+}
+
+func TestIndexManagement(t *testing.T) {
+	newModel := func() Model {
+		m := InitialModel()
+		m.Connection.Connected = true
+		m.Connection.NosqlClient = &nosqldb.Client{}
+		m.Window.Width = 120
+		m.Window.Height = 40
+		m.CurrentPane = FocusPaneSchema
+		m.Tables.Tables = []string{"users"}
+		m.Tables.SelectedTable = 0
+		m.Schema.TableDetails["users"] = &db.TableDetailsResult{
+			TableName: "users",
+			Schema:    &nosqldb.TableResult{DDL: "CREATE TABLE users (uid INTEGER, name STRING, doc JSON, PRIMARY KEY(uid))"},
+			Indexes: []nosqldb.IndexInfo{
+				{IndexName: "idx_name", FieldNames: []string{"name"}},
+				{IndexName: "idx_city", FieldNames: []string{"doc.city", "doc.tags[]"}, FieldTypes: []string{"STRING", "INTEGER"}},
+			},
+		}
+		return m
+	}
+	press := func(m Model, keys ...tea.KeyMsg) Model {
+		for _, key := range keys {
+			m, _ = handleKeyPress(m, key)
+		}
+		return m
+	}
+	runes := func(s string) tea.KeyMsg { return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)} }
+	down := tea.KeyMsg{Type: tea.KeyDown}
+
+	t.Run("selects and inspects an index", func(t *testing.T) {
+		// The cursor moves over the three columns, then the indexes
+		m := press(newModel(), down, down, down, down)
+		if index, ok := schemaCursorIndex(m, "users"); !ok || index.IndexName != "idx_city" {
+			t.Fatalf("Cursor = %d, index = %q", m.Schema.Cursor, index.IndexName)
+		}
+		// The cursor stops at the last index
+		if m = press(m, down); m.Schema.Cursor != 4 {
+			t.Errorf("Cursor = %d, want 4", m.Schema.Cursor)
+		}
+		if help := getFooterHelp(m); !strings.Contains(help, "Drop index: d") {
+			t.Errorf("footer = %q", help)
+		}
+
+		m = press(m, tea.KeyMsg{Type: tea.KeyEnter})
+		if !m.IndexDetail.Visible || m.IndexDetail.Index.IndexName != "idx_city" || m.IndexDetail.ConfirmDrop {
+			t.Fatalf("IndexDetail = %+v", m.IndexDetail)
+		}
+		view := renderIndexDetail(m)
+		for _, want := range []string{"Index idx_city", "users", "doc.city", "STRING", "doc.tags[]", "INTEGER", "multi-key"} {
+			if !strings.Contains(view, want) {
+				t.Errorf("Expected %q in index detail:\n%s", want, view)
+			}
+		}
+		// The SDK reports no index state, so none is shown
+		if strings.Contains(view, "State") {
+			t.Errorf("Unexpected state in index detail:\n%s", view)
+		}
+		if types := indexFieldTypes(m, "users", m.Schema.TableDetails["users"].Indexes[0]); types[0] != "STRING" {
+			t.Errorf("column index types = %v", types)
+		}
+	})
+
+	t.Run("drops an index after confirmation", func(t *testing.T) {
+		m := press(newModel(), down, down, down, runes("d"))
+		if !m.IndexDetail.ConfirmDrop || !strings.Contains(renderIndexDetail(m), "Drop index idx_name on users?") {
+			t.Fatalf("IndexDetail = %+v", m.IndexDetail)
+		}
+		m, cmd := handleKeyPress(m, runes("y"))
+		if m.IndexDetail.Visible || cmd == nil || m.UI.CopyMessage != "Running DROP on users..." {
+			t.Errorf("Visible = %v, CopyMessage = %q", m.IndexDetail.Visible, m.UI.CopyMessage)
+		}
+
+		// Esc cancels
+		m = press(newModel(), down, down, down, runes("d"), tea.KeyMsg{Type: tea.KeyEscape})
+		if m.IndexDetail.Visible {
+			t.Error("drop confirmation was not cancelled")
+		}
+	})
+
+	t.Run("creates an index with typed JSON fields", func(t *testing.T) {
+		m := press(newModel(), runes("n"))
+		if !m.IndexDialog.Visible || m.IndexDialog.Design.Fields[0].Path != "name" {
+			t.Fatalf("IndexDialog = %+v", m.IndexDialog)
+		}
+		m = press(m, runes("idx_doc"), down)
+		m.IndexDialog.Design.Fields[0].Path = ""
+		m.IndexDialog.CursorPos = 0
+		m = press(m, runes("doc.city"), tea.KeyMsg{Type: tea.KeyTab}, tea.KeyMsg{Type: tea.KeySpace}, tea.KeyMsg{Type: tea.KeySpace})
+		m = press(m, tea.KeyMsg{Type: tea.KeyEnter}, runes("doc.tags[]"), tea.KeyMsg{Type: tea.KeyTab}, tea.KeyMsg{Type: tea.KeyLeft}, tea.KeyMsg{Type: tea.KeyLeft})
+
+		want := "CREATE INDEX idx_doc ON users (doc.city AS STRING, doc.tags[] AS POINT)"
+		if ddl, err := m.IndexDialog.Design.DDL(); ddl != want {
+			t.Fatalf("DDL() = %q, %v, want %q", ddl, err, want)
+		}
+		view := renderIndexDialog(m)
+		if !strings.Contains(view, "multi-key") || !strings.Contains(view, "New index on users") {
+			t.Errorf("dialog:\n%s", view)
+		}
+		for _, line := range strings.Split(view, "\n") {
+			if w := lipgloss.Width(line); w != m.Window.Width {
+				t.Fatalf("line width = %d, want %d: %q", w, m.Window.Width, line)
+			}
+		}
+
+		m, cmd := handleKeyPress(m, tea.KeyMsg{Type: tea.KeyCtrlS})
+		if m.IndexDialog.Visible || cmd == nil || m.UI.CopyMessage != "Running CREATE on users..." {
+			t.Errorf("Visible = %v, CopyMessage = %q", m.IndexDialog.Visible, m.UI.CopyMessage)
+		}
+	})
+
+	t.Run("read-only connection", func(t *testing.T) {
+		m := press(newModel(), down, down, down)
+		m.Connection.ReadOnly = true
+		for _, key := range []string{"n", "d"} {
+			m = press(m, runes(key))
+			if m.IndexDialog.Visible || m.IndexDetail.Visible || m.UI.CopyMessage != readOnlyRefusal {
+				t.Errorf("%s: CopyMessage = %q", key, m.UI.CopyMessage)
+			}
+		}
+		// Inspecting is allowed, dropping is not offered
+		m = press(m, tea.KeyMsg{Type: tea.KeyEnter}, runes("d"))
+		if !m.IndexDetail.Visible || m.IndexDetail.ConfirmDrop {
+			t.Errorf("IndexDetail = %+v", m.IndexDetail)
+		}
+	})
+}
+
+func TestSchemaTree(t *testing.T) {
+	newModel := func() Model {
+		m := InitialModel()
+		m.Window.Width = 120
+		m.Window.Height = 40
+		m.CurrentPane = FocusPaneSchema
+		m.Tables.Tables = []string{"users"}
+		m.Tables.SelectedTable = 0
+		m.Schema.TableDetails["users"] = &db.TableDetailsResult{
+			TableName: "users",
+			Schema: &nosqldb.TableResult{DDL: "CREATE TABLE users (id INTEGER, " +
+				"address RECORD(city STRING, geo RECORD(lat DOUBLE NOT NULL)), tags ARRAY(STRING), " +
+				"scores MAP(ARRAY(LONG)), PRIMARY KEY(id))"},
+		}
+		return m
+	}
+	press := func(m Model, keys ...tea.KeyMsg) Model {
+		for _, key := range keys {
+			m, _ = handleKeyPress(m, key)
+		}
+		return m
+	}
+	paths := func(m Model) []string {
+		var paths []string
+		for _, node := range schemaTreeNodes(m, "users") {
+			paths = append(paths, node.Path)
+		}
+		return paths
+	}
+	runes := func(s string) tea.KeyMsg { return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)} }
+	down := tea.KeyMsg{Type: tea.KeyDown}
+	enter := tea.KeyMsg{Type: tea.KeyEnter}
+
+	t.Run("expands and collapses nested types", func(t *testing.T) {
+		m := newModel()
+		if got, want := paths(m), []string{"id", "address", "tags", "scores"}; !reflect.DeepEqual(got, want) {
+			t.Fatalf("paths = %v, want %v", got, want)
+		}
+
+		m = press(m, down)
+		if help := getFooterHelp(m); !strings.Contains(help, "Expand: <enter>") {
+			t.Errorf("footer = %q", help)
+		}
+		m = press(m, enter, down, down, tea.KeyMsg{Type: tea.KeyRight})
+		want := []string{"id", "address", "address.city", "address.geo", "address.geo.lat", "tags", "scores"}
+		if got := paths(m); !reflect.DeepEqual(got, want) {
+			t.Fatalf("paths = %v, want %v", got, want)
+		}
+		view := renderSchemaPaneWithHeight(m, 60, 12)
+		for _, want := range []string{"▾ address RECORD", "  ▾ geo   RECORD", "      lat DOUBLE NOT NULL", "▸ tags    ARRAY(STRING)"} {
+			if !strings.Contains(view, want) {
+				t.Errorf("Expected %q in schema pane:\n%s", want, view)
+			}
+		}
+		// Columns:, the nodes, the empty Indexes section and the collapsed Table info section
+		if got := calculateSchemaContentLineCount(m, "users"); got != 1+len(want)+3+2 {
+			t.Errorf("content lines = %d, want %d", got, 1+len(want)+3+2)
+		}
+
+		// Left moves from a field to its record, then collapses it
+		m = press(m, down, tea.KeyMsg{Type: tea.KeyLeft}, tea.KeyMsg{Type: tea.KeyLeft})
+		if got := paths(m); len(got) != 6 || m.Schema.Cursor != 3 {
+			t.Errorf("paths = %v, cursor = %d", got, m.Schema.Cursor)
+		}
+		// The expansion state is kept per table
+		if !m.Schema.Expanded[schemaNodeKey{Table: "users", Path: "address"}] {
+			t.Error("address should stay expanded")
+		}
+	})
+
+	t.Run("uses element paths for arrays and maps", func(t *testing.T) {
+		m := press(newModel(), down, down, enter, down, down, enter, down, enter)
+		want := []string{"id", "address", "tags", "tags[]", "scores", "scores.values()", "scores.values()[]"}
+		if got := paths(m); !reflect.DeepEqual(got, want) {
+			t.Fatalf("paths = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("inserts a qualified path into the SQL pane", func(t *testing.T) {
+		m := newModel()
+		m.SQL.CurrentSQL = "SELECT  FROM users t"
+		m.SQL.CursorPos = 7
+		m = press(m, down, enter, down, runes("i"))
+		if m.SQL.CurrentSQL != "SELECT t.address.city FROM users t" || m.SQL.CursorPos != 21 {
+			t.Errorf("CurrentSQL = %q, CursorPos = %d", m.SQL.CurrentSQL, m.SQL.CursorPos)
+		}
+
+		// Without an alias the table name qualifies the path
+		m.SQL.CurrentSQL = "SELECT  FROM users"
+		m.SQL.CursorPos = 7
+		m = press(m, runes("i"))
+		if m.SQL.CurrentSQL != "SELECT users.address.city FROM users" || m.UI.CopyMessage != "Inserted users.address.city into the SQL pane" {
+			t.Errorf("CurrentSQL = %q, CopyMessage = %q", m.SQL.CurrentSQL, m.UI.CopyMessage)
+		}
+	})
+}
+
+func TestTableInfo(t *testing.T) {
+	newModel := func() Model {
+		m := InitialModel()
+		m.Connection.Connected = true
+		m.Connection.NosqlClient = &nosqldb.Client{}
+		m.Window.Width = 120
+		m.Window.Height = 40
+		m.CurrentPane = FocusPaneSchema
+		m.Tables.Tables = []string{"users"}
+		m.Tables.SelectedTable = 0
+		m.Schema.TableDetails["users"] = &db.TableDetailsResult{
+			TableName: "users",
+			Schema: &nosqldb.TableResult{
+				DDL:    "CREATE TABLE users (id INTEGER, PRIMARY KEY(id)) USING TTL 5 DAYS",
+				State:  types.Active,
+				Limits: nosqldb.TableLimits{ReadUnits: 50, WriteUnits: 20, StorageGB: 25},
+			},
+		}
+		return m
+	}
+	runes := func(s string) tea.KeyMsg { return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)} }
+
+	t.Run("shows state, TTL, limits and usage", func(t *testing.T) {
+		m, cmd := handleKeyPress(newModel(), runes("t"))
+		if !m.Schema.InfoExpanded || cmd == nil {
+			t.Fatalf("InfoExpanded = %v, usage fetched = %v", m.Schema.InfoExpanded, cmd != nil)
+		}
+		view := renderSchemaPaneWithHeight(m, 60, 16)
+		for _, want := range []string{"▾ Table info:", "State     ACTIVE", "TTL       5 DAYS", "Limits    50 RU, 20 WU, 25 GB", "not loaded", "Refresh   off"} {
+			if !strings.Contains(view, want) {
+				t.Errorf("Expected %q in schema pane:\n%s", want, view)
+			}
+		}
+
+		start := time.Date(2026, 1, 2, 10, 0, 0, 0, time.Local)
+		m, _ = handleTableUsageResult(m, db.TableUsageResult{TableName: "users", Usage: &nosqldb.TableUsage{
+			StartTime: start, EndTime: start.Add(time.Minute), ReadUnits: 12, WriteUnits: 3, StorageGB: 1, WriteThrottleCount: 2,
+		}})
+		view = renderSchemaPaneWithHeight(m, 60, 16)
+		for _, want := range []string{"Usage     12 RU, 3 WU, 1 GB (10:00:00-10:01:00)", "Throttled read 0, write 2, storage 0"} {
+			if !strings.Contains(view, want) {
+				t.Errorf("Expected %q in schema pane:\n%s", want, view)
+			}
+		}
+		for _, line := range strings.Split(view, "\n") {
+			if w := lipgloss.Width(line); w != 60 {
+				t.Errorf("line width = %d, want 60: %q", w, line)
+			}
+		}
+		// Columns:, id, the empty Indexes section, then the label and six info lines
+		if got := calculateSchemaContentLineCount(m, "users"); got != 2+3+2+6 {
+			t.Errorf("content lines = %d, want %d", got, 2+3+2+6)
+		}
+
+		m, _ = handleTableUsageResult(m, db.TableUsageResult{TableName: "users", Err: errors.New("not supported")})
+		if view = renderSchemaPaneWithHeight(m, 60, 16); !strings.Contains(view, "unavailable: not supported") {
+			t.Errorf("schema pane:\n%s", view)
+		}
+
+		// Collapsing keeps the usage
+		if m, cmd = handleKeyPress(m, runes("t")); m.Schema.InfoExpanded || cmd != nil {
+			t.Errorf("InfoExpanded = %v, usage fetched = %v", m.Schema.InfoExpanded, cmd != nil)
+		}
+	})
+
+	t.Run("refreshes usage periodically", func(t *testing.T) {
+		m, cmd := handleKeyPress(newModel(), runes("a"))
+		if !m.Schema.UsageRefresh || !m.Schema.InfoExpanded || cmd == nil {
+			t.Fatalf("UsageRefresh = %v, InfoExpanded = %v", m.Schema.UsageRefresh, m.Schema.InfoExpanded)
+		}
+		if _, cmd = handleUsageTick(m, usageTickMsg{Ticker: m.Schema.UsageTicker}); cmd == nil {
+			t.Error("tick should fetch usage and schedule the next tick")
+		}
+		if _, cmd = handleUsageTick(m, usageTickMsg{Ticker: m.Schema.UsageTicker - 1}); cmd != nil {
+			t.Error("stale tick should be ignored")
+		}
+
+		m, _ = handleKeyPress(m, runes("a"))
+		if _, cmd = handleUsageTick(m, usageTickMsg{Ticker: m.Schema.UsageTicker}); m.Schema.UsageRefresh || cmd != nil {
+			t.Error("tick after stopping should be ignored")
+		}
+	})
+
+	t.Run("formats limits", func(t *testing.T) {
+		if got := formatTableLimits(nosqldb.TableLimits{}); got != "none" {
+			t.Errorf("on-premise limits = %q", got)
+		}
+		if got := formatTableLimits(nosqldb.TableLimits{StorageGB: 10, CapacityMode: types.OnDemand}); got != "on demand, 10 GB" {
+			t.Errorf("on demand limits = %q", got)
+		}
+	})
+}
+
+func TestTableNamespaces(t *testing.T) {
+	newModel := func() Model {
+		m := InitialModel()
+		m.Connection.Connected = true
+		m.Connection.NosqlClient = &nosqldb.Client{}
+		m.Window.Width = 120
+		m.Window.Height = 40
+		m.CurrentPane = FocusPaneTables
+		m, _ = handleTableListResult(m, db.TableListResult{Tables: []string{"ns1:users.phones", "orders", "ns1:users"}})
+		return m
+	}
+	runes := func(s string) tea.KeyMsg { return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)} }
+
+	t.Run("shows namespaces as top-level nodes", func(t *testing.T) {
+		m := newModel()
+		view := renderTablesPaneWithHeight(m, 40, 8)
+		for _, want := range []string{"▾ sysdefault", "      orders", "▾ ns1", "    ▾ users", "        phones"} {
+			if !strings.Contains(view, want) {
+				t.Errorf("Expected %q in tables pane:\n%s", want, view)
+			}
+		}
+		if strings.Contains(view, "ns1:") {
+			t.Errorf("table names should not repeat their namespace:\n%s", view)
+		}
+	})
+
+	t.Run("namespace headers collapse and expand", func(t *testing.T) {
+		m := newModel()
+		if m.CursorTableName() != "orders" {
+			t.Fatalf("cursor on %q, want orders", m.CursorTableName())
+		}
+		m, _ = handleKeyPress(m, tea.KeyMsg{Type: tea.KeyDown})
+		if m.CursorTableName() != "" || m.Tables.CursorGroup != "ns1" {
+			t.Fatalf("cursor on %q/%q after down, want the ns1 header", m.CursorTableName(), m.Tables.CursorGroup)
+		}
+		m, _ = handleKeyPress(m, tea.KeyMsg{Type: tea.KeyEnter})
+		view := renderTablesPaneWithHeight(m, 40, 8)
+		if !strings.Contains(view, "▸ ns1 (2)") || strings.Contains(view, "phones") {
+			t.Errorf("ns1 should be collapsed:\n%s", view)
+		}
+		m, _ = handleTableListResult(m, db.TableListResult{Tables: []string{"ns1:users.phones", "orders", "ns1:users"}})
+		if m.Tables.CursorGroup != "ns1" || !m.Tables.CollapsedGroups["ns1"] {
+			t.Error("refreshing the list should keep the cursor on the collapsed header")
+		}
+		m, _ = handleKeyPress(m, tea.KeyMsg{Type: tea.KeyRight})
+		m, _ = handleKeyPress(m, tea.KeyMsg{Type: tea.KeyDown})
+		if m.CursorTableName() != "ns1:users" {
+			t.Errorf("cursor on %q after expanding, want ns1:users", m.CursorTableName())
+		}
+		m, _ = handleKeyPress(m, tea.KeyMsg{Type: tea.KeyUp})
+		m, _ = handleKeyPress(m, tea.KeyMsg{Type: tea.KeyUp})
+		if m.CursorTableName() != "orders" || m.Tables.ScrollOffset != 0 {
+			t.Errorf("cursor on %q at scroll %d, want orders with its header visible", m.CursorTableName(), m.Tables.ScrollOffset)
+		}
+	})
+
+	t.Run("system tables are listed in their own group", func(t *testing.T) {
+		m := newModel()
+		m, cmd := handleKeyPress(m, runes("S"))
+		if !m.Tables.ShowSystem || cmd == nil {
+			t.Fatalf("ShowSystem = %v, list fetched = %v", m.Tables.ShowSystem, cmd != nil)
+		}
+		m, _ = handleTableListResult(m, db.TableListResult{Tables: []string{"SYS$TableStatsPartition", "orders", "ns1:users", "SYS$IndexStatsLease"}})
+		want := []string{"orders", "ns1:users", "SYS$IndexStatsLease", "SYS$TableStatsPartition"}
+		if !reflect.DeepEqual(m.Tables.Tables, want) {
+			t.Errorf("Tables = %v, want %v", m.Tables.Tables, want)
+		}
+		m.UI.CopyMessage = ""
+		view := renderTablesPaneWithHeight(m, 40, 10)
+		for _, want := range []string{"▾ System tables", "   SYS$IndexStatsLease"} {
+			if !strings.Contains(view, want) {
+				t.Errorf("Expected %q in tables pane:\n%s", want, view)
+			}
+		}
+
+		// Collapsing the group from one of its tables is done on its header
+		m.Tables.CursorTable = m.FindTableIndex("SYS$TableStatsPartition")
+		m = toggleTableGroup(m, systemTablesGroup, true)
+		if m.Tables.CursorTable != -1 || m.Tables.CursorGroup != systemTablesGroup {
+			t.Errorf("cursor = %d/%q, want the system tables header", m.Tables.CursorTable, m.Tables.CursorGroup)
+		}
+		if view := renderTablesPaneWithHeight(m, 40, 10); !strings.Contains(view, "▸ System tables (2)") {
+			t.Errorf("system tables should be collapsed:\n%s", view)
+		}
+
+		// System tables are browsed like other tables
+		m = toggleTableGroup(m, systemTablesGroup, false)
+		m, _ = handleKeyPress(m, tea.KeyMsg{Type: tea.KeyDown})
+		m, _ = handleKeyPress(m, tea.KeyMsg{Type: tea.KeyEnter})
+		if m.SelectedTableName() != "SYS$IndexStatsLease" || m.SQL.CurrentSQL != "SELECT * FROM SYS$IndexStatsLease" {
+			t.Errorf("selected %q with %q", m.SelectedTableName(), m.SQL.CurrentSQL)
+		}
+
+		m.CurrentPane = FocusPaneTables
+		m, _ = handleKeyPress(m, runes("S"))
+		if m.Tables.ShowSystem {
+			t.Error("S should hide the system tables again")
+		}
+	})
+
+	t.Run("selecting a table qualifies the default SQL", func(t *testing.T) {
+		m := newModel()
+		m.Tables.CursorTable = m.FindTableIndex("ns1:users")
+		m.Schema.TableDetails["ns1:users"] = &db.TableDetailsResult{
+			TableName: "ns1:users",
+			Schema:    &nosqldb.TableResult{DDL: "CREATE TABLE ns1:users (id INTEGER, PRIMARY KEY(id))"},
+		}
+		m, _ = handleKeyPress(m, tea.KeyMsg{Type: tea.KeyEnter})
+		if m.SQL.CurrentSQL != "SELECT * FROM ns1:users ORDER BY id" {
+			t.Errorf("CurrentSQL = %q", m.SQL.CurrentSQL)
+		}
+		if m.FindTableIndex("NS1:Users") != m.Tables.SelectedTable || m.FindTableName("sysdefault:orders") != "orders" {
+			t.Error("table names should match case-insensitively and with the sysdefault qualifier")
+		}
+	})
+
+	t.Run("namespace selector cycles and refetches", func(t *testing.T) {
+		m := newModel()
+		if _, cmd := handleKeyPress(m, runes("N")); cmd == nil || m.Tables.Namespace != "" {
+			t.Error("N without namespaces should only show a message")
+		}
+		m, _ = handleNamespaceListResult(m, db.NamespaceListResult{Namespaces: []string{"ns1", "sysdefault"}})
+		if help := getFooterHelp(m); !strings.Contains(help, "Namespace: N") {
+			t.Errorf("footer help = %q", help)
+		}
+
+		var cmd tea.Cmd
+		for _, want := range []string{"ns1", "sysdefault", ""} {
+			m.UI.CopyMessage = ""
+			m, cmd = handleKeyPress(m, runes("N"))
+			if m.Tables.Namespace != want || cmd == nil {
+				t.Errorf("Namespace = %q, want %q (fetch %v)", m.Tables.Namespace, want, cmd != nil)
+			}
+		}
+
+		// A connection with a default namespace cannot list all namespaces
+		m.Connection.Namespace = "ns1"
+		m, _ = handleKeyPress(m, runes("N"))
+		if m.Tables.Namespace != "sysdefault" {
+			t.Errorf("Namespace = %q, want sysdefault", m.Tables.Namespace)
+		}
+		m, _ = handleKeyPress(m, runes("N"))
+		if m.Tables.Namespace != "" {
+			t.Errorf("Namespace = %q, want the connection's default", m.Tables.Namespace)
+		}
+	})
+
+	t.Run("cloud services have no namespaces", func(t *testing.T) {
+		m := newModel()
+		m, _ = handleNamespaceListResult(m, db.NamespaceListResult{Err: errors.New("not supported")})
+		m, _ = handleKeyPress(m, runes("N"))
+		if m.UI.CopyMessage != "Namespaces unavailable: not supported" || strings.Contains(getFooterHelp(newModel()), "Namespace") {
+			t.Errorf("message = %q", m.UI.CopyMessage)
+		}
+	})
+}
+
+func TestTableTree(t *testing.T) {
+	tables := []string{"users.addresses.phones", "products", "users", "users.addresses", "users.orders"}
+	m := InitialModel()
+	m.Window.Width = 120
+	m.Window.Height = 40
+	m.CurrentPane = FocusPaneTables
+	m, _ = handleTableListResult(m, db.TableListResult{Tables: tables})
+	key := func(m Model, k tea.KeyType) Model {
+		m, _ = handleKeyPress(m, tea.KeyMsg{Type: k})
+		return m
+	}
+
+	view := renderTablesPaneWithHeight(m, 40, 8)
+	for _, want := range []string{"    products", "  ▾ users", "    ▾ addresses", "        phones", "      orders"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("Expected %q in tables pane:\n%s", want, view)
+		}
+	}
+
+	// ← on a leaf moves to its parent, then collapses it
+	m.Tables.CursorTable = m.FindTableIndex("users.addresses.phones")
+	m = key(m, tea.KeyLeft)
+	if m.CursorTableName() != "users.addresses" {
+		t.Fatalf("cursor on %q, want users.addresses", m.CursorTableName())
+	}
+	m = key(m, tea.KeyLeft)
+	if !m.Tables.Collapsed["users.addresses"] {
+		t.Fatal("← on an expanded parent should collapse it")
+	}
+	m = key(m, tea.KeyLeft)
+	m = key(m, tea.KeyLeft)
+	view = renderTablesPaneWithHeight(m, 40, 8)
+	if m.CursorTableName() != "users" || !strings.Contains(view, "▸ users (3)") || strings.Contains(view, "orders") {
+		t.Errorf("users should be collapsed with 3 child tables, cursor on %q:\n%s", m.CursorTableName(), view)
+	}
+	if m = key(m, tea.KeyDown); m.CursorTableName() != "users" {
+		t.Errorf("cursor on %q, hidden tables should be skipped", m.CursorTableName())
+	}
+	if help := getFooterHelp(m); !strings.Contains(help, "Expand/Collapse: →/←") {
+		t.Errorf("footer help = %q", help)
+	}
+
+	// Expansion state survives a refresh of the table list
+	m, _ = handleTableListResult(m, db.TableListResult{Tables: append(tables, "users.addresses.tags")})
+	m = key(m, tea.KeyRight)
+	view = renderTablesPaneWithHeight(m, 40, 8)
+	if !strings.Contains(view, "▸ addresses (2)") || !strings.Contains(view, "orders") {
+		t.Errorf("users should be expanded and addresses still collapsed:\n%s", view)
+	}
+
+	// Collapsing the parent of the cursor table moves the cursor to the parent
+	m.Tables.CursorTable = m.FindTableIndex("users.orders")
+	m = toggleTableNode(m, "users", true)
+	if m.CursorTableName() != "users" {
+		t.Errorf("cursor on %q, want the collapsed parent", m.CursorTableName())
+	}
+}
+
+func TestTableFilter(t *testing.T) {
+	newModel := func() Model {
+		m := InitialModel()
+		m.Window.Width = 120
+		m.Window.Height = 40
+		m.CurrentPane = FocusPaneTables
+		m, _ = handleTableListResult(m, db.TableListResult{Tables: []string{
+			"products", "users", "users.addresses", "users.addresses.phones", "orders", "orders.items",
+		}})
+		return m
+	}
+	runes := func(s string) tea.KeyMsg { return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)} }
+
+	t.Run("shows matching tables with their ancestors", func(t *testing.T) {
+		m := newModel()
+		m.Tables.SelectedTable = m.FindTableIndex("orders")
+		m, _ = handleKeyPress(m, runes("/"))
+		m, _ = handleKeyPress(m, runes("uph"))
+		if !m.Tables.Filtering || m.Tables.Filter != "uph" {
+			t.Fatalf("Filtering = %v, Filter = %q", m.Tables.Filtering, m.Tables.Filter)
+		}
+		if m.CursorTableName() != "users.addresses.phones" {
+			t.Errorf("cursor on %q, want the first match", m.CursorTableName())
+		}
+		view := renderTablesPaneWithHeight(m, 40, 8)
+		for _, want := range []string{"Tables /uph (1/6)", "users", "addresses", "phones"} {
+			if !strings.Contains(view, want) {
+				t.Errorf("Expected %q in tables pane:\n%s", want, view)
+			}
+		}
+		for _, hidden := range []string{"products", "orders", "*"} {
+			if strings.Contains(view, hidden) {
+				t.Errorf("%q should be filtered out:\n%s", hidden, view)
+			}
+		}
+		if help := getFooterHelp(m); !strings.Contains(help, "Clear: esc") {
+			t.Errorf("footer help = %q", help)
+		}
+
+		// The cursor moves over the filtered lines only
+		m, _ = handleKeyPress(m, tea.KeyMsg{Type: tea.KeyDown})
+		if m.CursorTableName() != "users.addresses.phones" {
+			t.Errorf("cursor on %q, want it to stay on the last shown table", m.CursorTableName())
+		}
+		m, _ = handleKeyPress(m, tea.KeyMsg{Type: tea.KeyUp})
+		if m.CursorTableName() != "users.addresses" {
+			t.Errorf("cursor on %q, want users.addresses", m.CursorTableName())
+		}
+	})
+
+	t.Run("matches child paths and namespaces", func(t *testing.T) {
+		m := newModel()
+		m.Tables.Filter = "ord.it"
+		lines := tableLines(m)
+		if len(lines) != 2 || m.Tables.Tables[lines[1].Table] != "orders.items" {
+			t.Fatalf("lines = %+v", lines)
+		}
+		if !reflect.DeepEqual(lines[1].Matches, []int{0, 1, 2, 6, 7, 8}) || lines[0].Matches != nil {
+			t.Errorf("Matches = %v and %v", lines[0].Matches, lines[1].Matches)
+		}
+	})
+
+	t.Run("enter selects and the filter stays", func(t *testing.T) {
+		m := newModel()
+		m, _ = handleKeyPress(m, runes("/"))
+		m, _ = handleKeyPress(m, runes("items"))
+		m, _ = handleKeyPress(m, tea.KeyMsg{Type: tea.KeyEnter})
+		if m.Tables.Filtering || m.Tables.Filter != "items" {
+			t.Errorf("Filtering = %v, Filter = %q", m.Tables.Filtering, m.Tables.Filter)
+		}
+		if m.SelectedTableName() != "orders.items" || m.Tables.SelectedTable != m.Tables.CursorTable {
+			t.Errorf("selected %q, cursor %d", m.SelectedTableName(), m.Tables.CursorTable)
+		}
+
+		// Esc clears the filter and keeps the cursor on its table
+		m.CurrentPane = FocusPaneTables
+		m, _ = handleKeyPress(m, tea.KeyMsg{Type: tea.KeyEsc})
+		if m.Tables.Filter != "" || m.CursorTableName() != "orders.items" || len(tableLines(m)) != 6 {
+			t.Errorf("Filter = %q, cursor on %q", m.Tables.Filter, m.CursorTableName())
+		}
+	})
+
+	t.Run("no match leaves the cursor on no table", func(t *testing.T) {
+		m := newModel()
+		m, _ = handleKeyPress(m, runes("/"))
+		m, _ = handleKeyPress(m, runes("zz"))
+		if m.CursorTableName() != "" || !strings.Contains(renderTablesPaneWithHeight(m, 40, 8), "No matching tables") {
+			t.Errorf("cursor on %q", m.CursorTableName())
+		}
+		if m, _ = handleKeyPress(m, tea.KeyMsg{Type: tea.KeyEnter}); m.Tables.SelectedTable != -1 {
+			t.Error("enter without a match should not select a table")
+		}
+		m, _ = handleKeyPress(m, runes("/"))
+		m, _ = handleKeyPress(m, tea.KeyMsg{Type: tea.KeyBackspace})
+		m, _ = handleKeyPress(m, tea.KeyMsg{Type: tea.KeyBackspace})
+		if m.CursorTableName() != "orders" || m.Tables.Filter != "" {
+			t.Errorf("cursor on %q with filter %q", m.CursorTableName(), m.Tables.Filter)
+		}
+		if m, _ = handleKeyPress(m, tea.KeyMsg{Type: tea.KeyBackspace}); m.Tables.Filtering {
+			t.Error("backspace on an empty filter should stop filtering")
+		}
+	})
+}
+
+func TestTableRefresh(t *testing.T) {
+	newModel := func() Model {
+		m := InitialModel()
+		m.Connection.Connected = true
+		m.Connection.NosqlClient = &nosqldb.Client{}
+		m.Window.Width = 120
+		m.Window.Height = 40
+		m.CurrentPane = FocusPaneTables
+		m, _ = handleTableListResult(m, db.TableListResult{Tables: []string{"orders", "products", "users"}})
+		for _, name := range []string{"orders", "users"} {
+			m.Schema.TableDetails[name] = &db.TableDetailsResult{TableName: name, Schema: &nosqldb.TableResult{}}
+			m.Data.TableData[name] = &db.TableDataResult{TableName: name}
+		}
+		m.Tables.SelectedTable = m.FindTableIndex("users")
+		m.Tables.CursorTable = m.FindTableIndex("products")
+		return m
+	}
+	runes := func(s string) tea.KeyMsg { return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)} }
+
+	t.Run("refresh key fetches the table list", func(t *testing.T) {
+		m, cmd := handleKeyPress(newModel(), runes("r"))
+		if !m.Tables.Refreshing || cmd == nil {
+			t.Fatalf("Refreshing = %v, list fetched = %v", m.Tables.Refreshing, cmd != nil)
+		}
+
+		// A table created by another tool shows up and orders was dropped
+		m, cmd = handleTableListResult(m, db.TableListResult{Tables: []string{"audit", "products", "users"}})
+		if m.Tables.Refreshing || cmd == nil {
+			t.Errorf("Refreshing = %v, schemas fetched = %v", m.Tables.Refreshing, cmd != nil)
+		}
+		if m.SelectedTableName() != "users" || m.CursorTableName() != "products" {
+			t.Errorf("selected %q with cursor on %q, want users and products", m.SelectedTableName(), m.CursorTableName())
+		}
+		if _, ok := m.Schema.TableDetails["orders"]; ok {
+			t.Error("schema of the dropped table should be dropped")
+		}
+		if _, ok := m.Data.TableData["orders"]; ok {
+			t.Error("rows of the dropped table should be dropped")
+		}
+		if m.Schema.TableDetails["users"] == nil || m.Data.TableData["users"] == nil {
+			t.Error("caches of the listed tables should stay until they are fetched again")
+		}
+	})
+
+	t.Run("other list fetches keep the caches", func(t *testing.T) {
+		m, _ := handleTableListResult(newModel(), db.TableListResult{Tables: []string{"users"}})
+		if m.Schema.TableDetails["orders"] == nil {
+			t.Error("only a refresh should drop the caches of unlisted tables")
+		}
+	})
+
+	t.Run("failed refresh shows the error", func(t *testing.T) {
+		m, _ := handleKeyPress(newModel(), runes("r"))
+		m, _ = handleTableListResult(m, db.TableListResult{Err: errors.New("timed out")})
+		if m.Tables.Refreshing || m.UI.CopyMessage != "Refresh failed: timed out" || len(m.Tables.Tables) != 3 {
+			t.Errorf("Refreshing = %v, message %q, %d tables", m.Tables.Refreshing, m.UI.CopyMessage, len(m.Tables.Tables))
+		}
+	})
+
+	t.Run("schema and data panes refresh their table", func(t *testing.T) {
+		m := newModel()
+		m.CurrentPane = FocusPaneSchema
+		if _, cmd := handleKeyPress(m, runes("r")); cmd == nil {
+			t.Error("r in the Schema pane should fetch the schema")
+		}
+
+		m.CurrentPane = FocusPaneData
+		m.Data.SelectedDataRow = 3
+		m, cmd := handleKeyPress(m, runes("r"))
+		if cmd == nil || m.Data.SelectedDataRow != 0 {
+			t.Errorf("rows fetched = %v, row %d", cmd != nil, m.Data.SelectedDataRow)
+		}
+
+		// A DML statement is not run again
+		m.SQL.CustomSQL = true
+		m.Data.TableData["users"] = &db.TableDataResult{TableName: "users", CurrentSQL: "SELECT * FROM users"}
+		if _, cmd = handleKeyPress(m, runes("r")); cmd == nil {
+			t.Error("r should run the query again")
+		}
+		delete(m.Data.TableData, "users")
+		if _, cmd = handleKeyPress(m, runes("r")); cmd != nil {
+			t.Error("r after a DML statement should do nothing")
+		}
+	})
+
+	t.Run("refreshes on an interval", func(t *testing.T) {
+		m := newModel()
+		if _, cmd := startAutoRefresh(m); cmd != nil {
+			t.Error("no interval should not schedule a refresh")
+		}
+		m.Tables.RefreshInterval = 30 * time.Second
+		m, cmd := startAutoRefresh(m)
+		if cmd == nil {
+			t.Fatal("interval should schedule a refresh")
+		}
+		if m, cmd = handleRefreshTick(m, refreshTickMsg{Ticker: m.Tables.RefreshTicker}); !m.Tables.Refreshing || cmd == nil {
+			t.Error("tick should fetch the list and schedule the next tick")
+		}
+		if _, cmd = handleRefreshTick(m, refreshTickMsg{Ticker: m.Tables.RefreshTicker - 1}); cmd != nil {
+			t.Error("stale tick should be ignored")
+		}
+
+		m, _ = handleKeyPress(m, tea.KeyMsg{Type: tea.KeyShiftTab})
+		m, _ = handleKeyPress(m, tea.KeyMsg{Type: tea.KeyCtrlD})
+		if _, cmd = handleRefreshTick(m, refreshTickMsg{Ticker: m.Tables.RefreshTicker}); cmd != nil {
+			t.Error("tick after disconnecting should be ignored")
+		}
+	})
+}
+
+func TestReadOnlyConnection(t *testing.T) {
+	newModel := func() Model {
+		m := InitialModel()
+		m.Connection.Connected = true
+		m.Connection.ReadOnly = true
+		m.Window.Height = 40
+		m.Tables.Tables = []string{"users"}
+		m.Tables.SelectedTable = 0
+		m.Data.TableData["users"] = &db.TableDataResult{
+			TableName: "users",
+			Rows:      []map[string]interface{}{{"id": float64(1)}},
+		}
+		return m
+	}
+	runes := func(s string) tea.KeyMsg { return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)} }
+
+	t.Run("SQL pane refuses DML", func(t *testing.T) {
+		m := newModel()
+		m.CurrentPane = FocusPaneSQL
+		m.SQL.CurrentSQL = "DELETE FROM users"
+
+		m, _ = handleKeyPress(m, tea.KeyMsg{Type: tea.KeyCtrlR})
+		if m.SQL.CustomSQL || m.Data.LoadingData {
+			t.Errorf("CustomSQL = %v, LoadingData = %v", m.SQL.CustomSQL, m.Data.LoadingData)
+		}
+		if !strings.Contains(m.UI.CopyMessage, "DELETE statements are not allowed") {
+			t.Errorf("CopyMessage = %q", m.UI.CopyMessage)
+		}
+	})
+
+	t.Run("Data pane refuses writes", func(t *testing.T) {
+		for _, key := range []tea.KeyMsg{runes("e"), runes("n"), runes("d"), {Type: tea.KeySpace}} {
+			m := newModel()
+			m.CurrentPane = FocusPaneData
+
+			m, _ = handleKeyPress(m, key)
+			if m.RowEditor.Visible || m.DeleteDialog.Visible || len(m.Data.Marked) != 0 {
+				t.Errorf("key %q opened a write dialog", key)
+			}
+			if m.UI.CopyMessage != readOnlyRefusal {
+				t.Errorf("key %q: CopyMessage = %q", key, m.UI.CopyMessage)
+			}
+		}
+	})
+
+	t.Run("footer hides write keys", func(t *testing.T) {
+		m := newModel()
+		m.CurrentPane = FocusPaneData
+		if got := getFooterHelp(m); strings.Contains(got, "Edit") || strings.Contains(got, "Delete") {
+			t.Errorf("footer = %q", got)
+		}
+	})
+}
+
+func TestExportHasMore(t *testing.T) {
+	tests := []struct {
+		name   string
+		cursor db.TableDataResult
+		want   bool
+	}{
+		{name: "last page", cursor: db.TableDataResult{HasMore: false, LastPKValues: map[string]interface{}{"id": 1}}},
+		{name: "primary key cursor", cursor: db.TableDataResult{HasMore: true, LastPKValues: map[string]interface{}{"id": 1}}, want: true},
+		{name: "no primary key cursor", cursor: db.TableDataResult{HasMore: true}},
+		{name: "custom SQL offset", cursor: db.TableDataResult{HasMore: true, IsCustomSQL: true, CurrentSQL: "SELECT * FROM users"}, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := exportHasMore(tt.cursor); got != tt.want {
+				t.Errorf("exportHasMore() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -166,10 +166,7 @@ func fetchMoreDataIfNeeded(m Model, shouldFetch bool) tea.Cmd {
 
 	// Standard queries use PRIMARY KEY cursor pagination
 	if data.LastPKValues != nil {
-		var primaryKeys []string
-		if details := m.GetSelectedTableDetails(); details != nil && details.Schema != nil && details.Schema.DDL != "" {
-			primaryKeys = ui.ParsePrimaryKeysFromDDL(details.Schema.DDL)
-		}
+		primaryKeys := tablePrimaryKeys(m, tableName)
 		return db.FetchMoreTableData(m.Connection.NosqlClient, tableName, ui.DefaultFetchSize, primaryKeys, data.LastPKValues)
 	}
 
@@ -250,15 +247,22 @@ func calculateRecordDetailMaxScroll(m Model) int {
 }
 
 // buildDefaultSQL generates the default SELECT statement for a table.
-// If primary keys are available from DDL, adds ORDER BY clause.
-func buildDefaultSQL(tableName string, ddl string) string {
-	sql := "SELECT * FROM " + tableName
-	if ddl != "" {
-		primaryKeys := ui.ParsePrimaryKeysFromDDL(ddl)
-		if len(primaryKeys) > 0 {
-			sql += " ORDER BY " + strings.Join(primaryKeys, ", ")
+// The table name is qualified with its namespace ("ns1:users.phones") when it has
+// one; tables of the sysdefault namespace are qualified only when the connection
+// has a default namespace (defaultNamespace) that would otherwise apply.
+// If primary keys are available from the schema, adds ORDER BY clause.
+func buildDefaultSQL(tableName, defaultNamespace string, primaryKeys []string) string {
+	namespace, name := db.SplitTableName(tableName)
+	if namespace == "" || strings.EqualFold(namespace, db.DefaultNamespace) {
+		namespace = ""
+		if defaultNamespace != "" && !strings.EqualFold(defaultNamespace, db.DefaultNamespace) {
+			namespace = db.DefaultNamespace
 		}
 	}
+	sql := "SELECT * FROM " + db.QualifyTableName(namespace, name)
+	if len(primaryKeys) > 0 {
+		sql += " ORDER BY " + strings.Join(primaryKeys, ", ")
+	}
 	return sql
 }
 
@@ -324,26 +328,8 @@ func calculateSchemaContentLineCount(m Model, schemaTableName string) int {
 
 	lineCount := 1 // "Columns:"
 
-	// Count inherited primary key columns from ancestors
-	ancestors := ui.GetAncestorTableNames(schemaTableName)
-	for _, ancestorName := range ancestors {
-		if ancestorDetails, ancestorExists := m.Schema.TableDetails[ancestorName]; ancestorExists && ancestorDetails != nil && ancestorDetails.Schema != nil && ancestorDetails.Schema.DDL != "" {
-			ancestorPKs := ui.ParsePrimaryKeysFromDDL(ancestorDetails.Schema.DDL)
-			ancestorCols := ui.ParseColumnsFromDDL(ancestorDetails.Schema.DDL, ancestorPKs)
-			for _, col := range ancestorCols {
-				if col.IsPrimaryKey {
-					lineCount++
-				}
-			}
-		}
-	}
-
-	// Count this table's own columns
-	if details.Schema != nil && details.Schema.DDL != "" {
-		primaryKeys := ui.ParsePrimaryKeysFromDDL(details.Schema.DDL)
-		columns := ui.ParseColumnsFromDDL(details.Schema.DDL, primaryKeys)
-		lineCount += len(columns)
-	}
+	// Count the column tree: inherited key columns, own columns and expanded nodes
+	lineCount += len(schemaTreeNodes(m, schemaTableName))
 
 	lineCount += 2 // Empty line + "Indexes:"
 	lineCount += len(details.Indexes)
@@ -351,6 +337,11 @@ func calculateSchemaContentLineCount(m Model, schemaTableName string) int {
 		lineCount++ // "(none)" line
 	}
 
+	lineCount += 2 // Empty line + "Table info:"
+	if m.Schema.InfoExpanded {
+		lineCount += len(tableInfoLines(m, schemaTableName))
+	}
+
 	return lineCount
 }
 
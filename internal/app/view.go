@@ -1,6 +1,7 @@
 package app
 
 import (
+	"fmt"
 	"strings"
 
 	"github.com/charmbracelet/lipgloss"
@@ -69,6 +70,44 @@ func RenderView(m Model) string {
 		return renderConnectionDialog(m)
 	}
 
+	// Overlay profile picker if visible
+	if m.ProfilePicker.Visible {
+		return renderProfilePicker(m)
+	}
+
+	// Overlay export dialog if visible
+	if m.ExportDialog.Visible {
+		return renderExportDialog(m)
+	}
+
+	// Overlay import dialog if visible
+	if m.ImportDialog.Visible {
+		return renderImportDialog(m)
+	}
+
+	// Overlay table designer if visible
+	if m.TableDesigner.Visible {
+		return renderTableDesigner(m)
+	}
+
+	// Overlay index dialogs if visible
+	if m.IndexDialog.Visible {
+		return renderIndexDialog(m)
+	}
+	if m.IndexDetail.Visible {
+		return renderIndexDetail(m)
+	}
+
+	// Overlay delete confirmation if visible
+	if m.DeleteDialog.Visible {
+		return renderDeleteDialog(m)
+	}
+
+	// Overlay row editor if visible
+	if m.RowEditor.Visible {
+		return renderRowEditor(m)
+	}
+
 	// Overlay record detail dialog if visible
 	if m.RecordDetail.Visible {
 		return renderRecordDetailDialog(m)
@@ -94,16 +133,58 @@ func getFooterHelp(m Model) string {
 		if m.Connection.Connected {
 			return "Disconnect: ctrl+d"
 		}
-		return "Setup: <enter>"
+		return "Setup: <enter> | Profiles: p"
 	case FocusPaneTables:
-		return "Select: <enter>"
+		if m.Tables.Filtering {
+			return "Filter: type to match | Select: <enter> | Clear: esc"
+		}
+		var parts []string
+		lines := tableLines(m)
+		if pos := tableCursorLine(m, lines); pos >= 0 && lines[pos].Table < 0 {
+			parts = append(parts, "Expand/Collapse: <enter>")
+		} else {
+			parts = append(parts, "Select: <enter>")
+			if pos >= 0 && lines[pos].Children > 0 {
+				parts = append(parts, "Expand/Collapse: →/←")
+			}
+			if !m.Connection.ReadOnly {
+				parts = append(parts, "New table: n", "Child table: c", "Import: i")
+			}
+		}
+		parts = append(parts, "Refresh: r", "System tables: S")
+		if len(m.Tables.Namespaces) > 0 {
+			parts = append(parts, "Namespace: N")
+		}
+		parts = append(parts, "Filter: /")
+		if m.Tables.Filter != "" {
+			parts = append(parts, "Clear filter: esc")
+		}
+		return strings.Join(parts, " | ")
+	case FocusPaneSchema:
+		return schemaFooterHelp(m)
 	case FocusPaneSQL:
 		return "Execute: ctrl+r"
 	case FocusPaneData:
+		if m.Data.Statement != nil {
+			if m.SQL.CustomSQL {
+				return "Reset: esc"
+			}
+			return ""
+		}
 		if m.SQL.CustomSQL {
-			return "Copy: ctrl+c | Detail: <enter> | Reset: esc"
+			return "Copy: ctrl+c | Export: ctrl+s | Detail: <enter> | Refresh: r | Reset: esc"
+		}
+		if m.Connection.ReadOnly {
+			return "Copy: ctrl+c | Export: ctrl+s | Detail: <enter> | Refresh: r"
+		}
+		help := "Copy: ctrl+c | Export: ctrl+s | Detail: <enter> | Refresh: r | Edit: e | New: n | Mark: <space> | Delete: d"
+		if marked := len(markedRowSet(m)); marked > 0 {
+			help = fmt.Sprintf("Mark: <space> | Delete %d marked: d | Unmark: esc", marked)
+		}
+		if len(m.Data.Undo) > 0 {
+			help += " | Undo: u"
 		}
-		return "Copy: ctrl+c | Detail: <enter>"
+		return help
 	}
 	return ""
 }
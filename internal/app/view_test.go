@@ -1,9 +1,16 @@
 package app
 
 import (
+	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/charmbracelet/lipgloss"
+	"github.com/oracle/nosql-go-sdk/nosqldb"
+
+	"github.com/camikura/dito/internal/config"
+	"github.com/camikura/dito/internal/db"
+	"github.com/camikura/dito/internal/importer"
 )
 
 func TestGetFooterHelp(t *testing.T) {
@@ -15,7 +22,7 @@ func TestGetFooterHelp(t *testing.T) {
 		{
 			name:     "Connection pane not connected",
 			model:    Model{CurrentPane: FocusPaneConnection, Connection: ConnectionState{Connected: false}},
-			expected: "Setup: <enter>",
+			expected: "Setup: <enter> | Profiles: p",
 		},
 		{
 			name:     "Connection pane connected",
@@ -25,7 +32,7 @@ func TestGetFooterHelp(t *testing.T) {
 		{
 			name:     "Tables pane",
 			model:    Model{CurrentPane: FocusPaneTables},
-			expected: "Select: <enter>",
+			expected: "Select: <enter> | New table: n | Child table: c | Import: i | Refresh: r | System tables: S | Filter: /",
 		},
 		{
 			name:     "SQL pane",
@@ -40,12 +47,12 @@ func TestGetFooterHelp(t *testing.T) {
 		{
 			name:     "Data pane normal",
 			model:    Model{CurrentPane: FocusPaneData, SQL: SQLState{CustomSQL: false}},
-			expected: "Copy: ctrl+c | Detail: <enter>",
+			expected: "Copy: ctrl+c | Export: ctrl+s | Detail: <enter> | Refresh: r | Edit: e | New: n | Mark: <space> | Delete: d",
 		},
 		{
 			name:     "Data pane custom SQL",
 			model:    Model{CurrentPane: FocusPaneData, SQL: SQLState{CustomSQL: true}},
-			expected: "Copy: ctrl+c | Detail: <enter> | Reset: esc",
+			expected: "Copy: ctrl+c | Export: ctrl+s | Detail: <enter> | Refresh: r | Reset: esc",
 		},
 		{
 			name:     "Copy message shown",
@@ -234,3 +241,332 @@ func TestFooterWidthConsistency(t *testing.T) {
 		}
 	}
 }
+
+func TestRenderConnectionDialog(t *testing.T) {
+	t.Run("password is masked", func(t *testing.T) {
+		m := InitialModel()
+		m.Window.Width = 120
+		m.Window.Height = 40
+		m.ConnectionDialog.Visible = true
+		m.ConnectionDialog.EditUsername = "admin"
+		m.ConnectionDialog.EditPassword = "secret"
+
+		result := renderConnectionDialog(m)
+
+		if strings.Contains(result, "secret") {
+			t.Error("Password should not be rendered in plain text")
+		}
+		if !strings.Contains(result, "******") {
+			t.Error("Expected masked password in output")
+		}
+		if !strings.Contains(result, "admin") {
+			t.Error("Expected username in output")
+		}
+	})
+
+	t.Run("lines have consistent width", func(t *testing.T) {
+		m := InitialModel()
+		m.Window.Width = 120
+		m.Window.Height = 40
+		m.ConnectionDialog.Visible = true
+		m.ConnectionDialog.Field = 2 // Focused checkbox
+
+		result := renderConnectionDialog(m)
+
+		for _, line := range strings.Split(result, "\n") {
+			if w := lipgloss.Width(line); w != 0 && w != m.Window.Width {
+				t.Errorf("line width = %d, want %d: %q", w, m.Window.Width, line)
+			}
+		}
+	})
+
+	t.Run("profile editor shows password references", func(t *testing.T) {
+		m := InitialModel()
+		m.Window.Width = 120
+		m.Window.Height = 40
+		m = openProfileEditor(m, -1)
+		m.ConnectionDialog.EditPasswordEnv = "KV_PASSWORD"
+		m.ConnectionDialog.Message = "profile name is required"
+
+		result := renderConnectionDialog(m)
+
+		for _, want := range []string{"New Profile", "Password Env", "KV_PASSWORD", "Password File", "profile name is required"} {
+			if !strings.Contains(result, want) {
+				t.Errorf("Expected %q in output", want)
+			}
+		}
+		for _, line := range strings.Split(result, "\n") {
+			if w := lipgloss.Width(line); w != 0 && w != m.Window.Width {
+				t.Errorf("line width = %d, want %d: %q", w, m.Window.Width, line)
+			}
+		}
+	})
+}
+
+func TestRenderProfilePicker(t *testing.T) {
+	newModel := func() Model {
+		m := InitialModel()
+		m.Window.Width = 120
+		m.Window.Height = 40
+		m.Profiles.Items = []config.Profile{
+			{Name: "local", Endpoint: "localhost", Port: "8080"},
+			{Name: "oci", Edition: db.EditionCloud, Region: "us-ashburn-1"},
+		}
+		m.Profiles.Active = "oci"
+		return openProfilePicker(m)
+	}
+
+	t.Run("lists profiles with summary", func(t *testing.T) {
+		result := renderProfilePicker(newModel())
+
+		if !strings.Contains(result, "local") || !strings.Contains(result, "On-Premise: localhost:8080") {
+			t.Error("Expected local profile with summary")
+		}
+		if !strings.Contains(result, "* oci") {
+			t.Error("Expected active profile marker")
+		}
+	})
+
+	t.Run("shows delete confirmation", func(t *testing.T) {
+		m := newModel()
+		m.ProfilePicker.ConfirmDelete = true
+
+		result := renderProfilePicker(m)
+
+		if !strings.Contains(result, "Delete oci? (y/n)") {
+			t.Error("Expected delete confirmation")
+		}
+	})
+
+	t.Run("lines have consistent width", func(t *testing.T) {
+		for _, renaming := range []bool{false, true} {
+			m := newModel()
+			m.ProfilePicker.Renaming = renaming
+			m.ProfilePicker.EditName = "oci"
+
+			result := renderProfilePicker(m)
+
+			for _, line := range strings.Split(result, "\n") {
+				if w := lipgloss.Width(line); w != 0 && w != m.Window.Width {
+					t.Errorf("line width = %d, want %d: %q", w, m.Window.Width, line)
+				}
+			}
+		}
+	})
+
+	t.Run("empty list", func(t *testing.T) {
+		m := InitialModel()
+		m.Window.Width = 120
+		m.Window.Height = 40
+		m = openProfilePicker(m)
+
+		if !strings.Contains(renderProfilePicker(m), "(no saved profiles)") {
+			t.Error("Expected empty list message")
+		}
+	})
+}
+
+func TestRenderExportDialog(t *testing.T) {
+	newModel := func() Model {
+		m := InitialModel()
+		m.Window.Width = 120
+		m.Window.Height = 40
+		m.Tables.Tables = []string{"users"}
+		m.Tables.SelectedTable = 0
+		m.Data.TableData["users"] = &db.TableDataResult{TableName: "users"}
+		return openExportDialog(m)
+	}
+
+	t.Run("shows path and format", func(t *testing.T) {
+		result := renderExportDialog(newModel())
+
+		if !strings.Contains(result, "Export users") || !strings.Contains(result, "users.csv") || !strings.Contains(result, "< CSV >") {
+			t.Error("Expected title, path and format")
+		}
+	})
+
+	t.Run("shows progress while exporting", func(t *testing.T) {
+		m := newModel()
+		m.ExportDialog.Job = &exportJob{}
+		m.ExportDialog.Rows = 1200
+
+		result := renderExportDialog(m)
+
+		if !strings.Contains(result, "Exporting... 1200 rows written") || !strings.Contains(result, "Cancel: esc") {
+			t.Error("Expected progress and cancel help")
+		}
+	})
+
+	t.Run("lines have consistent width", func(t *testing.T) {
+		result := renderExportDialog(newModel())
+
+		for _, line := range strings.Split(result, "\n") {
+			if w := lipgloss.Width(line); w != 0 && w != 120 {
+				t.Errorf("line width = %d, want 120: %q", w, line)
+			}
+		}
+	})
+}
+
+func TestRenderImportDialog(t *testing.T) {
+	newModel := func() Model {
+		m := InitialModel()
+		m.Window.Width = 120
+		m.Window.Height = 40
+		m.Connection.NosqlClient = &nosqldb.Client{}
+		m.Tables.Tables = []string{"users"}
+		return openImportDialog(m)
+	}
+
+	t.Run("shows fields", func(t *testing.T) {
+		result := renderImportDialog(newModel())
+
+		for _, want := range []string{"Import into users", "users.csv", "< Upsert >", "users.rejects.jsonl"} {
+			if !strings.Contains(result, want) {
+				t.Errorf("Expected %q in dialog", want)
+			}
+		}
+	})
+
+	t.Run("shows progress while importing", func(t *testing.T) {
+		m := newModel()
+		m.ImportDialog.Job = &importJob{}
+		m.ImportDialog.Stats = importer.Stats{Written: 1000, Rejected: 3}
+
+		result := renderImportDialog(m)
+
+		if !strings.Contains(result, "Importing... 1000 rows written, 3 rejected") || !strings.Contains(result, "Cancel: esc") {
+			t.Error("Expected progress and cancel help")
+		}
+	})
+
+	t.Run("lines have consistent width", func(t *testing.T) {
+		result := renderImportDialog(newModel())
+
+		for _, line := range strings.Split(result, "\n") {
+			if w := lipgloss.Width(line); w != 0 && w != 120 {
+				t.Errorf("line width = %d, want 120: %q", w, line)
+			}
+		}
+	})
+}
+
+func TestRenderRowEditor(t *testing.T) {
+	schema, err := importer.NewSchema("users", "CREATE TABLE users (id INTEGER, name STRING, active BOOLEAN, PRIMARY KEY(id))", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	newModel := func() Model {
+		m := InitialModel()
+		m.Window.Width = 120
+		m.Window.Height = 40
+		m.RowEditor = RowEditorState{
+			Visible:   true,
+			TableName: "users",
+			Schema:    schema,
+			Field:     1,
+			Fields: []RowEditorField{
+				{Column: schema.Columns[0], Text: "1", Original: 1},
+				{Column: schema.Columns[1], Text: "Alice", Original: "Alice"},
+				{Column: schema.Columns[2], Null: true},
+			},
+		}
+		return m
+	}
+
+	t.Run("shows fields with types", func(t *testing.T) {
+		result := renderRowEditor(newModel())
+
+		for _, want := range []string{"Edit users", "INTEGER", "Alice", "BOOLEAN", "(null)", "Save: <enter>"} {
+			if !strings.Contains(result, want) {
+				t.Errorf("Expected %q in editor", want)
+			}
+		}
+	})
+
+	t.Run("shows conflict", func(t *testing.T) {
+		m := newModel()
+		m.RowEditor.Conflict = true
+		m.RowEditor.Message = "Conflict: the row was changed"
+
+		result := renderRowEditor(m)
+
+		if !strings.Contains(result, "Conflict: the row was changed") || !strings.Contains(result, "Reload: ctrl+r") {
+			t.Error("Expected conflict message and reload help")
+		}
+	})
+
+	t.Run("new row form", func(t *testing.T) {
+		m := newModel()
+		m.RowEditor.Insert = true
+		m.RowEditor.Field = 0
+		m.RowEditor.Fields[0] = RowEditorField{Column: schema.Columns[0], Null: true}
+
+		result := renderRowEditor(m)
+
+		for _, want := range []string{"New row in users", "id*", "(required)", "Insert: <enter>"} {
+			if !strings.Contains(result, want) {
+				t.Errorf("Expected %q in form", want)
+			}
+		}
+	})
+
+	t.Run("multi-line JSON field", func(t *testing.T) {
+		jsonSchema, err := importer.NewSchema("docs", "CREATE TABLE docs (id INTEGER, doc JSON, PRIMARY KEY(id))", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		m := newModel()
+		m.RowEditor.Schema = jsonSchema
+		m.RowEditor.Fields = []RowEditorField{
+			{Column: jsonSchema.Columns[0], Text: "1", Original: 1},
+			{Column: jsonSchema.Columns[1], Text: "{\n  \"a\": 1\n}"},
+		}
+		m.RowEditor.CursorPos = 3
+
+		result := renderRowEditor(m)
+
+		for _, want := range []string{`"a": 1`, "}", "Save: ctrl+s"} {
+			if !strings.Contains(result, want) {
+				t.Errorf("Expected %q in editor", want)
+			}
+		}
+		for _, line := range strings.Split(result, "\n") {
+			if w := lipgloss.Width(line); w != 0 && w != 120 {
+				t.Errorf("line width = %d, want 120: %q", w, line)
+			}
+		}
+	})
+
+	t.Run("lines have consistent width", func(t *testing.T) {
+		result := renderRowEditor(newModel())
+
+		for _, line := range strings.Split(result, "\n") {
+			if w := lipgloss.Width(line); w != 0 && w != 120 {
+				t.Errorf("line width = %d, want 120: %q", w, line)
+			}
+		}
+	})
+}
+
+func TestRenderDeleteDialog(t *testing.T) {
+	m := InitialModel()
+	m.Window.Width = 120
+	m.Window.Height = 40
+	m.DeleteDialog = DeleteDialogState{Visible: true, TableName: "users"}
+	for i := 1; i <= deleteDialogVisibleKeys+2; i++ {
+		m.DeleteDialog.Rows = append(m.DeleteDialog.Rows, i-1)
+		m.DeleteDialog.Labels = append(m.DeleteDialog.Labels, fmt.Sprintf("id=%d", i))
+	}
+
+	result := renderDeleteDialog(m)
+
+	for _, want := range []string{"Delete from users", "Delete 12 rows", "id=1", "id=10", "... 2 more", "Delete: y"} {
+		if !strings.Contains(result, want) {
+			t.Errorf("Expected %q in dialog", want)
+		}
+	}
+	if strings.Contains(result, "id=11") {
+		t.Error("Expected keys beyond the visible list to be hidden")
+	}
+}
@@ -0,0 +1,159 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/camikura/dito/internal/db"
+	"github.com/camikura/dito/internal/importer"
+)
+
+// importStepRecords is the number of records processed between cancellation checks
+const importStepRecords = 500
+
+// RunImport implements "dito import": it loads the rows of a JSON Lines or CSV
+// file (or stdin with "-") into a table. Records that cannot be converted or
+// written are recorded in a rejects file. It returns the process exit code.
+func RunImport(ctx context.Context, args []string, stdin io.Reader, stdout, stderr io.Writer, getenv func(string) string) int {
+	var opts Options
+	fs := flag.NewFlagSet("dito import", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	opts.RegisterFlags(fs, getenv)
+	tableName := fs.String("table", "", "Table to import into (required)")
+	formatName := fs.String("format", "", "Input format: jsonl or csv (default: from the file extension)")
+	modeName := fs.String("mode", "upsert", "Write mode: upsert or insert (existing rows are rejected)")
+	rejectsPath := fs.String("rejects", "", "File for rejected records (default: <file>.rejects.jsonl)")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: dito import --table <name> [flags] <file | ->")
+		fmt.Fprintln(stderr)
+		fmt.Fprintln(stderr, "Imports rows from a JSON Lines or CSV file into a table.")
+		fmt.Fprintln(stderr, "Use - to read from stdin (requires --format).")
+		fmt.Fprintln(stderr)
+		fmt.Fprintln(stderr, "Flags:")
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return ExitOK
+		}
+		return ExitUsage
+	}
+
+	usageError := func(format string, a ...interface{}) int {
+		fmt.Fprintf(stderr, "Error: "+format+"\n", a...)
+		return ExitUsage
+	}
+
+	if fs.NArg() != 1 {
+		return usageError("expected exactly one file argument (or - for stdin)")
+	}
+	path := fs.Arg(0)
+	if *tableName == "" {
+		return usageError("--table is required")
+	}
+	mode, err := importer.ParseMode(*modeName)
+	if err != nil {
+		return usageError("%v", err)
+	}
+	var format importer.Format
+	switch {
+	case *formatName != "":
+		format, err = importer.ParseFormat(*formatName)
+	case path == "-":
+		err = errors.New("--format is required when reading from stdin")
+	default:
+		format, err = importer.FormatForPath(path)
+	}
+	if err != nil {
+		return usageError("%v", err)
+	}
+	if *rejectsPath == "" {
+		*rejectsPath = importer.DefaultRejectsPath(path)
+	}
+
+	input := stdin
+	if path != "-" {
+		file, err := os.Open(path)
+		if err != nil {
+			fmt.Fprintf(stderr, "Error: %v\n", err)
+			return ExitError
+		}
+		defer file.Close()
+		input = file
+	}
+	reader, err := importer.NewReader(input, format)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		return ExitError
+	}
+
+	cfg, code := resolveConnection(opts, stderr)
+	if code != ExitOK {
+		return code
+	}
+	if cfg.ReadOnly {
+		fmt.Fprintln(stderr, "Error: read-only connection: import is not allowed")
+		return ExitError
+	}
+	client, code := connect(cfg, stderr)
+	if client == nil {
+		return code
+	}
+	defer client.Close()
+
+	table, ancestors, err := db.GetTableSchemas(client, *tableName)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		return ExitError
+	}
+	schema, err := importer.NewSchemaFromTables(*tableName, table, ancestors)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		return ExitError
+	}
+
+	im := importer.New(schema, reader, importer.ClientWriter(client, *tableName, mode),
+		importer.Options{RejectsPath: *rejectsPath})
+	err = runImport(ctx, im)
+	if closeErr := im.Close(); err == nil {
+		err = closeErr
+	}
+
+	stats := im.Stats()
+	fmt.Fprintf(stdout, "Imported %d rows into %s", stats.Written, *tableName)
+	if stats.Rejected > 0 {
+		fmt.Fprintf(stdout, ", %d rejected (see %s)", stats.Rejected, *rejectsPath)
+	}
+	fmt.Fprintln(stdout)
+
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		return ExitError
+	}
+	if stats.Rejected > 0 {
+		return ExitRejected
+	}
+	return ExitOK
+}
+
+// runImport steps the importer to the end of the input or until ctx is cancelled.
+// On cancellation the rows not yet written are rejected.
+func runImport(ctx context.Context, im *importer.Importer) error {
+	for {
+		if err := ctx.Err(); err != nil {
+			if cancelErr := im.Cancel(); cancelErr != nil {
+				return cancelErr
+			}
+			return fmt.Errorf("import interrupted: %w", err)
+		}
+		done, err := im.Step(importStepRecords)
+		if err != nil || done {
+			return err
+		}
+	}
+}
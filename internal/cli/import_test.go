@@ -0,0 +1,101 @@
+package cli
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRunImportUsageErrors(t *testing.T) {
+	env := map[string]string{"DITO_ENDPOINT": "localhost:8080"}
+
+	tests := []struct {
+		name string
+		args []string
+		env  map[string]string
+		want string
+	}{
+		{name: "missing file", args: []string{"--table", "users"}, env: env, want: "expected exactly one file"},
+		{name: "missing table", args: []string{"users.csv"}, env: env, want: "--table is required"},
+		{name: "unknown mode", args: []string{"--table", "users", "--mode", "merge", "users.csv"}, env: env, want: "unknown mode"},
+		{name: "unknown extension", args: []string{"--table", "users", "users.txt"}, env: env, want: "unknown format"},
+		{name: "stdin without format", args: []string{"--table", "users", "-"}, env: env, want: "--format is required"},
+		{name: "no connection", args: []string{"--table", "users", "--format", "jsonl", "-"}, want: "no connection given"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr strings.Builder
+			getenv := func(key string) string { return tt.env[key] }
+
+			code := RunImport(context.Background(), tt.args, strings.NewReader(""), &stdout, &stderr, getenv)
+			if code != ExitUsage {
+				t.Errorf("exit code = %d, want %d", code, ExitUsage)
+			}
+			if !strings.Contains(stderr.String(), tt.want) {
+				t.Errorf("stderr = %q, want it to contain %q", stderr.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestRunImportHelp(t *testing.T) {
+	var stdout, stderr strings.Builder
+	code := RunImport(context.Background(), []string{"-h"}, strings.NewReader(""), &stdout, &stderr, func(string) string { return "" })
+	if code != ExitOK {
+		t.Errorf("exit code = %d, want %d", code, ExitOK)
+	}
+	if !strings.Contains(stderr.String(), "Usage: dito import") {
+		t.Errorf("stderr = %q, want usage", stderr.String())
+	}
+}
+
+func TestRunImportErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Reject with a non-retryable error so the client fails fast
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte("stand-in server"))
+	}))
+	defer server.Close()
+	getenv := func(string) string { return "" }
+
+	t.Run("missing file", func(t *testing.T) {
+		var stdout, stderr strings.Builder
+		path := filepath.Join(t.TempDir(), "missing.csv")
+		code := RunImport(context.Background(), []string{"--endpoint", server.URL, "--table", "users", path}, strings.NewReader(""), &stdout, &stderr, getenv)
+		if code != ExitError {
+			t.Errorf("exit code = %d, want %d", code, ExitError)
+		}
+	})
+
+	t.Run("read-only connection", func(t *testing.T) {
+		var stdout, stderr strings.Builder
+		path := filepath.Join(t.TempDir(), "users.jsonl")
+		if err := os.WriteFile(path, []byte(`{"id":1}`+"\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		code := RunImport(context.Background(), []string{"--endpoint", server.URL, "--read-only", "--table", "users", path}, strings.NewReader(""), &stdout, &stderr, getenv)
+		if code != ExitError || !strings.Contains(stderr.String(), "read-only connection") {
+			t.Errorf("exit code = %d, stderr = %q", code, stderr.String())
+		}
+	})
+
+	t.Run("server error", func(t *testing.T) {
+		var stdout, stderr strings.Builder
+		path := filepath.Join(t.TempDir(), "users.jsonl")
+		if err := os.WriteFile(path, []byte(`{"id":1}`+"\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		code := RunImport(context.Background(), []string{"--endpoint", server.URL, "--table", "users", path}, strings.NewReader(""), &stdout, &stderr, getenv)
+		if code != ExitError {
+			t.Errorf("exit code = %d, want %d", code, ExitError)
+		}
+		if !strings.Contains(stderr.String(), "stand-in server") {
+			t.Errorf("stderr = %q, want the server error", stderr.String())
+		}
+	})
+}
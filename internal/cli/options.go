@@ -0,0 +1,208 @@
+// Package cli parses command-line flags and DITO_* environment variables.
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/camikura/dito/internal/config"
+	"github.com/camikura/dito/internal/db"
+)
+
+// defaultPort is used when an on-premise or Cloud Simulator endpoint has no port.
+const defaultPort = "8080"
+
+// Options holds connection and startup options.
+// Each option can be given as a flag or as a DITO_* environment variable;
+// flags take precedence over the environment, which takes precedence over a saved profile.
+type Options struct {
+	Profile  string
+	Edition  string
+	Endpoint string // host, host:port or URL
+
+	// Secure on-premise store
+	HTTPS              bool
+	CACert             string
+	InsecureSkipVerify bool
+	Username           string
+	Password           string // Only from DITO_PASSWORD so it does not show up in the process list
+	PasswordFile       string
+
+	// Cloud Service
+	OCIConfigFile string
+	OCIProfile    string
+	Region        string
+	Compartment   string
+
+	// Cloud Simulator
+	TenantID string
+
+	Namespace string
+	ReadOnly  bool // Refuse statements and actions that change data or schema
+
+	// Startup actions
+	Table   string        // Table to select after connecting
+	SQL     string        // SQL to prefill in the SQL pane
+	Refresh time.Duration // Interval of the automatic table list refresh, 0 for off
+}
+
+// RegisterFlags registers the connection flags on fs, using DITO_* environment
+// variables read through getenv as defaults.
+func (o *Options) RegisterFlags(fs *flag.FlagSet, getenv func(string) string) {
+	stringVar := func(p *string, name, env, usage string) {
+		fs.StringVar(p, name, getenv(env), usage+" (env "+env+")")
+	}
+	boolVar := func(p *bool, name, env, usage string) {
+		value, _ := strconv.ParseBool(getenv(env))
+		fs.BoolVar(p, name, value, usage+" (env "+env+")")
+	}
+
+	stringVar(&o.Profile, "profile", "DITO_PROFILE", "Saved connection profile name")
+	stringVar(&o.Edition, "edition", "DITO_EDITION", "Edition: onprem, cloud or cloudsim")
+	stringVar(&o.Endpoint, "endpoint", "DITO_ENDPOINT", "Endpoint as host:port or URL")
+	boolVar(&o.HTTPS, "https", "DITO_HTTPS", "Connect over HTTPS/TLS")
+	stringVar(&o.CACert, "ca-cert", "DITO_CA_CERT", "PEM CA certificate bundle")
+	boolVar(&o.InsecureSkipVerify, "insecure-skip-verify", "DITO_INSECURE_SKIP_VERIFY", "Skip server certificate verification")
+	stringVar(&o.Username, "username", "DITO_USERNAME", "KVStore username")
+	stringVar(&o.PasswordFile, "password-file", "DITO_PASSWORD_FILE", "File containing the KVStore password")
+	stringVar(&o.OCIConfigFile, "oci-config", "DITO_OCI_CONFIG", "OCI config file")
+	stringVar(&o.OCIProfile, "oci-profile", "DITO_OCI_PROFILE", "OCI config profile")
+	stringVar(&o.Region, "region", "DITO_REGION", "Cloud region, e.g. us-ashburn-1")
+	stringVar(&o.Compartment, "compartment", "DITO_COMPARTMENT", "Cloud compartment OCID or path")
+	stringVar(&o.TenantID, "tenant-id", "DITO_TENANT_ID", "Cloud Simulator tenant ID")
+	stringVar(&o.Namespace, "namespace", "DITO_NAMESPACE", "Default namespace for on-premise stores")
+	boolVar(&o.ReadOnly, "read-only", "DITO_READ_ONLY", "Refuse statements and actions that change data or schema")
+	o.Password = getenv("DITO_PASSWORD")
+}
+
+// RegisterStartupFlags registers the flags that control what the TUI shows after connecting.
+func (o *Options) RegisterStartupFlags(fs *flag.FlagSet, getenv func(string) string) {
+	fs.StringVar(&o.Table, "table", getenv("DITO_TABLE"), "Table to select after connecting (env DITO_TABLE)")
+	fs.StringVar(&o.SQL, "sql", getenv("DITO_SQL"), "SQL to prefill in the SQL pane (env DITO_SQL)")
+	refresh, _ := time.ParseDuration(getenv("DITO_REFRESH"))
+	fs.DurationVar(&o.Refresh, "refresh", refresh, "Refresh the table list and cached schemas at this interval, e.g. 30s (env DITO_REFRESH)")
+}
+
+// HasConnection reports whether any connection option was given.
+// A password or --read-only alone does not select a store and is not counted.
+func (o Options) HasConnection() bool {
+	return o.Profile != "" || o.Edition != "" || o.Endpoint != "" ||
+		o.HTTPS || o.CACert != "" || o.InsecureSkipVerify || o.Username != "" ||
+		o.OCIConfigFile != "" || o.OCIProfile != "" || o.Region != "" || o.Compartment != "" ||
+		o.TenantID != "" || o.Namespace != ""
+}
+
+// ConnectionConfig builds the connection configuration from the options,
+// starting from the named saved profile when one is given.
+func (o Options) ConnectionConfig(profiles []config.Profile) (db.ConnectionConfig, error) {
+	if !o.HasConnection() {
+		return db.ConnectionConfig{}, errors.New("no connection given (use --endpoint, --profile or --edition)")
+	}
+
+	var cfg db.ConnectionConfig
+	if o.Profile != "" {
+		index := config.FindProfile(profiles, o.Profile)
+		if index < 0 {
+			return db.ConnectionConfig{}, fmt.Errorf("unknown profile %q", o.Profile)
+		}
+		profile := profiles[index]
+		if o.Password != "" || o.PasswordFile != "" {
+			// An explicit password replaces the profile's reference
+			profile.PasswordEnv, profile.PasswordFile = "", ""
+		}
+		var err error
+		if cfg, err = profile.ConnectionConfig(); err != nil {
+			return db.ConnectionConfig{}, err
+		}
+	}
+
+	if o.Edition != "" {
+		edition, err := parseEdition(o.Edition)
+		if err != nil {
+			return db.ConnectionConfig{}, err
+		}
+		cfg.Edition = edition
+	}
+	if o.Endpoint != "" {
+		cfg.Endpoint, cfg.Port = splitEndpoint(o.Endpoint)
+	}
+	if o.HTTPS {
+		cfg.UseHTTPS = true
+	}
+	if o.InsecureSkipVerify {
+		cfg.InsecureSkipVerify = true
+	}
+	if o.ReadOnly {
+		// Can only be turned on; a read-only profile stays read-only
+		cfg.ReadOnly = true
+	}
+	overrideString(&cfg.CertPath, o.CACert)
+	overrideString(&cfg.Username, o.Username)
+	overrideString(&cfg.OCIConfigFile, o.OCIConfigFile)
+	overrideString(&cfg.OCIProfile, o.OCIProfile)
+	overrideString(&cfg.Region, o.Region)
+	overrideString(&cfg.Compartment, o.Compartment)
+	overrideString(&cfg.TenantID, o.TenantID)
+	overrideString(&cfg.Namespace, o.Namespace)
+
+	if o.Password != "" {
+		cfg.Password = o.Password
+	} else if o.PasswordFile != "" {
+		data, err := os.ReadFile(o.PasswordFile)
+		if err != nil {
+			return db.ConnectionConfig{}, fmt.Errorf("password file: %w", err)
+		}
+		cfg.Password = strings.TrimRight(string(data), "\r\n")
+	}
+
+	// Local editions default to localhost:8080 like the setup dialog
+	if cfg.EffectiveEdition() != db.EditionCloud {
+		if cfg.Endpoint == "" {
+			cfg.Endpoint = "localhost"
+		}
+		if cfg.Port == "" && !strings.Contains(cfg.Endpoint, "://") {
+			cfg.Port = defaultPort
+		}
+	}
+
+	return cfg, nil
+}
+
+// parseEdition validates an edition name
+func parseEdition(value string) (db.Edition, error) {
+	for _, edition := range db.Editions() {
+		if strings.EqualFold(value, string(edition)) {
+			return edition, nil
+		}
+	}
+	return "", fmt.Errorf("unknown edition %q (want onprem, cloud or cloudsim)", value)
+}
+
+// splitEndpoint splits "host:port" or "scheme://host:port" into endpoint and port.
+// The scheme is kept on the endpoint; the port is empty when not given.
+func splitEndpoint(value string) (endpoint, port string) {
+	scheme := ""
+	host := value
+	if i := strings.Index(value, "://"); i >= 0 {
+		scheme = value[:i+3]
+		host = value[i+3:]
+	}
+	host = strings.TrimSuffix(host, "/")
+	// Ignore colons inside an IPv6 literal such as [::1]:8080
+	if i := strings.LastIndex(host, ":"); i >= 0 && i > strings.LastIndex(host, "]") {
+		return scheme + host[:i], host[i+1:]
+	}
+	return scheme + host, ""
+}
+
+// overrideString replaces *dst with value when value is not empty
+func overrideString(dst *string, value string) {
+	if value != "" {
+		*dst = value
+	}
+}
@@ -0,0 +1,203 @@
+package cli
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/camikura/dito/internal/config"
+	"github.com/camikura/dito/internal/db"
+)
+
+// parseOptions registers the flags on a new flag set and parses args
+func parseOptions(t *testing.T, args []string, env map[string]string) Options {
+	t.Helper()
+	var opts Options
+	fs := flag.NewFlagSet("dito", flag.ContinueOnError)
+	getenv := func(key string) string { return env[key] }
+	opts.RegisterFlags(fs, getenv)
+	opts.RegisterStartupFlags(fs, getenv)
+	if err := fs.Parse(args); err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	return opts
+}
+
+func TestRegisterFlags(t *testing.T) {
+	t.Run("environment provides defaults", func(t *testing.T) {
+		opts := parseOptions(t, nil, map[string]string{
+			"DITO_ENDPOINT": "kvhost:9090",
+			"DITO_HTTPS":    "true",
+			"DITO_PASSWORD": "secret",
+			"DITO_TABLE":    "users",
+			"DITO_SQL":      "SELECT * FROM users",
+			"DITO_REFRESH":  "30s",
+		})
+
+		if opts.Endpoint != "kvhost:9090" || !opts.HTTPS || opts.Password != "secret" {
+			t.Errorf("Options = %+v", opts)
+		}
+		if opts.Table != "users" || opts.SQL != "SELECT * FROM users" {
+			t.Errorf("Startup options = %q, %q", opts.Table, opts.SQL)
+		}
+		if opts.Refresh != 30*time.Second {
+			t.Errorf("Refresh = %s, want 30s", opts.Refresh)
+		}
+	})
+
+	t.Run("flags override environment", func(t *testing.T) {
+		opts := parseOptions(t, []string{"--endpoint", "flaghost:8080", "--table", "orders"}, map[string]string{
+			"DITO_ENDPOINT": "envhost:9090",
+			"DITO_TABLE":    "users",
+		})
+
+		if opts.Endpoint != "flaghost:8080" {
+			t.Errorf("Endpoint = %q, want %q", opts.Endpoint, "flaghost:8080")
+		}
+		if opts.Table != "orders" {
+			t.Errorf("Table = %q, want %q", opts.Table, "orders")
+		}
+	})
+}
+
+func TestOptionsConnectionConfig(t *testing.T) {
+	profiles := []config.Profile{
+		{Name: "local", Edition: db.EditionOnPremise, Endpoint: "localhost", Port: "8080", Namespace: "dev"},
+		{Name: "secure", Edition: db.EditionOnPremise, Endpoint: "kvproxy", Port: "8443", Username: "admin", PasswordEnv: "DITO_TEST_UNSET_PASSWORD"},
+	}
+
+	tests := []struct {
+		name    string
+		opts    Options
+		want    db.ConnectionConfig
+		wantErr bool
+	}{
+		{
+			name: "host and port",
+			opts: Options{Endpoint: "kvhost:9090"},
+			want: db.ConnectionConfig{Endpoint: "kvhost", Port: "9090"},
+		},
+		{
+			name: "host without port uses default",
+			opts: Options{Endpoint: "kvhost"},
+			want: db.ConnectionConfig{Endpoint: "kvhost", Port: "8080"},
+		},
+		{
+			name: "URL keeps scheme",
+			opts: Options{Endpoint: "https://kvproxy:8443"},
+			want: db.ConnectionConfig{Endpoint: "https://kvproxy", Port: "8443"},
+		},
+		{
+			name: "URL without port",
+			opts: Options{Endpoint: "https://kvproxy"},
+			want: db.ConnectionConfig{Endpoint: "https://kvproxy"},
+		},
+		{
+			name: "IPv6 literal",
+			opts: Options{Endpoint: "[::1]:8080"},
+			want: db.ConnectionConfig{Endpoint: "[::1]", Port: "8080"},
+		},
+		{
+			name: "cloud simulator defaults",
+			opts: Options{Edition: "cloudsim", TenantID: "dev"},
+			want: db.ConnectionConfig{Edition: db.EditionCloudSim, Endpoint: "localhost", Port: "8080", TenantID: "dev"},
+		},
+		{
+			name: "cloud region",
+			opts: Options{Edition: "CLOUD", Region: "us-ashburn-1", Compartment: "dev"},
+			want: db.ConnectionConfig{Edition: db.EditionCloud, Region: "us-ashburn-1", Compartment: "dev"},
+		},
+		{
+			name: "secure on-premise",
+			opts: Options{Endpoint: "kvproxy:8443", HTTPS: true, CACert: "/ca.pem", Username: "admin", Password: "secret"},
+			want: db.ConnectionConfig{Endpoint: "kvproxy", Port: "8443", UseHTTPS: true, CertPath: "/ca.pem", Username: "admin", Password: "secret"},
+		},
+		{
+			name: "profile with overrides",
+			opts: Options{Profile: "local", Endpoint: "otherhost:9090"},
+			want: db.ConnectionConfig{Edition: db.EditionOnPremise, Endpoint: "otherhost", Port: "9090", Namespace: "dev"},
+		},
+		{
+			name: "password overrides profile reference",
+			opts: Options{Profile: "secure", Password: "secret"},
+			want: db.ConnectionConfig{Edition: db.EditionOnPremise, Endpoint: "kvproxy", Port: "8443", Username: "admin", Password: "secret"},
+		},
+		{
+			name:    "unresolved profile password",
+			opts:    Options{Profile: "secure"},
+			wantErr: true,
+		},
+		{
+			name:    "unknown profile",
+			opts:    Options{Profile: "missing"},
+			wantErr: true,
+		},
+		{
+			name:    "unknown edition",
+			opts:    Options{Edition: "mainframe"},
+			wantErr: true,
+		},
+		{
+			name:    "no connection",
+			opts:    Options{Table: "users"},
+			wantErr: true,
+		},
+		{
+			name:    "password alone is no connection",
+			opts:    Options{Password: "secret", ReadOnly: true},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.opts.ConnectionConfig(profiles)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ConnectionConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("ConnectionConfig() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOptionsHasConnection(t *testing.T) {
+	// Each option that describes the store connects to the local default
+	for _, opts := range []Options{
+		{Username: "admin"},
+		{TenantID: "tenant"},
+		{HTTPS: true},
+		{CACert: "ca.pem"},
+		{InsecureSkipVerify: true},
+		{Namespace: "dev"},
+		{Compartment: "dev"},
+	} {
+		if !opts.HasConnection() {
+			t.Errorf("HasConnection() = false for %+v", opts)
+		}
+		cfg, err := opts.ConnectionConfig(nil)
+		if err != nil {
+			t.Errorf("ConnectionConfig() error = %v for %+v", err, opts)
+		} else if cfg.Endpoint != "localhost" || cfg.Port != "8080" {
+			t.Errorf("endpoint = %s:%s for %+v", cfg.Endpoint, cfg.Port, opts)
+		}
+	}
+}
+
+func TestOptionsPasswordFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "password")
+	if err := os.WriteFile(path, []byte("from-file\n"), 0600); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	cfg, err := Options{Endpoint: "kvhost:8080", Username: "admin", PasswordFile: path}.ConnectionConfig(nil)
+	if err != nil {
+		t.Fatalf("ConnectionConfig() error = %v", err)
+	}
+	if cfg.Password != "from-file" {
+		t.Errorf("Password = %q, want %q", cfg.Password, "from-file")
+	}
+}
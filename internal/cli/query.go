@@ -0,0 +1,246 @@
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/oracle/nosql-go-sdk/nosqldb"
+
+	"github.com/camikura/dito/internal/config"
+	"github.com/camikura/dito/internal/db"
+	"github.com/camikura/dito/internal/ddl"
+	"github.com/camikura/dito/internal/output"
+	"github.com/camikura/dito/internal/ui"
+)
+
+// Exit codes of the non-interactive subcommands
+const (
+	ExitOK       = 0 // Success
+	ExitError    = 1 // Connection, query or output error
+	ExitUsage    = 2 // Invalid flags or arguments
+	ExitRejected = 3 // dito import finished but some records were rejected
+)
+
+// DefaultQueryLimit is the number of rows dito query writes unless --limit or --all is given.
+const DefaultQueryLimit = 1000
+
+// RunQuery implements "dito query": it executes one statement and writes the
+// result rows to stdout. The statement is the only argument, or "-" to read it from stdin.
+// It returns the process exit code.
+func RunQuery(args []string, stdin io.Reader, stdout, stderr io.Writer, getenv func(string) string) int {
+	var opts Options
+	fs := flag.NewFlagSet("dito query", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	opts.RegisterFlags(fs, getenv)
+	formatName := fs.String("format", "table", "Output format: table, jsonl, json, csv or tsv")
+	limit := fs.Int("limit", DefaultQueryLimit, "Maximum number of rows to write")
+	all := fs.Bool("all", false, "Write all rows (ignores --limit)")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: dito query [flags] <statement | ->")
+		fmt.Fprintln(stderr)
+		fmt.Fprintln(stderr, "Executes a statement and writes the results to stdout.")
+		fmt.Fprintln(stderr, "Use - to read the statement from stdin.")
+		fmt.Fprintln(stderr)
+		fmt.Fprintln(stderr, "Flags:")
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return ExitOK
+		}
+		return ExitUsage
+	}
+
+	usageError := func(format string, a ...interface{}) int {
+		fmt.Fprintf(stderr, "Error: "+format+"\n", a...)
+		return ExitUsage
+	}
+
+	if fs.NArg() != 1 {
+		return usageError("expected exactly one statement argument (or - for stdin)")
+	}
+	format, err := output.ParseFormat(*formatName)
+	if err != nil {
+		return usageError("%v", err)
+	}
+	if *limit < 1 && !*all {
+		return usageError("--limit must be at least 1 (use --all for no limit)")
+	}
+	maxRows := *limit
+	if *all {
+		maxRows = 0
+	}
+
+	statement := fs.Arg(0)
+	if statement == "-" {
+		data, err := io.ReadAll(stdin)
+		if err != nil {
+			fmt.Fprintf(stderr, "Error: reading statement: %v\n", err)
+			return ExitError
+		}
+		statement = string(data)
+	}
+	statement = strings.TrimRight(strings.TrimSpace(statement), ";")
+	if statement == "" {
+		return usageError("empty statement")
+	}
+
+	cfg, code := resolveConnection(opts, stderr)
+	if code != ExitOK {
+		return code
+	}
+	if cfg.ReadOnly {
+		// Refuse before the statement is prepared
+		if err := db.CheckReadOnly(statement); err != nil {
+			fmt.Fprintf(stderr, "Error: %v\n", err)
+			return ExitError
+		}
+	}
+	client, code := connect(cfg, stderr)
+	if client == nil {
+		return code
+	}
+	defer client.Close()
+
+	truncated, err := writeQuery(client, statement, maxRows, format, stdout)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		return ExitError
+	}
+	if truncated {
+		fmt.Fprintf(stderr, "Stopped after %d rows; use --limit or --all to write more\n", maxRows)
+	}
+	return ExitOK
+}
+
+// resolveConnection builds the connection configuration from the flags, loading the
+// saved profiles only when --profile is given. On failure it reports the error and
+// returns the exit code.
+func resolveConnection(opts Options, stderr io.Writer) (db.ConnectionConfig, int) {
+	var profiles []config.Profile
+	if opts.Profile != "" {
+		path, err := config.ProfilesPath()
+		if err != nil {
+			fmt.Fprintf(stderr, "Error: %v\n", err)
+			return db.ConnectionConfig{}, ExitError
+		}
+		if profiles, err = config.LoadProfiles(path); err != nil {
+			fmt.Fprintf(stderr, "Error: %v\n", err)
+			return db.ConnectionConfig{}, ExitError
+		}
+	}
+	cfg, err := opts.ConnectionConfig(profiles)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		return db.ConnectionConfig{}, ExitUsage
+	}
+	return cfg, ExitOK
+}
+
+// connect creates a client for cfg. On failure it reports the error and returns
+// a nil client with the exit code.
+func connect(cfg db.ConnectionConfig, stderr io.Writer) (*nosqldb.Client, int) {
+	client, err := db.NewClient(cfg)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		return nil, ExitError
+	}
+	return client, ExitOK
+}
+
+// writeQuery streams the statement's rows to w, stopping after maxRows rows (0 for no limit).
+// The writer is created on the first row so that its columns can be taken from the data.
+func writeQuery(client *nosqldb.Client, statement string, maxRows int, format output.Format, w io.Writer) (truncated bool, err error) {
+	selectColumns := db.ParseSelectColumns(statement)
+	var writer output.Writer
+	count := 0
+
+	err = db.QueryRows(client, statement, func(row map[string]interface{}) error {
+		if maxRows > 0 && count == maxRows {
+			// One row past the limit tells us the output was cut short
+			truncated = true
+			return db.ErrStopQuery
+		}
+		if writer == nil {
+			columns := resolveColumns(selectColumns, tableSchemas(client, statement), row)
+			var err error
+			if writer, err = output.NewWriter(w, format, columns); err != nil {
+				return err
+			}
+		}
+		count++
+		return writer.WriteRow(row)
+	})
+	if err != nil {
+		return false, err
+	}
+
+	if writer == nil {
+		// No rows: still write the header when the columns are known
+		columns := resolveColumns(selectColumns, tableSchemas(client, statement), nil)
+		if len(columns) == 0 {
+			return false, nil
+		}
+		if writer, err = output.NewWriter(w, format, columns); err != nil {
+			return false, err
+		}
+	}
+	return truncated, writer.Close()
+}
+
+// tableSchemas returns the schema of the statement's table followed by its ancestors'
+// schemas (root to parent), or nil when the table is unknown.
+func tableSchemas(client *nosqldb.Client, statement string) []*ddl.Table {
+	tableName := ui.ExtractTableNameFromSQL(statement)
+	if tableName == "" {
+		return nil
+	}
+	table, ancestors, err := db.GetTableSchemas(client, tableName)
+	if err != nil {
+		return nil
+	}
+	return append([]*ddl.Table{table}, ancestors...)
+}
+
+// resolveColumns returns the output column order.
+// An explicit SELECT list wins; otherwise the schema order is used (schemas holds the
+// table schema followed by its ancestors' schemas). Columns of the first row that are not
+// covered are appended alphabetically, and SELECT items missing from the row are dropped.
+func resolveColumns(selectColumns []string, schemas []*ddl.Table, first map[string]interface{}) []string {
+	var rows []map[string]interface{}
+	if first != nil {
+		rows = []map[string]interface{}{first}
+	}
+
+	if len(selectColumns) == 0 {
+		if len(schemas) == 0 {
+			return ui.GetColumnsInSchemaOrderFromTables(nil, nil, rows)
+		}
+		return ui.GetColumnsInSchemaOrderFromTables(schemas[0], schemas[1:], rows)
+	}
+
+	if first == nil {
+		return selectColumns
+	}
+	var columns []string
+	seen := make(map[string]bool)
+	for _, col := range selectColumns {
+		if _, ok := first[col]; ok && !seen[col] {
+			columns = append(columns, col)
+			seen[col] = true
+		}
+	}
+	var extra []string
+	for col := range first {
+		if !seen[col] {
+			extra = append(extra, col)
+		}
+	}
+	sort.Strings(extra)
+	return append(columns, extra...)
+}
@@ -0,0 +1,166 @@
+package cli
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/camikura/dito/internal/ddl"
+)
+
+func TestRunQueryUsageErrors(t *testing.T) {
+	env := map[string]string{"DITO_ENDPOINT": "localhost:8080"}
+
+	tests := []struct {
+		name string
+		args []string
+		env  map[string]string
+		want string
+	}{
+		{name: "missing statement", args: nil, env: env, want: "expected exactly one statement"},
+		{name: "extra arguments", args: []string{"SELECT * FROM users", "extra"}, env: env, want: "expected exactly one statement"},
+		{name: "unknown format", args: []string{"--format", "xml", "SELECT * FROM users"}, env: env, want: "unknown format"},
+		{name: "invalid limit", args: []string{"--limit", "0", "SELECT * FROM users"}, env: env, want: "--limit must be at least 1"},
+		{name: "empty statement", args: []string{" ; "}, env: env, want: "empty statement"},
+		{name: "no connection", args: []string{"SELECT * FROM users"}, want: "no connection given"},
+		{name: "unknown flag", args: []string{"--bogus", "SELECT * FROM users"}, env: env, want: "flag provided but not defined"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr strings.Builder
+			getenv := func(key string) string { return tt.env[key] }
+
+			code := RunQuery(tt.args, strings.NewReader(""), &stdout, &stderr, getenv)
+			if code != ExitUsage {
+				t.Errorf("exit code = %d, want %d", code, ExitUsage)
+			}
+			if !strings.Contains(stderr.String(), tt.want) {
+				t.Errorf("stderr = %q, want it to contain %q", stderr.String(), tt.want)
+			}
+			if stdout.Len() != 0 {
+				t.Errorf("stdout = %q, want empty", stdout.String())
+			}
+		})
+	}
+}
+
+func TestRunQueryHelp(t *testing.T) {
+	var stdout, stderr strings.Builder
+	code := RunQuery([]string{"-h"}, strings.NewReader(""), &stdout, &stderr, func(string) string { return "" })
+	if code != ExitOK {
+		t.Errorf("exit code = %d, want %d", code, ExitOK)
+	}
+	if !strings.Contains(stderr.String(), "Usage: dito query") {
+		t.Errorf("stderr = %q, want usage", stderr.String())
+	}
+}
+
+func TestRunQueryReadOnly(t *testing.T) {
+	// The statement is refused before connecting, so no server is needed
+	var stdout, stderr strings.Builder
+	getenv := func(key string) string {
+		return map[string]string{"DITO_ENDPOINT": "localhost:1", "DITO_READ_ONLY": "true"}[key]
+	}
+	code := RunQuery([]string{"DELETE FROM users"}, strings.NewReader(""), &stdout, &stderr, getenv)
+
+	if code != ExitError {
+		t.Errorf("exit code = %d, want %d", code, ExitError)
+	}
+	if !strings.Contains(stderr.String(), "read-only connection: DELETE statements are not allowed") {
+		t.Errorf("stderr = %q, want the read-only refusal", stderr.String())
+	}
+}
+
+func TestRunQueryServerError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Reject with a non-retryable error so the client fails fast
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte("stand-in server"))
+	}))
+	defer server.Close()
+
+	var stdout, stderr strings.Builder
+	stdin := strings.NewReader("SELECT * FROM users;\n")
+	code := RunQuery([]string{"--endpoint", server.URL, "-"}, stdin, &stdout, &stderr, func(string) string { return "" })
+
+	if code != ExitError {
+		t.Errorf("exit code = %d, want %d", code, ExitError)
+	}
+	if !strings.Contains(stderr.String(), "stand-in server") {
+		t.Errorf("stderr = %q, want the server error", stderr.String())
+	}
+	if stdout.Len() != 0 {
+		t.Errorf("stdout = %q, want empty", stdout.String())
+	}
+}
+
+func TestResolveColumns(t *testing.T) {
+	usersDDL := "CREATE TABLE users (id INTEGER, name STRING, age INTEGER, PRIMARY KEY(id))"
+	childDDL := "CREATE TABLE users.addresses (addr_id INTEGER, city STRING, PRIMARY KEY(addr_id))"
+
+	tests := []struct {
+		name          string
+		selectColumns []string
+		ddls          []string
+		first         map[string]interface{}
+		want          []string
+	}{
+		{
+			name:  "schema order",
+			ddls:  []string{usersDDL},
+			first: map[string]interface{}{"age": 1, "name": "a", "id": 1},
+			want:  []string{"id", "name", "age"},
+		},
+		{
+			name:  "child table puts parent keys first",
+			ddls:  []string{childDDL, usersDDL},
+			first: map[string]interface{}{"city": "x", "addr_id": 1, "id": 1},
+			want:  []string{"id", "addr_id", "city"},
+		},
+		{
+			name:  "no schema sorts row columns",
+			first: map[string]interface{}{"b": 1, "a": 2},
+			want:  []string{"a", "b"},
+		},
+		{
+			name:          "select list order",
+			selectColumns: []string{"name", "id"},
+			ddls:          []string{usersDDL},
+			first:         map[string]interface{}{"id": 1, "name": "a"},
+			want:          []string{"name", "id"},
+		},
+		{
+			name:          "select items missing from row",
+			selectColumns: []string{"name", "count(*)"},
+			first:         map[string]interface{}{"name": "a", "Column_2": 3},
+			want:          []string{"name", "Column_2"},
+		},
+		{
+			name:          "no rows uses select list",
+			selectColumns: []string{"name", "id"},
+			want:          []string{"name", "id"},
+		},
+		{
+			name: "no rows uses schema",
+			ddls: []string{usersDDL},
+			want: []string{"id", "name", "age"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var schemas []*ddl.Table
+			for _, text := range tt.ddls {
+				schema, _ := ddl.Parse(text)
+				schemas = append(schemas, schema)
+			}
+			got := resolveColumns(tt.selectColumns, schemas, tt.first)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("resolveColumns() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,223 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/camikura/dito/internal/db"
+)
+
+// ProfilesFileName is the name of the saved connection profiles file.
+const ProfilesFileName = "profiles.json"
+
+// Profile is a named connection saved in the profiles file.
+// Secrets are never stored: the password is referenced by an environment
+// variable or a file path and resolved when connecting.
+type Profile struct {
+	Name    string     `json:"name"`
+	Edition db.Edition `json:"edition,omitempty"`
+
+	// On-premise and Cloud Simulator
+	Endpoint string `json:"endpoint,omitempty"`
+	Port     string `json:"port,omitempty"`
+
+	// Secure on-premise store
+	UseHTTPS           bool   `json:"https,omitempty"`
+	CertPath           string `json:"cert_path,omitempty"`
+	InsecureSkipVerify bool   `json:"insecure_skip_verify,omitempty"`
+	Username           string `json:"username,omitempty"`
+	PasswordEnv        string `json:"password_env,omitempty"`  // Environment variable holding the password
+	PasswordFile       string `json:"password_file,omitempty"` // File containing the password
+
+	// Cloud Service
+	OCIConfigFile string `json:"oci_config_file,omitempty"`
+	OCIProfile    string `json:"oci_profile,omitempty"`
+	Region        string `json:"region,omitempty"`
+	Compartment   string `json:"compartment,omitempty"`
+
+	// Cloud Simulator
+	TenantID string `json:"tenant_id,omitempty"`
+
+	// Namespace used for unqualified table names (on-premise only)
+	Namespace string `json:"namespace,omitempty"`
+
+	// Refuse statements and actions that change data or schema
+	ReadOnly bool `json:"read_only,omitempty"`
+}
+
+// profilesFile is the on-disk layout of the profiles file.
+type profilesFile struct {
+	Profiles []Profile `json:"profiles"`
+}
+
+// Dir returns the dito configuration directory.
+// It is $XDG_CONFIG_HOME/dito, falling back to ~/.config/dito.
+func Dir() (string, error) {
+	if xdg := os.Getenv("XDG_CONFIG_HOME"); xdg != "" {
+		return filepath.Join(xdg, "dito"), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("config directory: %w", err)
+	}
+	return filepath.Join(home, ".config", "dito"), nil
+}
+
+// ProfilesPath returns the path of the saved connection profiles file.
+func ProfilesPath() (string, error) {
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, ProfilesFileName), nil
+}
+
+// LoadProfiles reads saved profiles from path.
+// A missing file is not an error and yields no profiles.
+func LoadProfiles(path string) ([]Profile, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read profiles: %w", err)
+	}
+
+	var file profilesFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("parse profiles %s: %w", path, err)
+	}
+	return file.Profiles, nil
+}
+
+// SaveProfiles writes profiles to path, creating the directory if needed.
+// The file is replaced atomically and is readable by the owner only.
+func SaveProfiles(path string, profiles []Profile) error {
+	if profiles == nil {
+		profiles = []Profile{}
+	}
+	data, err := json.MarshalIndent(profilesFile{Profiles: profiles}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode profiles: %w", err)
+	}
+
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return fmt.Errorf("create config directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(dir, ProfilesFileName+".*")
+	if err != nil {
+		return fmt.Errorf("write profiles: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		tmp.Close()
+		return fmt.Errorf("write profiles: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("write profiles: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("write profiles: %w", err)
+	}
+	return nil
+}
+
+// FindProfile returns the index of the profile with the given name, or -1 if not found.
+func FindProfile(profiles []Profile, name string) int {
+	for i, p := range profiles {
+		if p.Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// ValidateName checks that name can be used for the profile at index
+// (use -1 for a new profile).
+func ValidateName(profiles []Profile, name string, index int) error {
+	if strings.TrimSpace(name) == "" {
+		return errors.New("profile name is required")
+	}
+	if i := FindProfile(profiles, name); i >= 0 && i != index {
+		return fmt.Errorf("profile %q already exists", name)
+	}
+	return nil
+}
+
+// ConnectionConfig builds the connection configuration for the profile,
+// resolving the password reference.
+func (p Profile) ConnectionConfig() (db.ConnectionConfig, error) {
+	password, err := p.password()
+	if err != nil {
+		return db.ConnectionConfig{}, err
+	}
+	cfg := p.connectionConfig()
+	cfg.Password = password
+	return cfg, nil
+}
+
+// Summary returns a short description of the connection target,
+// e.g. "On-Premise: localhost:8080".
+func (p Profile) Summary() string {
+	cfg := p.connectionConfig()
+	return cfg.EffectiveEdition().Label() + ": " + cfg.DisplayEndpoint()
+}
+
+// connectionConfig converts the profile without resolving the password.
+func (p Profile) connectionConfig() db.ConnectionConfig {
+	return db.ConnectionConfig{
+		Edition:            p.Edition,
+		Endpoint:           p.Endpoint,
+		Port:               p.Port,
+		UseHTTPS:           p.UseHTTPS,
+		CertPath:           expandHome(p.CertPath),
+		InsecureSkipVerify: p.InsecureSkipVerify,
+		Username:           p.Username,
+		OCIConfigFile:      p.OCIConfigFile,
+		OCIProfile:         p.OCIProfile,
+		Region:             p.Region,
+		Compartment:        p.Compartment,
+		TenantID:           p.TenantID,
+		Namespace:          p.Namespace,
+		ReadOnly:           p.ReadOnly,
+	}
+}
+
+// password resolves the password from the environment variable or file
+// referenced by the profile. The environment variable takes precedence.
+func (p Profile) password() (string, error) {
+	if p.PasswordEnv != "" {
+		value, ok := os.LookupEnv(p.PasswordEnv)
+		if !ok {
+			return "", fmt.Errorf("profile %s: environment variable %s is not set", p.Name, p.PasswordEnv)
+		}
+		return value, nil
+	}
+	if p.PasswordFile != "" {
+		data, err := os.ReadFile(expandHome(p.PasswordFile))
+		if err != nil {
+			return "", fmt.Errorf("profile %s: password file: %w", p.Name, err)
+		}
+		return strings.TrimRight(string(data), "\r\n"), nil
+	}
+	return "", nil
+}
+
+// expandHome replaces a leading ~ with the user's home directory.
+func expandHome(path string) string {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, strings.TrimPrefix(path, "~"))
+}
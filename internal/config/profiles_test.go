@@ -0,0 +1,209 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/camikura/dito/internal/db"
+)
+
+func TestProfilesPath(t *testing.T) {
+	t.Run("uses XDG_CONFIG_HOME", func(t *testing.T) {
+		t.Setenv("XDG_CONFIG_HOME", "/tmp/xdg")
+
+		path, err := ProfilesPath()
+		if err != nil {
+			t.Fatalf("ProfilesPath() error = %v", err)
+		}
+		if want := filepath.Join("/tmp/xdg", "dito", "profiles.json"); path != want {
+			t.Errorf("ProfilesPath() = %q, want %q", path, want)
+		}
+	})
+
+	t.Run("falls back to ~/.config", func(t *testing.T) {
+		t.Setenv("XDG_CONFIG_HOME", "")
+		t.Setenv("HOME", "/home/test")
+
+		path, err := ProfilesPath()
+		if err != nil {
+			t.Fatalf("ProfilesPath() error = %v", err)
+		}
+		if want := filepath.Join("/home/test", ".config", "dito", "profiles.json"); path != want {
+			t.Errorf("ProfilesPath() = %q, want %q", path, want)
+		}
+	})
+}
+
+func TestLoadProfilesMissingFile(t *testing.T) {
+	profiles, err := LoadProfiles(filepath.Join(t.TempDir(), "missing.json"))
+	if err != nil {
+		t.Fatalf("LoadProfiles() error = %v", err)
+	}
+	if len(profiles) != 0 {
+		t.Errorf("LoadProfiles() = %v, want no profiles", profiles)
+	}
+}
+
+func TestLoadProfilesInvalidFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "profiles.json")
+	if err := os.WriteFile(path, []byte("{not json"), 0600); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	if _, err := LoadProfiles(path); err == nil {
+		t.Error("Expected error for invalid profiles file")
+	}
+}
+
+func TestSaveAndLoadProfiles(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dito", "profiles.json")
+	profiles := []Profile{
+		{Name: "local", Edition: db.EditionOnPremise, Endpoint: "localhost", Port: "8080", Namespace: "dev"},
+		{Name: "secure", Edition: db.EditionOnPremise, Endpoint: "kvproxy", Port: "8443", UseHTTPS: true, Username: "admin", PasswordEnv: "KV_PASSWORD"},
+		{Name: "oci", Edition: db.EditionCloud, Region: "us-ashburn-1", Compartment: "dev", ReadOnly: true},
+	}
+
+	if err := SaveProfiles(path, profiles); err != nil {
+		t.Fatalf("SaveProfiles() error = %v", err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("Stat: %v", err)
+	}
+	if perm := info.Mode().Perm(); perm != 0600 {
+		t.Errorf("file mode = %o, want 600", perm)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	if !strings.Contains(string(data), `"password_env": "KV_PASSWORD"`) {
+		t.Errorf("Expected password reference in file, got %s", data)
+	}
+
+	loaded, err := LoadProfiles(path)
+	if err != nil {
+		t.Fatalf("LoadProfiles() error = %v", err)
+	}
+	if len(loaded) != len(profiles) {
+		t.Fatalf("LoadProfiles() returned %d profiles, want %d", len(loaded), len(profiles))
+	}
+	for i := range profiles {
+		if loaded[i] != profiles[i] {
+			t.Errorf("profile[%d] = %+v, want %+v", i, loaded[i], profiles[i])
+		}
+	}
+}
+
+func TestValidateName(t *testing.T) {
+	profiles := []Profile{{Name: "local"}, {Name: "prod"}}
+
+	tests := []struct {
+		name    string
+		input   string
+		index   int
+		wantErr bool
+	}{
+		{name: "new unique name", input: "staging", index: -1},
+		{name: "empty name", input: "  ", index: -1, wantErr: true},
+		{name: "duplicate name", input: "prod", index: -1, wantErr: true},
+		{name: "keep own name", input: "prod", index: 1},
+		{name: "rename to other profile", input: "local", index: 1, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateName(profiles, tt.input, tt.index)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateName(%q, %d) error = %v, wantErr %v", tt.input, tt.index, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestProfileConnectionConfig(t *testing.T) {
+	t.Run("password from environment", func(t *testing.T) {
+		t.Setenv("DITO_TEST_PASSWORD", "from-env")
+		p := Profile{Name: "secure", Endpoint: "kvproxy", Port: "8443", Username: "admin", PasswordEnv: "DITO_TEST_PASSWORD", Namespace: "dev"}
+
+		cfg, err := p.ConnectionConfig()
+		if err != nil {
+			t.Fatalf("ConnectionConfig() error = %v", err)
+		}
+		if cfg.Password != "from-env" {
+			t.Errorf("Password = %q, want %q", cfg.Password, "from-env")
+		}
+		if cfg.Username != "admin" || cfg.Endpoint != "kvproxy" || cfg.Namespace != "dev" {
+			t.Errorf("ConnectionConfig() = %+v", cfg)
+		}
+	})
+
+	t.Run("password from file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "password")
+		if err := os.WriteFile(path, []byte("from-file\n"), 0600); err != nil {
+			t.Fatalf("WriteFile: %v", err)
+		}
+		p := Profile{Name: "secure", Username: "admin", PasswordFile: path}
+
+		cfg, err := p.ConnectionConfig()
+		if err != nil {
+			t.Fatalf("ConnectionConfig() error = %v", err)
+		}
+		if cfg.Password != "from-file" {
+			t.Errorf("Password = %q, want %q", cfg.Password, "from-file")
+		}
+	})
+
+	t.Run("unset environment variable", func(t *testing.T) {
+		p := Profile{Name: "secure", PasswordEnv: "DITO_TEST_UNSET_PASSWORD"}
+
+		_, err := p.ConnectionConfig()
+		if err == nil || !strings.Contains(err.Error(), "DITO_TEST_UNSET_PASSWORD") {
+			t.Errorf("Expected error naming the variable, got %v", err)
+		}
+	})
+
+	t.Run("missing password file", func(t *testing.T) {
+		p := Profile{Name: "secure", PasswordFile: filepath.Join(t.TempDir(), "missing")}
+
+		if _, err := p.ConnectionConfig(); err == nil {
+			t.Error("Expected error for missing password file")
+		}
+	})
+}
+
+func TestProfileSummary(t *testing.T) {
+	tests := []struct {
+		name    string
+		profile Profile
+		want    string
+	}{
+		{
+			name:    "on-premise",
+			profile: Profile{Endpoint: "localhost", Port: "8080"},
+			want:    "On-Premise: localhost:8080",
+		},
+		{
+			name:    "cloud",
+			profile: Profile{Edition: db.EditionCloud, Region: "us-ashburn-1"},
+			want:    "Cloud: us-ashburn-1",
+		},
+		{
+			name:    "cloud simulator",
+			profile: Profile{Edition: db.EditionCloudSim, Endpoint: "localhost", Port: "8080"},
+			want:    "Cloud Simulator: localhost:8080",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.profile.Summary(); got != tt.want {
+				t.Errorf("Summary() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
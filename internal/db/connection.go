@@ -0,0 +1,211 @@
+package db
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/oracle/nosql-go-sdk/nosqldb"
+	"github.com/oracle/nosql-go-sdk/nosqldb/auth/cloudsim"
+	"github.com/oracle/nosql-go-sdk/nosqldb/auth/iam"
+	"github.com/oracle/nosql-go-sdk/nosqldb/auth/kvstore"
+	"github.com/oracle/nosql-go-sdk/nosqldb/common"
+	"github.com/oracle/nosql-go-sdk/nosqldb/httputil"
+)
+
+// Edition represents the Oracle NoSQL Database edition to connect to.
+// The value matches the SDK configuration mode.
+type Edition string
+
+const (
+	// EditionOnPremise connects to an on-premise Oracle NoSQL Database proxy.
+	EditionOnPremise Edition = "onprem"
+	// EditionCloud connects to Oracle NoSQL Database Cloud Service using OCI IAM.
+	EditionCloud Edition = "cloud"
+	// EditionCloudSim connects to a local Oracle NoSQL Cloud Simulator.
+	EditionCloudSim Edition = "cloudsim"
+)
+
+// DefaultOCIConfigFile is the default location of the OCI configuration file.
+const DefaultOCIConfigFile = "~/.oci/config"
+
+// DefaultOCIProfile is the default profile name in the OCI configuration file.
+const DefaultOCIProfile = "DEFAULT"
+
+// DefaultCloudSimTenantID is the tenant ID sent to the Cloud Simulator when none is given.
+const DefaultCloudSimTenantID = "ExampleTenantId"
+
+// Editions returns all supported editions in display order.
+func Editions() []Edition {
+	return []Edition{EditionOnPremise, EditionCloud, EditionCloudSim}
+}
+
+// Label returns the human readable name of the edition.
+func (e Edition) Label() string {
+	switch e {
+	case EditionCloud:
+		return "Cloud"
+	case EditionCloudSim:
+		return "Cloud Simulator"
+	default:
+		return "On-Premise"
+	}
+}
+
+// ConnectionConfig holds the parameters needed to connect to a NoSQL database.
+type ConnectionConfig struct {
+	Edition Edition // Empty means EditionOnPremise
+
+	// On-premise (and optional Cloud endpoint override)
+	Endpoint string
+	Port     string
+
+	// Secure on-premise store
+	UseHTTPS           bool   // Connect with https:// (implied by an https:// Endpoint)
+	CertPath           string // PEM CA bundle used in addition to system certificates
+	InsecureSkipVerify bool   // Skip server certificate verification
+	Username           string // KVStore user (login is enabled when set)
+	Password           string // KVStore password
+
+	// Cloud Service (OCI IAM)
+	OCIConfigFile string // Defaults to DefaultOCIConfigFile
+	OCIProfile    string // Defaults to DefaultOCIProfile
+	Region        string // Region identifier, e.g. us-ashburn-1 (optional if set in OCI config)
+	Compartment   string // Compartment OCID or path (optional, defaults to tenancy)
+
+	// Cloud Simulator
+	TenantID string // Tenant ID sent as the bearer token (defaults to DefaultCloudSimTenantID)
+
+	// Namespace used for unqualified table names (on-premise only)
+	Namespace string
+
+	// ReadOnly refuses statements and actions that change data or schema
+	ReadOnly bool
+}
+
+// EffectiveEdition returns the edition, treating an empty value as on-premise.
+func (c ConnectionConfig) EffectiveEdition() Edition {
+	if c.Edition == "" {
+		return EditionOnPremise
+	}
+	return c.Edition
+}
+
+// DisplayEndpoint returns a short description of the connection target.
+func (c ConnectionConfig) DisplayEndpoint() string {
+	if c.EffectiveEdition() == EditionCloud {
+		if c.Region != "" {
+			return c.Region
+		}
+		if c.Endpoint != "" {
+			return c.hostPort()
+		}
+		return "OCI profile " + c.profile()
+	}
+	if c.isSecure() {
+		return c.endpointURL()
+	}
+	return fmt.Sprintf("%s:%s", c.Endpoint, c.Port)
+}
+
+// hostPort joins Endpoint and Port, omitting the port when it is empty.
+func (c ConnectionConfig) hostPort() string {
+	if c.Port == "" {
+		return c.Endpoint
+	}
+	return c.Endpoint + ":" + c.Port
+}
+
+// endpointURL builds the endpoint URL from Endpoint and Port.
+// An explicit scheme in Endpoint is preserved, otherwise http or https is used
+// depending on UseHTTPS.
+func (c ConnectionConfig) endpointURL() string {
+	endpoint := c.hostPort()
+	if !strings.Contains(endpoint, "://") {
+		if c.UseHTTPS {
+			endpoint = "https://" + endpoint
+		} else {
+			endpoint = "http://" + endpoint
+		}
+	}
+	return endpoint
+}
+
+// isSecure reports whether the on-premise connection uses TLS.
+func (c ConnectionConfig) isSecure() bool {
+	return c.UseHTTPS || strings.HasPrefix(strings.ToLower(c.Endpoint), "https://")
+}
+
+// profile returns the OCI profile name, falling back to the default.
+func (c ConnectionConfig) profile() string {
+	if c.OCIProfile == "" {
+		return DefaultOCIProfile
+	}
+	return c.OCIProfile
+}
+
+// configFile returns the OCI configuration file path, falling back to the default.
+func (c ConnectionConfig) configFile() string {
+	if c.OCIConfigFile == "" {
+		return DefaultOCIConfigFile
+	}
+	return c.OCIConfigFile
+}
+
+// NosqlConfig builds the SDK configuration for this connection.
+func (c ConnectionConfig) NosqlConfig() (nosqldb.Config, error) {
+	switch c.EffectiveEdition() {
+	case EditionOnPremise:
+		cfg := nosqldb.Config{
+			Mode:     "onprem",
+			Endpoint: c.endpointURL(),
+		}
+		if c.isSecure() {
+			cfg.HTTPConfig = httputil.HTTPConfig{
+				UseHTTPS:           true,
+				CertPath:           c.CertPath,
+				InsecureSkipVerify: c.InsecureSkipVerify,
+			}
+		}
+		if c.Username != "" {
+			// The SDK sets the login endpoint and HTTP client on the provider
+			provider, err := kvstore.NewAccessTokenProvider(c.Username, []byte(c.Password))
+			if err != nil {
+				return nosqldb.Config{}, fmt.Errorf("KVStore login: %w", err)
+			}
+			cfg.AuthorizationProvider = provider
+		}
+		cfg.RequestConfig.Namespace = c.Namespace
+		return cfg, nil
+
+	case EditionCloud:
+		provider, err := iam.NewSignatureProviderFromFile(c.configFile(), c.profile(), "", c.Compartment)
+		if err != nil {
+			return nosqldb.Config{}, fmt.Errorf("OCI config: %w", err)
+		}
+		cfg := nosqldb.Config{
+			Mode:                  "cloud",
+			AuthorizationProvider: provider,
+		}
+		// Region takes precedence; the endpoint is only used when no region is given
+		if c.Region != "" {
+			cfg.Region = common.Region(c.Region)
+		} else if c.Endpoint != "" {
+			// Without a scheme the SDK picks https for port 443 and http otherwise
+			cfg.Endpoint = c.hostPort()
+		}
+		return cfg, nil
+
+	case EditionCloudSim:
+		tenantID := c.TenantID
+		if tenantID == "" {
+			tenantID = DefaultCloudSimTenantID
+		}
+		return nosqldb.Config{
+			Mode:                  "cloudsim",
+			Endpoint:              c.endpointURL(),
+			AuthorizationProvider: &cloudsim.AccessTokenProvider{TenantID: tenantID},
+		}, nil
+	}
+
+	return nosqldb.Config{}, fmt.Errorf("unsupported edition: %s", c.Edition)
+}
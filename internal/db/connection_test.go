@@ -0,0 +1,376 @@
+package db
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/oracle/nosql-go-sdk/nosqldb/auth/cloudsim"
+	"github.com/oracle/nosql-go-sdk/nosqldb/auth/kvstore"
+)
+
+func TestConnectionConfigDisplayEndpoint(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  ConnectionConfig
+		want string
+	}{
+		{
+			name: "on-premise",
+			cfg:  ConnectionConfig{Endpoint: "localhost", Port: "8080"},
+			want: "localhost:8080",
+		},
+		{
+			name: "cloud simulator",
+			cfg:  ConnectionConfig{Edition: EditionCloudSim, Endpoint: "localhost", Port: "8080"},
+			want: "localhost:8080",
+		},
+		{
+			name: "cloud with region",
+			cfg:  ConnectionConfig{Edition: EditionCloud, Region: "us-ashburn-1"},
+			want: "us-ashburn-1",
+		},
+		{
+			name: "cloud with endpoint",
+			cfg:  ConnectionConfig{Edition: EditionCloud, Endpoint: "nosql.example.com"},
+			want: "nosql.example.com",
+		},
+		{
+			name: "cloud with profile only",
+			cfg:  ConnectionConfig{Edition: EditionCloud, OCIProfile: "dev"},
+			want: "OCI profile dev",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.cfg.DisplayEndpoint(); got != tt.want {
+				t.Errorf("DisplayEndpoint() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConnectionConfigNosqlConfigOnPremise(t *testing.T) {
+	cfg, err := ConnectionConfig{Endpoint: "localhost", Port: "8080"}.NosqlConfig()
+	if err != nil {
+		t.Fatalf("NosqlConfig() error = %v", err)
+	}
+	if cfg.Mode != "onprem" {
+		t.Errorf("Mode = %q, want %q", cfg.Mode, "onprem")
+	}
+	if cfg.Endpoint != "http://localhost:8080" {
+		t.Errorf("Endpoint = %q, want %q", cfg.Endpoint, "http://localhost:8080")
+	}
+}
+
+func TestConnectionConfigNosqlConfigNamespace(t *testing.T) {
+	cfg, err := ConnectionConfig{Endpoint: "localhost", Port: "8080", Namespace: "dev"}.NosqlConfig()
+	if err != nil {
+		t.Fatalf("NosqlConfig() error = %v", err)
+	}
+	if cfg.DefaultNamespace() != "dev" {
+		t.Errorf("DefaultNamespace() = %q, want %q", cfg.DefaultNamespace(), "dev")
+	}
+}
+
+func TestConnectionConfigNosqlConfigCloud(t *testing.T) {
+	configFile := writeTestOCIConfig(t, "us-phoenix-1")
+
+	t.Run("region from dialog", func(t *testing.T) {
+		cfg, err := ConnectionConfig{
+			Edition:       EditionCloud,
+			OCIConfigFile: configFile,
+			Region:        "us-ashburn-1",
+			Compartment:   "ocid1.compartment.oc1..test",
+		}.NosqlConfig()
+		if err != nil {
+			t.Fatalf("NosqlConfig() error = %v", err)
+		}
+		if cfg.Mode != "cloud" {
+			t.Errorf("Mode = %q, want %q", cfg.Mode, "cloud")
+		}
+		if string(cfg.Region) != "us-ashburn-1" {
+			t.Errorf("Region = %q, want %q", cfg.Region, "us-ashburn-1")
+		}
+		if cfg.AuthorizationProvider == nil {
+			t.Error("Expected an IAM signature provider")
+		}
+	})
+
+	t.Run("missing config file", func(t *testing.T) {
+		_, err := ConnectionConfig{
+			Edition:       EditionCloud,
+			OCIConfigFile: filepath.Join(t.TempDir(), "missing"),
+		}.NosqlConfig()
+		if err == nil {
+			t.Error("Expected error for missing OCI config file")
+		}
+	})
+
+	t.Run("unknown profile", func(t *testing.T) {
+		_, err := ConnectionConfig{
+			Edition:       EditionCloud,
+			OCIConfigFile: configFile,
+			OCIProfile:    "NOPE",
+		}.NosqlConfig()
+		if err == nil {
+			t.Error("Expected error for unknown OCI profile")
+		}
+	})
+}
+
+func TestConnectCloudSignsRequests(t *testing.T) {
+	var mu sync.Mutex
+	var authHeader, compartmentHeader, dateHeader string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		authHeader = r.Header.Get("Authorization")
+		compartmentHeader = r.Header.Get("X-Nosql-Compartment-Id")
+		dateHeader = r.Header.Get("Date")
+		mu.Unlock()
+		// Reject with a non-retryable error so the client fails fast
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte("stand-in server"))
+	}))
+	defer server.Close()
+
+	// No region in the OCI config so that the endpoint is used
+	configFile := writeTestOCIConfig(t, "")
+	cmd := Connect(ConnectionConfig{
+		Edition:       EditionCloud,
+		Endpoint:      server.URL,
+		OCIConfigFile: configFile,
+		Compartment:   "ocid1.compartment.oc1..test",
+	}, true)
+
+	result, ok := cmd().(ConnectionResult)
+	if !ok {
+		t.Fatal("Expected ConnectionResult")
+	}
+	if result.Err == nil || !strings.Contains(result.Err.Error(), "stand-in server") {
+		t.Fatalf("Expected stand-in server error, got %v", result.Err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if !strings.HasPrefix(authHeader, "Signature ") {
+		t.Errorf("Authorization header = %q, want Signature scheme", authHeader)
+	}
+	wantKeyID := `keyId="ocid1.tenancy.oc1..test/ocid1.user.oc1..test/aa:bb:cc"`
+	if !strings.Contains(authHeader, wantKeyID) {
+		t.Errorf("Authorization header = %q, want %s", authHeader, wantKeyID)
+	}
+	if !strings.Contains(authHeader, `algorithm="rsa-sha256"`) {
+		t.Errorf("Authorization header = %q, want rsa-sha256 algorithm", authHeader)
+	}
+	if compartmentHeader != "ocid1.compartment.oc1..test" {
+		t.Errorf("X-Nosql-Compartment-Id = %q, want %q", compartmentHeader, "ocid1.compartment.oc1..test")
+	}
+	if dateHeader == "" {
+		t.Error("Expected Date header to be set for signing")
+	}
+}
+
+func TestConnectionConfigNosqlConfigSecureOnPremise(t *testing.T) {
+	cfg, err := ConnectionConfig{
+		Endpoint:           "kvproxy",
+		Port:               "8443",
+		UseHTTPS:           true,
+		CertPath:           "/etc/ssl/ca.pem",
+		InsecureSkipVerify: true,
+		Username:           "admin",
+		Password:           "secret",
+	}.NosqlConfig()
+	if err != nil {
+		t.Fatalf("NosqlConfig() error = %v", err)
+	}
+	if cfg.Endpoint != "https://kvproxy:8443" {
+		t.Errorf("Endpoint = %q, want %q", cfg.Endpoint, "https://kvproxy:8443")
+	}
+	if !cfg.HTTPConfig.UseHTTPS || cfg.HTTPConfig.CertPath != "/etc/ssl/ca.pem" || !cfg.HTTPConfig.InsecureSkipVerify {
+		t.Errorf("HTTPConfig = %+v", cfg.HTTPConfig)
+	}
+	if _, ok := cfg.AuthorizationProvider.(*kvstore.AccessTokenProvider); !ok {
+		t.Errorf("AuthorizationProvider = %T, want *kvstore.AccessTokenProvider", cfg.AuthorizationProvider)
+	}
+}
+
+func TestConnectionConfigNosqlConfigHTTPSEndpoint(t *testing.T) {
+	cfg, err := ConnectionConfig{Endpoint: "https://kvproxy", Port: "8443"}.NosqlConfig()
+	if err != nil {
+		t.Fatalf("NosqlConfig() error = %v", err)
+	}
+	if cfg.Endpoint != "https://kvproxy:8443" {
+		t.Errorf("Endpoint = %q, want %q", cfg.Endpoint, "https://kvproxy:8443")
+	}
+	if !cfg.HTTPConfig.UseHTTPS {
+		t.Error("Expected UseHTTPS for https:// endpoint")
+	}
+	if cfg.AuthorizationProvider != nil {
+		t.Error("Expected no authorization provider without username")
+	}
+}
+
+func TestConnectSecureOnPremiseLogin(t *testing.T) {
+	var mu sync.Mutex
+	var loginAuth, requestAuth string
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if strings.HasSuffix(r.URL.Path, "/login") {
+			loginAuth = r.Header.Get("Authorization")
+			w.Write([]byte(`{"token":"test-token"}`))
+			return
+		}
+		requestAuth = r.Header.Get("Authorization")
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte("stand-in server"))
+	}))
+	defer server.Close()
+
+	// Trust the test server certificate through a CA bundle file
+	certFile := filepath.Join(t.TempDir(), "ca.pem")
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	if err := os.WriteFile(certFile, certPEM, 0600); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	host, port, _ := strings.Cut(strings.TrimPrefix(server.URL, "https://"), ":")
+	cmd := Connect(ConnectionConfig{
+		Endpoint: host,
+		Port:     port,
+		UseHTTPS: true,
+		CertPath: certFile,
+		Username: "admin",
+		Password: "secret",
+	}, true)
+
+	result, ok := cmd().(ConnectionResult)
+	if !ok {
+		t.Fatal("Expected ConnectionResult")
+	}
+	if result.Err == nil || !strings.Contains(result.Err.Error(), "stand-in server") {
+		t.Fatalf("Expected stand-in server error, got %v", result.Err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if !strings.HasPrefix(loginAuth, "Basic ") {
+		t.Errorf("Login Authorization = %q, want Basic credentials", loginAuth)
+	}
+	if requestAuth != "Bearer test-token" {
+		t.Errorf("Request Authorization = %q, want %q", requestAuth, "Bearer test-token")
+	}
+}
+
+func TestConnectionConfigNosqlConfigCloudSim(t *testing.T) {
+	t.Run("default tenant", func(t *testing.T) {
+		cfg, err := ConnectionConfig{Edition: EditionCloudSim, Endpoint: "localhost", Port: "8080"}.NosqlConfig()
+		if err != nil {
+			t.Fatalf("NosqlConfig() error = %v", err)
+		}
+		if cfg.Mode != "cloudsim" {
+			t.Errorf("Mode = %q, want %q", cfg.Mode, "cloudsim")
+		}
+		if cfg.Endpoint != "http://localhost:8080" {
+			t.Errorf("Endpoint = %q, want %q", cfg.Endpoint, "http://localhost:8080")
+		}
+		provider, ok := cfg.AuthorizationProvider.(*cloudsim.AccessTokenProvider)
+		if !ok {
+			t.Fatalf("AuthorizationProvider = %T, want *cloudsim.AccessTokenProvider", cfg.AuthorizationProvider)
+		}
+		if provider.TenantID != DefaultCloudSimTenantID {
+			t.Errorf("TenantID = %q, want %q", provider.TenantID, DefaultCloudSimTenantID)
+		}
+	})
+
+	t.Run("custom tenant", func(t *testing.T) {
+		cfg, err := ConnectionConfig{Edition: EditionCloudSim, Endpoint: "localhost", Port: "8080", TenantID: "dev"}.NosqlConfig()
+		if err != nil {
+			t.Fatalf("NosqlConfig() error = %v", err)
+		}
+		provider, ok := cfg.AuthorizationProvider.(*cloudsim.AccessTokenProvider)
+		if !ok || provider.TenantID != "dev" {
+			t.Errorf("AuthorizationProvider = %+v, want tenant %q", cfg.AuthorizationProvider, "dev")
+		}
+	})
+}
+
+func TestConnectCloudSimSendsTenant(t *testing.T) {
+	var mu sync.Mutex
+	var authHeader string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		authHeader = r.Header.Get("Authorization")
+		mu.Unlock()
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte("stand-in server"))
+	}))
+	defer server.Close()
+
+	host, port, _ := strings.Cut(strings.TrimPrefix(server.URL, "http://"), ":")
+	cmd := Connect(ConnectionConfig{
+		Edition:  EditionCloudSim,
+		Endpoint: host,
+		Port:     port,
+		TenantID: "TestTenant",
+	}, true)
+
+	result, ok := cmd().(ConnectionResult)
+	if !ok {
+		t.Fatal("Expected ConnectionResult")
+	}
+	if result.Err == nil || !strings.Contains(result.Err.Error(), "stand-in server") {
+		t.Fatalf("Expected stand-in server error, got %v", result.Err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if authHeader != "Bearer TestTenant" {
+		t.Errorf("Authorization header = %q, want %q", authHeader, "Bearer TestTenant")
+	}
+}
+
+// writeTestOCIConfig writes an OCI config file and private key to a temp dir
+// and returns the config file path.
+func writeTestOCIConfig(t *testing.T, region string) string {
+	t.Helper()
+	dir := t.TempDir()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+	keyFile := filepath.Join(dir, "key.pem")
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	if err := os.WriteFile(keyFile, keyPEM, 0600); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	var config strings.Builder
+	config.WriteString("[DEFAULT]\n")
+	config.WriteString("tenancy=ocid1.tenancy.oc1..test\n")
+	config.WriteString("user=ocid1.user.oc1..test\n")
+	config.WriteString("fingerprint=aa:bb:cc\n")
+	config.WriteString("key_file=" + keyFile + "\n")
+	if region != "" {
+		config.WriteString("region=" + region + "\n")
+	}
+	configFile := filepath.Join(dir, "config")
+	if err := os.WriteFile(configFile, []byte(config.String()), 0600); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	return configFile
+}
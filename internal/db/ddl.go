@@ -0,0 +1,115 @@
+package db
+
+import (
+	"regexp"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/oracle/nosql-go-sdk/nosqldb"
+	"github.com/oracle/nosql-go-sdk/nosqldb/types"
+)
+
+const (
+	// DDLTimeout is how long WaitForDDL waits for an operation to complete.
+	DDLTimeout = 10 * time.Minute
+	// DDLPollInterval is how often WaitForDDL checks the table state.
+	DDLPollInterval = time.Second
+)
+
+// DDLResult represents the progress of a DDL statement.
+// The first result is produced when the server accepts the statement; unless
+// the operation is already Done, WaitForDDL produces a second one on completion.
+type DDLResult struct {
+	TableName string
+	SQL       string
+	Keyword   string           // Leading keyword of the statement
+	State     types.TableState // Table state reported by the server
+	Done      bool             // Whether the operation has completed
+	Table     *nosqldb.TableResult
+	Err       error
+}
+
+// ExecuteDDL submits a DDL statement with a TableRequest.
+// Returns a tea.Cmd that produces a DDLResult message.
+func ExecuteDDL(client *nosqldb.Client, tableName string, sql string) tea.Cmd {
+	return func() tea.Msg {
+		_, keyword := ClassifyStatement(sql)
+		res := DDLResult{TableName: tableName, SQL: sql, Keyword: keyword}
+
+		table, err := client.DoTableRequest(&nosqldb.TableRequest{Statement: sql})
+		if err != nil {
+			res.Err = err
+			return res
+		}
+		if table.TableName != "" {
+			res.TableName = table.TableName
+		}
+		res.State = table.State
+		res.Done = table.State.IsTerminal()
+		res.Table = table
+		return res
+	}
+}
+
+// SystemResult represents the completion of a system statement (GRANT, REVOKE,
+// CREATE/DROP NAMESPACE, USER or ROLE), which does not operate on a table.
+type SystemResult struct {
+	SQL     string
+	Keyword string // Leading keyword of the statement
+	Err     error
+}
+
+// ExecuteSystemStatement submits a system statement with a SystemRequest and
+// waits until it completes. Returns a tea.Cmd that produces a SystemResult message.
+func ExecuteSystemStatement(client *nosqldb.Client, sql string) tea.Cmd {
+	return func() tea.Msg {
+		_, keyword := ClassifyStatement(sql)
+		res := SystemResult{SQL: sql, Keyword: keyword}
+
+		result, err := client.DoSystemRequest(&nosqldb.SystemRequest{Statement: sql})
+		if err == nil {
+			_, err = result.WaitForCompletion(client, DDLTimeout, DDLPollInterval)
+		}
+		res.Err = err
+		return res
+	}
+}
+
+// WaitForDDL waits in the background until the operation of a DDLResult completes.
+// Returns a tea.Cmd that produces a DDLResult message with Done set.
+func WaitForDDL(client *nosqldb.Client, res DDLResult) tea.Cmd {
+	return func() tea.Msg {
+		table, err := res.Table.WaitForCompletion(client, DDLTimeout, DDLPollInterval)
+		res.Done = true
+		if err != nil {
+			res.Err = err
+			return res
+		}
+		res.State = table.State
+		res.Table = table
+		return res
+	}
+}
+
+var (
+	ddlTableRe = regexp.MustCompile(`(?i)^(?:CREATE|ALTER|DROP)\s+TABLE\s+(?:IF\s+(?:NOT\s+)?EXISTS\s+)?([a-zA-Z_][\w.:]*)`)
+	ddlIndexRe = regexp.MustCompile(`(?i)^(?:CREATE|DROP)\s+(?:FULLTEXT\s+)?INDEX\s+(?:IF\s+(?:NOT\s+)?EXISTS\s+)?\w+\s+ON\s+([a-zA-Z_][\w.:]*)`)
+)
+
+// DDLTableName returns the table a CREATE/ALTER/DROP TABLE or CREATE/DROP INDEX statement
+// operates on, or "" for other statements.
+func DDLTableName(sql string) string {
+	s := skipSpaceAndComments(sql)
+	for _, re := range []*regexp.Regexp{ddlTableRe, ddlIndexRe} {
+		if matches := re.FindStringSubmatch(s); len(matches) >= 2 {
+			return matches[1]
+		}
+	}
+	return ""
+}
+
+// TableStateLabel returns the upper-case name of a table state, e.g. "CREATING"
+func TableStateLabel(state types.TableState) string {
+	return strings.ToUpper(state.String())
+}
@@ -0,0 +1,29 @@
+package db
+
+import "testing"
+
+func TestDDLTableName(t *testing.T) {
+	tests := []struct {
+		sql  string
+		want string
+	}{
+		{sql: "CREATE TABLE users (id INTEGER, PRIMARY KEY(id))", want: "users"},
+		{sql: "create table if not exists users.addresses (aid INTEGER, PRIMARY KEY(aid))", want: "users.addresses"},
+		{sql: "ALTER TABLE users (ADD age INTEGER)", want: "users"},
+		{sql: "DROP TABLE IF EXISTS ns1:users", want: "ns1:users"},
+		{sql: "-- index\nCREATE INDEX idx_name ON users (name)", want: "users"},
+		{sql: "CREATE INDEX IF NOT EXISTS idx_name ON users(name)", want: "users"},
+		{sql: "CREATE FULLTEXT INDEX ft ON docs (body)", want: "docs"},
+		{sql: "DROP INDEX idx_name ON users", want: "users"},
+		{sql: "CREATE NAMESPACE ns1", want: ""},
+		{sql: "SELECT * FROM users", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.sql, func(t *testing.T) {
+			if got := DDLTableName(tt.sql); got != tt.want {
+				t.Errorf("DDLTableName() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package db
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/oracle/nosql-go-sdk/nosqldb"
+)
+
+// DefaultNamespace is the namespace of tables whose name has no namespace qualifier
+const DefaultNamespace = "sysdefault"
+
+// NamespaceListResult represents the result of fetching the namespaces of a store.
+type NamespaceListResult struct {
+	Namespaces []string
+	Err        error
+}
+
+// FetchNamespaces fetches the namespaces of an on-premise store. Cloud services
+// have no namespaces and return an error.
+// Returns a tea.Cmd that produces a NamespaceListResult message.
+func FetchNamespaces(client *nosqldb.Client) tea.Cmd {
+	return func() tea.Msg {
+		namespaces, err := client.ListNamespaces()
+		return NamespaceListResult{Namespaces: namespaces, Err: err}
+	}
+}
+
+// SplitTableName splits a namespace-qualified table name such as "ns1:users.phones"
+// into its namespace and table name. The namespace is "" for unqualified names.
+func SplitTableName(name string) (namespace, table string) {
+	if i := strings.Index(name, ":"); i >= 0 {
+		return name[:i], name[i+1:]
+	}
+	return "", name
+}
+
+// IsSystemTable reports whether name is a system table (SYS$*) of the store
+func IsSystemTable(name string) bool {
+	_, table := SplitTableName(name)
+	return strings.HasPrefix(table, "SYS$")
+}
+
+// QualifyTableName prefixes table with namespace unless it is already qualified
+// or namespace is empty.
+func QualifyTableName(namespace, table string) string {
+	if namespace == "" || strings.Contains(table, ":") {
+		return table
+	}
+	return namespace + ":" + table
+}
@@ -0,0 +1,57 @@
+package db
+
+import "testing"
+
+func TestSplitTableName(t *testing.T) {
+	tests := []struct {
+		name      string
+		namespace string
+		table     string
+	}{
+		{name: "users", table: "users"},
+		{name: "users.phones", table: "users.phones"},
+		{name: "ns1:users", namespace: "ns1", table: "users"},
+		{name: "ns1:users.phones", namespace: "ns1", table: "users.phones"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			namespace, table := SplitTableName(tt.name)
+			if namespace != tt.namespace || table != tt.table {
+				t.Errorf("SplitTableName(%q) = %q, %q, want %q, %q", tt.name, namespace, table, tt.namespace, tt.table)
+			}
+		})
+	}
+}
+
+func TestQualifyTableName(t *testing.T) {
+	tests := []struct {
+		namespace string
+		table     string
+		expected  string
+	}{
+		{namespace: "", table: "users", expected: "users"},
+		{namespace: "ns1", table: "users", expected: "ns1:users"},
+		{namespace: "ns1", table: "users.phones", expected: "ns1:users.phones"},
+		{namespace: "ns1", table: "ns2:users", expected: "ns2:users"},
+	}
+
+	for _, tt := range tests {
+		if got := QualifyTableName(tt.namespace, tt.table); got != tt.expected {
+			t.Errorf("QualifyTableName(%q, %q) = %q, want %q", tt.namespace, tt.table, got, tt.expected)
+		}
+	}
+}
+
+func TestIsSystemTable(t *testing.T) {
+	for name, expected := range map[string]bool{
+		"SYS$TableStatsPartition":        true,
+		"sysdefault:SYS$IndexStatsLease": true,
+		"users":                          false,
+		"ns1:users.SYS$x":                false,
+	} {
+		if got := IsSystemTable(name); got != expected {
+			t.Errorf("IsSystemTable(%q) = %v, want %v", name, got, expected)
+		}
+	}
+}
@@ -2,6 +2,7 @@ package db
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"reflect"
 	"strings"
@@ -10,15 +11,20 @@ import (
 	"github.com/oracle/nosql-go-sdk/nosqldb"
 	"github.com/oracle/nosql-go-sdk/nosqldb/nosqlerr"
 	"github.com/oracle/nosql-go-sdk/nosqldb/types"
+
+	"github.com/camikura/dito/internal/ddl"
 )
 
 // ConnectionResult represents the result of a connection attempt.
 type ConnectionResult struct {
-	Err      error
-	Version  string
-	Client   *nosqldb.Client
-	Endpoint string
-	IsTest   bool // true for test connections (no screen transition)
+	Err       error
+	Version   string
+	Client    *nosqldb.Client
+	Endpoint  string
+	Edition   Edition // Edition of the established connection
+	Namespace string  // Default namespace of the connection (on-premise only)
+	ReadOnly  bool    // Whether the connection refuses changes
+	IsTest    bool    // true for test connections (no screen transition)
 }
 
 // TableListResult represents the result of fetching table list.
@@ -31,10 +37,18 @@ type TableListResult struct {
 type TableDetailsResult struct {
 	TableName string
 	Schema    *nosqldb.TableResult
+	Parent    *nosqldb.TableResult // Parent table of a child table, to read its schema (see TableSchema)
 	Indexes   []nosqldb.IndexInfo
 	Err       error
 }
 
+// TableUsageResult represents the latest usage record of a table.
+type TableUsageResult struct {
+	TableName string
+	Usage     *nosqldb.TableUsage // nil when the server returned no record
+	Err       error
+}
+
 // TableDataResult represents the result of fetching table data.
 type TableDataResult struct {
 	TableName    string
@@ -51,19 +65,20 @@ type TableDataResult struct {
 	Offset       int      // Current offset for custom SQL pagination
 }
 
+// StatementResult represents the result of a DML statement (INSERT, UPSERT, UPDATE, DELETE).
+type StatementResult struct {
+	TableName string
+	SQL       string // Executed statement
+	Keyword   string // Leading keyword of the statement
+	Affected  int    // Number of rows inserted, updated or deleted
+	Err       error
+}
+
 // Connect attempts to connect to NoSQL database.
 // Returns a tea.Cmd that produces a ConnectionResult message.
-func Connect(endpoint, port string, isTest bool) tea.Cmd {
+func Connect(conn ConnectionConfig, isTest bool) tea.Cmd {
 	return func() tea.Msg {
-		// Connection configuration
-		endpointURL := fmt.Sprintf("http://%s:%s", endpoint, port)
-		cfg := nosqldb.Config{
-			Mode:     "onprem",
-			Endpoint: endpointURL,
-		}
-
-		// Create client
-		client, err := nosqldb.NewClient(cfg)
+		client, err := NewClient(conn)
 		if err != nil {
 			return ConnectionResult{Err: err, IsTest: isTest}
 		}
@@ -92,34 +107,48 @@ func Connect(endpoint, port string, isTest bool) tea.Cmd {
 
 		// Connection successful - return client (don't close)
 		return ConnectionResult{
-			Version:  "Connected",
-			Err:      nil,
-			Client:   client,
-			Endpoint: fmt.Sprintf("%s:%s", endpoint, port),
-			IsTest:   false,
+			Version:   "Connected",
+			Err:       nil,
+			Client:    client,
+			Endpoint:  conn.DisplayEndpoint(),
+			Edition:   conn.EffectiveEdition(),
+			Namespace: conn.Namespace,
+			ReadOnly:  conn.ReadOnly,
+			IsTest:    false,
 		}
 	}
 }
 
+// NewClient creates a client for the connection without testing it.
+func NewClient(conn ConnectionConfig) (*nosqldb.Client, error) {
+	cfg, err := conn.NosqlConfig()
+	if err != nil {
+		return nil, err
+	}
+	return nosqldb.NewClient(cfg)
+}
+
 // FetchTables fetches the list of tables from NoSQL database.
+// When namespace is set only the tables of that namespace are listed, qualified
+// with it (e.g. "ns1:users"); otherwise the tables of all namespaces are listed.
+// System tables (SYS$*) are left out unless system is set.
 // Returns a tea.Cmd that produces a TableListResult message.
-func FetchTables(client *nosqldb.Client) tea.Cmd {
+func FetchTables(client *nosqldb.Client, namespace string, system bool) tea.Cmd {
 	return func() tea.Msg {
-		req := &nosqldb.ListTablesRequest{}
+		req := &nosqldb.ListTablesRequest{Namespace: namespace}
 		result, err := client.ListTables(req)
 		if err != nil {
 			return TableListResult{Err: err}
 		}
 
-		// Filter out system tables (SYS$*)
-		var userTables []string
+		var tables []string
 		for _, table := range result.Tables {
-			if !strings.HasPrefix(table, "SYS$") {
-				userTables = append(userTables, table)
+			if system || !IsSystemTable(table) {
+				tables = append(tables, QualifyTableName(namespace, table))
 			}
 		}
 
-		return TableListResult{Tables: userTables, Err: nil}
+		return TableListResult{Tables: tables, Err: nil}
 	}
 }
 
@@ -136,6 +165,14 @@ func FetchTableDetails(client *nosqldb.Client, tableName string) tea.Cmd {
 			return TableDetailsResult{TableName: tableName, Err: err}
 		}
 
+		// The JSON schema of a child table is read with its parent's key
+		var parent *nosqldb.TableResult
+		if i := strings.LastIndex(tableName, "."); i >= 0 {
+			if result, err := client.GetTable(&nosqldb.GetTableRequest{TableName: tableName[:i]}); err == nil {
+				parent = result
+			}
+		}
+
 		// Get index information
 		indexReq := &nosqldb.GetIndexesRequest{
 			TableName: tableName,
@@ -143,11 +180,58 @@ func FetchTableDetails(client *nosqldb.Client, tableName string) tea.Cmd {
 		indexResult, err := client.GetIndexes(indexReq)
 		if err != nil {
 			// Ignore index fetch errors, return schema information only
-			return TableDetailsResult{TableName: tableName, Schema: tableResult, Indexes: nil, Err: nil}
+			return TableDetailsResult{TableName: tableName, Schema: tableResult, Parent: parent, Indexes: nil, Err: nil}
+		}
+
+		return TableDetailsResult{TableName: tableName, Schema: tableResult, Parent: parent, Indexes: indexResult.Indexes, Err: nil}
+	}
+}
+
+// FetchTableUsage fetches the most recent complete usage record of a table
+// (read/write units, storage and throttling). On-premise stores do not record usage.
+// Returns a tea.Cmd that produces a TableUsageResult message.
+func FetchTableUsage(client *nosqldb.Client, tableName string) tea.Cmd {
+	return func() tea.Msg {
+		result, err := client.GetTableUsage(&nosqldb.TableUsageRequest{TableName: tableName})
+		if err != nil {
+			return TableUsageResult{TableName: tableName, Err: err}
 		}
+		res := TableUsageResult{TableName: tableName}
+		if n := len(result.UsageRecords); n > 0 {
+			res.Usage = &result.UsageRecords[n-1]
+		}
+		return res
+	}
+}
+
+// GetTableSchemas returns the schema of tableName and the schemas of its ancestor
+// tables (root to immediate parent). An ancestor that cannot be read has an empty
+// schema. See TableSchema.
+func GetTableSchemas(client *nosqldb.Client, tableName string) (table *ddl.Table, ancestors []*ddl.Table, err error) {
+	result, err := client.GetTable(&nosqldb.GetTableRequest{TableName: tableName})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// "a.b.c" has the ancestors "a" and "a.b"; each schema is read with its parent
+	var parent *nosqldb.TableResult
+	parts := strings.Split(tableName, ".")
+	for i := 1; i < len(parts); i++ {
+		ancestor, err := client.GetTable(&nosqldb.GetTableRequest{TableName: strings.Join(parts[:i], ".")})
+		if err != nil {
+			ancestors = append(ancestors, &ddl.Table{})
+			parent = nil
+			continue
+		}
+		schema, _ := TableSchema(ancestor, parent)
+		ancestors = append(ancestors, schema)
+		parent = ancestor
+	}
 
-		return TableDetailsResult{TableName: tableName, Schema: tableResult, Indexes: indexResult.Indexes, Err: nil}
+	if table, err = TableSchema(result, parent); err != nil {
+		return nil, nil, err
 	}
+	return table, ancestors, nil
 }
 
 // FetchTableData fetches table data (initial fetch, sorted by PRIMARY KEY).
@@ -231,11 +315,30 @@ func ParseSelectColumns(sql string) []string {
 }
 
 // ExecuteCustomSQL executes custom SQL query and returns results.
-// Returns a tea.Cmd that produces a TableDataResult message.
+// Returns a tea.Cmd that produces a TableDataResult message, or a StatementResult
+// message for DML statements, which are run once without a LIMIT clause.
 func ExecuteCustomSQL(client *nosqldb.Client, tableName string, sql string, limit int) tea.Cmd {
+	if kind, keyword := ClassifyStatement(sql); kind == StatementDML {
+		return executeStatement(client, tableName, sql, keyword)
+	}
 	return executeCustomSQLWithOffset(client, tableName, sql, limit, 0, false)
 }
 
+// executeStatement runs a DML statement and reports the number of affected rows.
+func executeStatement(client *nosqldb.Client, tableName string, sql string, keyword string) tea.Cmd {
+	return func() tea.Msg {
+		var rows []map[string]interface{}
+		err := QueryRows(client, sql, func(row map[string]interface{}) error {
+			rows = append(rows, row)
+			return nil
+		})
+		if err != nil {
+			return StatementResult{TableName: tableName, SQL: sql, Keyword: keyword, Err: err}
+		}
+		return StatementResult{TableName: tableName, SQL: sql, Keyword: keyword, Affected: affectedRows(rows)}
+	}
+}
+
 // FetchMoreCustomSQL fetches additional rows for custom SQL using OFFSET pagination.
 func FetchMoreCustomSQL(client *nosqldb.Client, tableName string, sql string, limit int, offset int) tea.Cmd {
 	return executeCustomSQLWithOffset(client, tableName, sql, limit, offset, true)
@@ -264,45 +367,15 @@ func executeCustomSQLWithOffset(client *nosqldb.Client, tableName string, sql st
 			statement = fmt.Sprintf("%s LIMIT %d", statement, limit)
 		}
 
-		prepReq := &nosqldb.PrepareRequest{
-			Statement: statement,
-		}
-		prepResult, err := client.Prepare(prepReq)
+		var rows []map[string]interface{}
+		err := QueryRows(client, statement, func(row map[string]interface{}) error {
+			rows = append(rows, row)
+			return nil
+		})
 		if err != nil {
 			return TableDataResult{TableName: tableName, Err: err, IsAppend: isAppend, SQL: statement, DisplaySQL: displayStatement, IsCustomSQL: true}
 		}
 
-		queryReq := &nosqldb.QueryRequest{
-			PreparedStatement: &prepResult.PreparedStatement,
-		}
-
-		// Fetch all results (using SDK's internal pagination)
-		var rows []map[string]interface{}
-		for {
-			queryResult, err := client.Query(queryReq)
-			if err != nil {
-				return TableDataResult{TableName: tableName, Err: err, IsAppend: isAppend, SQL: statement, DisplaySQL: displayStatement, IsCustomSQL: true}
-			}
-
-			// Get results
-			results, err := queryResult.GetResults()
-			if err != nil {
-				return TableDataResult{TableName: tableName, Err: err, IsAppend: isAppend, SQL: statement, DisplaySQL: displayStatement, IsCustomSQL: true}
-			}
-
-			for _, result := range results {
-				row := result.Map()
-				// Convert SDK-specific types (e.g., *types.MapValue) to native Go types
-				convertedRow := convertRowValues(row)
-				rows = append(rows, convertedRow)
-			}
-
-			// Exit if no continuation token
-			if queryReq.IsDone() {
-				break
-			}
-		}
-
 		// Check if more pages exist
 		hasMore := len(rows) == limit
 
@@ -323,6 +396,52 @@ func executeCustomSQLWithOffset(client *nosqldb.Client, tableName string, sql st
 	}
 }
 
+// ErrStopQuery can be returned by a QueryRows callback to stop reading rows without an error.
+var ErrStopQuery = errors.New("stop query")
+
+// QueryRows prepares and runs statement, calling fn for each result row.
+// Rows are converted to native Go types and fetched page by page, so the whole
+// result set is never held in memory. Iteration stops at the first error from fn.
+func QueryRows(client *nosqldb.Client, statement string, fn func(row map[string]interface{}) error) error {
+	prepResult, err := client.Prepare(&nosqldb.PrepareRequest{Statement: statement})
+	if err != nil {
+		return err
+	}
+
+	queryReq := &nosqldb.QueryRequest{
+		PreparedStatement: &prepResult.PreparedStatement,
+	}
+
+	// Fetch all results (using SDK's internal pagination)
+	for {
+		queryResult, err := client.Query(queryReq)
+		if err != nil {
+			return err
+		}
+
+		// Get results
+		results, err := queryResult.GetResults()
+		if err != nil {
+			return err
+		}
+
+		for _, result := range results {
+			// Convert SDK-specific types (e.g., *types.MapValue) to native Go types
+			if err := fn(convertRowValues(result.Map())); err != nil {
+				if errors.Is(err, ErrStopQuery) {
+					return nil
+				}
+				return err
+			}
+		}
+
+		// Exit if no continuation token
+		if queryReq.IsDone() {
+			return nil
+		}
+	}
+}
+
 // fetchTableDataWithCursor is an internal function to fetch table data with PRIMARY KEY cursor support.
 func fetchTableDataWithCursor(client *nosqldb.Client, tableName string, limit int, primaryKeys []string, lastPKValues map[string]interface{}, isAppend bool) tea.Cmd {
 	return func() tea.Msg {
@@ -365,45 +484,15 @@ func fetchTableDataWithCursor(client *nosqldb.Client, tableName string, limit in
 		// Display SQL (without LIMIT clause)
 		displayStatement := fmt.Sprintf("SELECT * FROM %s%s%s", tableName, whereClause, orderByClause)
 
-		prepReq := &nosqldb.PrepareRequest{
-			Statement: statement,
-		}
-		prepResult, err := client.Prepare(prepReq)
+		var rows []map[string]interface{}
+		err := QueryRows(client, statement, func(row map[string]interface{}) error {
+			rows = append(rows, row)
+			return nil
+		})
 		if err != nil {
 			return TableDataResult{TableName: tableName, Err: err, IsAppend: isAppend, SQL: statement, DisplaySQL: displayStatement, IsCustomSQL: false}
 		}
 
-		queryReq := &nosqldb.QueryRequest{
-			PreparedStatement: &prepResult.PreparedStatement,
-		}
-
-		// Fetch all results (using SDK's internal pagination)
-		var rows []map[string]interface{}
-		for {
-			queryResult, err := client.Query(queryReq)
-			if err != nil {
-				return TableDataResult{TableName: tableName, Err: err, IsAppend: isAppend, SQL: statement, DisplaySQL: displayStatement, IsCustomSQL: false}
-			}
-
-			// Get results
-			results, err := queryResult.GetResults()
-			if err != nil {
-				return TableDataResult{TableName: tableName, Err: err, IsAppend: isAppend, SQL: statement, DisplaySQL: displayStatement, IsCustomSQL: false}
-			}
-
-			for _, result := range results {
-				row := result.Map()
-				// Convert SDK-specific types (e.g., *types.MapValue) to native Go types
-				convertedRow := convertRowValues(row)
-				rows = append(rows, convertedRow)
-			}
-
-			// Exit if no continuation token
-			if queryReq.IsDone() {
-				break
-			}
-		}
-
 		// Save last row's PRIMARY KEY values
 		var newLastPKValues map[string]interface{}
 		if len(rows) > 0 && len(primaryKeys) > 0 {
@@ -0,0 +1,305 @@
+package db
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/oracle/nosql-go-sdk/nosqldb"
+
+	"github.com/camikura/dito/internal/ddl"
+)
+
+// jsonTable is the JSON schema the server returns in TableResult.Schema
+type jsonTable struct {
+	Name            string        `json:"name"`
+	Namespace       string        `json:"namespace"`
+	Parent          string        `json:"parent"` // Full name of the parent of a child table, e.g. "users"
+	Description     string        `json:"description"`
+	TTL             interface{}   `json:"ttl"` // e.g. "5 DAYS"
+	ShardKey        []string      `json:"shardKey"`
+	PrimaryKey      []string      `json:"primaryKey"`
+	PrimaryKeySizes []int         `json:"primaryKeySizes"`
+	Fields          []jsonField   `json:"fields"`
+	Identity        *jsonIdentity `json:"identity"`
+	Regions         []jsonRegion  `json:"regions"`
+	JSONCollection  bool          `json:"jsonCollection"`
+}
+
+// jsonField is a column, RECORD field or collection element of a JSON schema
+type jsonField struct {
+	Name        string          `json:"name"`
+	Type        string          `json:"type"`
+	Nullable    *bool           `json:"nullable"`
+	Default     json.RawMessage `json:"default"`
+	Precision   *int            `json:"precision"` // TIMESTAMP
+	Size        *int            `json:"size"`      // FIXED_BINARY
+	Symbols     []string        `json:"symbols"`   // ENUM
+	Collection  *jsonField      `json:"collection"`
+	Fields      []jsonField     `json:"fields"`
+	AsUUID      bool            `json:"asUuid"`
+	Generated   bool            `json:"generated"`
+	MRCounter   bool            `json:"mrcounter"`
+	Description string          `json:"description"`
+}
+
+// jsonIdentity describes the identity column of a JSON schema
+type jsonIdentity struct {
+	Name   string `json:"name"`
+	Always bool   `json:"always"`
+	OnNull bool   `json:"onNull"`
+}
+
+// jsonRegion is a region of a multi-region table
+type jsonRegion struct {
+	Name string `json:"regionName"`
+}
+
+// TableSchema returns the structured schema of a table. It reads the JSON schema
+// when the server returns one, and parses the DDL otherwise (or when the JSON
+// schema cannot be read). parent is the parent table of a child table, nil for
+// top-level tables: its key tells the inherited key columns apart in the JSON
+// schema. The returned table is never nil; on error it holds whatever could be read.
+func TableSchema(table, parent *nosqldb.TableResult) (*ddl.Table, error) {
+	if table == nil {
+		return &ddl.Table{}, fmt.Errorf("no table schema")
+	}
+	if table.Schema != "" {
+		if schema, err := ParseJSONSchema(table.Schema, jsonPrimaryKey(parent)); err == nil {
+			return schema, nil
+		} else if table.DDL == "" {
+			return schema, err
+		}
+	}
+	if table.DDL == "" {
+		return &ddl.Table{Name: table.TableName}, fmt.Errorf("no schema for %s", table.TableName)
+	}
+	schema, err := ddl.Parse(table.DDL)
+	if schema == nil {
+		schema = &ddl.Table{Name: table.TableName}
+	}
+	return schema, err
+}
+
+// Table returns the structured schema of the table, ignoring read errors.
+// See TableSchema.
+func (r *TableDetailsResult) Table() *ddl.Table {
+	schema, _ := TableSchema(r.Schema, r.Parent)
+	return schema
+}
+
+// ParseJSONSchema reads the JSON schema of a table (TableResult.Schema).
+// The JSON schema of a child table names the table without its parents and
+// lists the key columns inherited from them with its own; parentKey, the full
+// primary key of the parent table, is used to remove them so the table has
+// the same shape as one read from its DDL. parentKey is ignored for top-level
+// tables.
+func ParseJSONSchema(text string, parentKey []string) (*ddl.Table, error) {
+	var jt jsonTable
+	if err := json.Unmarshal([]byte(text), &jt); err != nil {
+		return &ddl.Table{}, fmt.Errorf("invalid table schema: %w", err)
+	}
+	if jt.Parent != "" && !strings.Contains(jt.Name, ".") {
+		jt.Name = jt.Parent + "." + jt.Name
+	}
+	if len(jt.Fields) == 0 {
+		return &ddl.Table{Name: jt.Name}, fmt.Errorf("table schema has no fields")
+	}
+	if jt.Parent != "" {
+		if err := stripInheritedKeys(&jt, parentKey); err != nil {
+			return &ddl.Table{Name: jt.Name}, err
+		}
+	}
+
+	t := &ddl.Table{
+		Name:           jt.Name,
+		Comment:        jt.Description,
+		PrimaryKey:     jt.PrimaryKey,
+		ShardKey:       jt.ShardKey,
+		JSONCollection: jt.JSONCollection,
+	}
+	if jt.Namespace != "" && !strings.Contains(jt.Name, ":") {
+		t.Name = jt.Namespace + ":" + jt.Name
+	}
+	if len(t.ShardKey) == 0 {
+		t.ShardKey = t.PrimaryKey
+	}
+	for _, region := range jt.Regions {
+		t.Regions = append(t.Regions, region.Name)
+	}
+	ttl, err := parseJSONTTL(jt.TTL)
+	if err != nil {
+		return t, err
+	}
+	t.TTL = ttl
+
+	for _, field := range jt.Fields {
+		col, err := jsonColumn(field)
+		if err != nil {
+			return t, err
+		}
+		t.Columns = append(t.Columns, col)
+	}
+	for i, name := range t.PrimaryKey {
+		col := t.Column(name)
+		if col == nil {
+			return t, fmt.Errorf("primary key column %s is not a field", name)
+		}
+		col.PrimaryKey = true
+		if i < len(jt.PrimaryKeySizes) {
+			col.KeySize = jt.PrimaryKeySizes[i]
+		}
+	}
+	if id := jt.Identity; id != nil {
+		if col := t.Column(id.Name); col != nil {
+			col.Identity = &ddl.Identity{Always: id.Always, OnNull: id.OnNull}
+		}
+	}
+	return t, nil
+}
+
+// stripInheritedKeys removes the key columns a child table inherits from its
+// parent, whose full primary key is parentKey, from the fields and keys of jt.
+// The shard key is the root table's, so the child's own key is used as in DDL.
+func stripInheritedKeys(jt *jsonTable, parentKey []string) error {
+	n := len(parentKey)
+	if n == 0 || n >= len(jt.PrimaryKey) || !slices.EqualFunc(jt.PrimaryKey[:n], parentKey, strings.EqualFold) {
+		return fmt.Errorf("primary key of %s does not start with the key of its parent %s", jt.Name, jt.Parent)
+	}
+	inherited := jt.PrimaryKey[:n]
+	jt.Fields = slices.DeleteFunc(jt.Fields, func(f jsonField) bool {
+		return slices.ContainsFunc(inherited, func(key string) bool { return strings.EqualFold(key, f.Name) })
+	})
+	jt.PrimaryKey = jt.PrimaryKey[n:]
+	if len(jt.PrimaryKeySizes) > n {
+		jt.PrimaryKeySizes = jt.PrimaryKeySizes[n:]
+	} else {
+		jt.PrimaryKeySizes = nil
+	}
+	jt.ShardKey = nil
+	return nil
+}
+
+// jsonPrimaryKey returns the full primary key of table from its JSON schema,
+// or nil when it has none
+func jsonPrimaryKey(table *nosqldb.TableResult) []string {
+	if table == nil || table.Schema == "" {
+		return nil
+	}
+	var jt jsonTable
+	if err := json.Unmarshal([]byte(table.Schema), &jt); err != nil {
+		return nil
+	}
+	return jt.PrimaryKey
+}
+
+// parseJSONTTL reads a TTL such as "5 DAYS"; a bare number is a number of days
+func parseJSONTTL(value interface{}) (*ddl.TTL, error) {
+	switch v := value.(type) {
+	case nil:
+		return nil, nil
+	case float64:
+		if v == 0 {
+			return nil, nil
+		}
+		return &ddl.TTL{Value: int(v), Unit: "DAYS"}, nil
+	case string:
+		fields := strings.Fields(strings.ToUpper(v))
+		if len(fields) == 0 {
+			return nil, nil
+		}
+		n, err := strconv.Atoi(fields[0])
+		if err != nil || len(fields) > 2 {
+			return nil, fmt.Errorf("invalid TTL %q", v)
+		}
+		if n == 0 {
+			return nil, nil
+		}
+		unit := "DAYS"
+		if len(fields) == 2 {
+			unit = strings.TrimSuffix(fields[1], "S") + "S"
+		}
+		if unit != "DAYS" && unit != "HOURS" {
+			return nil, fmt.Errorf("invalid TTL %q", v)
+		}
+		return &ddl.TTL{Value: n, Unit: unit}, nil
+	}
+	return nil, fmt.Errorf("invalid TTL %v", value)
+}
+
+// jsonColumn converts a JSON schema field to a column
+func jsonColumn(f jsonField) (*ddl.Column, error) {
+	typ, err := jsonType(f)
+	if err != nil {
+		return nil, fmt.Errorf("field %s: %w", f.Name, err)
+	}
+	col := &ddl.Column{
+		Name:      f.Name,
+		Type:      typ,
+		NotNull:   f.Nullable != nil && !*f.Nullable,
+		UUID:      f.AsUUID,
+		UUIDGen:   f.AsUUID && f.Generated,
+		MRCounter: f.MRCounter,
+		Comment:   f.Description,
+	}
+	col.Default = jsonDefault(f.Default, typ)
+	return col, nil
+}
+
+// jsonType converts the type of a JSON schema field
+func jsonType(f jsonField) (*ddl.Type, error) {
+	t := &ddl.Type{Name: strings.ToUpper(f.Type), Size: -1}
+	switch t.Name {
+	case "INTEGER", "LONG", "FLOAT", "DOUBLE", "NUMBER", "STRING", "BOOLEAN", "BINARY", "JSON", "ANY":
+	case "FIXED_BINARY":
+		t.Name = "BINARY"
+		if f.Size != nil {
+			t.Size = *f.Size
+		}
+	case "TIMESTAMP":
+		if f.Precision != nil {
+			t.Size = *f.Precision
+		}
+	case "ENUM":
+		t.Values = f.Symbols
+	case "ARRAY", "MAP":
+		if f.Collection == nil {
+			return nil, fmt.Errorf("%s without element type", t.Name)
+		}
+		elem, err := jsonType(*f.Collection)
+		if err != nil {
+			return nil, err
+		}
+		t.Elem = elem
+	case "RECORD":
+		for _, field := range f.Fields {
+			col, err := jsonColumn(field)
+			if err != nil {
+				return nil, err
+			}
+			t.Fields = append(t.Fields, col)
+		}
+	default:
+		return nil, fmt.Errorf("unknown type %q", f.Type)
+	}
+	return t, nil
+}
+
+// jsonDefault returns a default value as written in DDL: quoted for strings,
+// as is for numbers, booleans and ENUM symbols
+func jsonDefault(raw json.RawMessage, typ *ddl.Type) string {
+	if len(raw) == 0 || string(raw) == "null" {
+		return ""
+	}
+	var s string
+	if err := json.Unmarshal(raw, &s); err != nil {
+		return string(raw)
+	}
+	switch typ.Name {
+	case "STRING", "TIMESTAMP":
+		return strconv.Quote(s)
+	}
+	return s
+}
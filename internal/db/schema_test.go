@@ -0,0 +1,241 @@
+package db
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/oracle/nosql-go-sdk/nosqldb"
+)
+
+const usersJSONSchema = `{
+  "json_version": 1,
+  "type": "table",
+  "name": "users",
+  "namespace": "ns1",
+  "ttl": "5 DAYS",
+  "shardKey": ["id"],
+  "primaryKey": ["id", "name"],
+  "primaryKeySizes": [3, 0],
+  "fields": [
+    {"name": "id", "type": "INTEGER", "nullable": false, "default": null},
+    {"name": "name", "type": "STRING", "nullable": false, "default": null},
+    {"name": "status", "type": "STRING", "nullable": true, "default": "new"},
+    {"name": "created", "type": "TIMESTAMP", "precision": 3, "nullable": true},
+    {"name": "digest", "type": "FIXED_BINARY", "size": 16, "nullable": true},
+    {"name": "level", "type": "ENUM", "symbols": ["LOW", "HIGH"], "nullable": true, "default": "LOW"},
+    {"name": "address", "type": "RECORD", "nullable": true, "fields": [
+      {"name": "city", "type": "STRING", "nullable": true},
+      {"name": "geo", "type": "RECORD", "nullable": true, "fields": [
+        {"name": "lat", "type": "DOUBLE", "nullable": true}
+      ]}
+    ]},
+    {"name": "tags", "type": "ARRAY", "collection": {"type": "STRING"}, "nullable": true},
+    {"name": "scores", "type": "MAP", "collection": {"type": "ARRAY", "collection": {"type": "LONG"}}, "nullable": true},
+    {"name": "doc", "type": "JSON", "nullable": true},
+    {"name": "uid", "type": "STRING", "asUuid": true, "generated": true, "nullable": false}
+  ],
+  "identity": {"name": "id", "always": true}
+}`
+
+// phonesJSONSchema is a child table of usersJSONSchema: its key starts with the inherited id and name
+const phonesJSONSchema = `{
+  "json_version": 1,
+  "type": "table",
+  "name": "phones",
+  "parent": "users",
+  "namespace": "ns1",
+  "shardKey": ["id"],
+  "primaryKey": ["id", "name", "phone_id"],
+  "primaryKeySizes": [3, 0, 2],
+  "fields": [
+    {"name": "id", "type": "INTEGER", "nullable": false},
+    {"name": "name", "type": "STRING", "nullable": false},
+    {"name": "phone_id", "type": "INTEGER", "nullable": false},
+    {"name": "number", "type": "STRING", "nullable": true}
+  ]
+}`
+
+func TestParseJSONSchema(t *testing.T) {
+	table, err := ParseJSONSchema(usersJSONSchema, nil)
+	if err != nil {
+		t.Fatalf("ParseJSONSchema() error = %v", err)
+	}
+	if table.Name != "ns1:users" {
+		t.Errorf("Name = %q, want ns1:users", table.Name)
+	}
+	if !reflect.DeepEqual(table.PrimaryKey, []string{"id", "name"}) || !reflect.DeepEqual(table.ShardKey, []string{"id"}) {
+		t.Errorf("PrimaryKey = %v, ShardKey = %v", table.PrimaryKey, table.ShardKey)
+	}
+	if table.TTL == nil || table.TTL.Value != 5 || table.TTL.Unit != "DAYS" {
+		t.Errorf("TTL = %+v, want 5 DAYS", table.TTL)
+	}
+
+	types := map[string]string{
+		"id":      "INTEGER",
+		"name":    "STRING",
+		"status":  "STRING",
+		"created": "TIMESTAMP(3)",
+		"digest":  "BINARY(16)",
+		"level":   "ENUM(LOW, HIGH)",
+		"address": "RECORD(city STRING, geo RECORD(lat DOUBLE))",
+		"tags":    "ARRAY(STRING)",
+		"scores":  "MAP(ARRAY(LONG))",
+		"doc":     "JSON",
+		"uid":     "STRING",
+	}
+	if len(table.Columns) != len(types) {
+		t.Fatalf("got %d columns, want %d", len(table.Columns), len(types))
+	}
+	for _, col := range table.Columns {
+		if got := col.Type.String(); got != types[col.Name] {
+			t.Errorf("column %s type = %q, want %q", col.Name, got, types[col.Name])
+		}
+	}
+
+	id := table.Column("id")
+	if !id.PrimaryKey || id.KeySize != 3 || id.Identity == nil || !id.Identity.Always {
+		t.Errorf("id = %+v, want an identity key column of size 3", id)
+	}
+	if got := table.Column("status").Default; got != `"new"` {
+		t.Errorf("status default = %q, want quoted", got)
+	}
+	if got := table.Column("level").Default; got != "LOW" {
+		t.Errorf("level default = %q, want LOW", got)
+	}
+	if uid := table.Column("uid"); !uid.NotNull || !uid.Generated() {
+		t.Errorf("uid = %+v, want a NOT NULL generated UUID", uid)
+	}
+	if table.Column("doc").NotNull {
+		t.Error("doc should be nullable")
+	}
+}
+
+func TestParseJSONSchemaChildTable(t *testing.T) {
+	table, err := ParseJSONSchema(phonesJSONSchema, []string{"id", "name"})
+	if err != nil {
+		t.Fatalf("ParseJSONSchema() error = %v", err)
+	}
+	if table.Name != "ns1:users.phones" {
+		t.Errorf("Name = %q, want ns1:users.phones", table.Name)
+	}
+	if !reflect.DeepEqual(table.PrimaryKey, []string{"phone_id"}) || !reflect.DeepEqual(table.ShardKey, []string{"phone_id"}) {
+		t.Errorf("PrimaryKey = %v, ShardKey = %v, want the own key only", table.PrimaryKey, table.ShardKey)
+	}
+	var cols []string
+	for _, col := range table.Columns {
+		cols = append(cols, col.Name)
+	}
+	if !reflect.DeepEqual(cols, []string{"phone_id", "number"}) {
+		t.Errorf("columns = %v, want the inherited key columns removed", cols)
+	}
+	if id := table.Column("phone_id"); !id.PrimaryKey || id.KeySize != 2 {
+		t.Errorf("phone_id = %+v, want a key column of size 2", id)
+	}
+}
+
+func TestParseJSONSchemaErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		schema string
+	}{
+		{name: "not JSON", schema: "CREATE TABLE t (id INTEGER, PRIMARY KEY(id))"},
+		{name: "no fields", schema: `{"name": "t", "primaryKey": ["id"]}`},
+		{name: "unknown type", schema: `{"name": "t", "primaryKey": ["id"], "fields": [{"name": "id", "type": "UUID"}]}`},
+		{name: "collection without element", schema: `{"name": "t", "primaryKey": ["id"], "fields": [{"name": "id", "type": "ARRAY"}]}`},
+		{name: "missing key column", schema: `{"name": "t", "primaryKey": ["x"], "fields": [{"name": "id", "type": "INTEGER"}]}`},
+		{name: "bad TTL", schema: `{"name": "t", "ttl": "5 WEEKS", "primaryKey": ["id"], "fields": [{"name": "id", "type": "INTEGER"}]}`},
+		{name: "child without parent key", schema: phonesJSONSchema},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			table, err := ParseJSONSchema(tt.schema, nil)
+			if err == nil {
+				t.Error("ParseJSONSchema() should fail")
+			}
+			if table == nil {
+				t.Error("ParseJSONSchema() should return a partial table")
+			}
+		})
+	}
+}
+
+func TestTableSchema(t *testing.T) {
+	const ddlText = "CREATE TABLE users (id INTEGER, name STRING, PRIMARY KEY(id)) USING TTL 2 HOURS"
+
+	tests := []struct {
+		name      string
+		table     *nosqldb.TableResult
+		parent    *nosqldb.TableResult
+		wantCols  []string
+		wantTTL   string
+		wantError bool
+	}{
+		{
+			name:     "JSON schema",
+			table:    &nosqldb.TableResult{TableName: "users", Schema: usersJSONSchema, DDL: ddlText},
+			wantCols: []string{"id", "name", "status", "created", "digest", "level", "address", "tags", "scores", "doc", "uid"},
+			wantTTL:  "5 DAYS",
+		},
+		{
+			name:     "child table read with its parent's key",
+			table:    &nosqldb.TableResult{TableName: "users.phones", Schema: phonesJSONSchema},
+			parent:   &nosqldb.TableResult{TableName: "users", Schema: usersJSONSchema},
+			wantCols: []string{"phone_id", "number"},
+		},
+		{
+			name: "child table without its parent falls back to the DDL",
+			table: &nosqldb.TableResult{TableName: "users.phones", Schema: phonesJSONSchema,
+				DDL: "CREATE TABLE users.phones (phone_id INTEGER, number STRING, PRIMARY KEY(phone_id))"},
+			wantCols: []string{"phone_id", "number"},
+		},
+		{
+			name:     "DDL fallback",
+			table:    &nosqldb.TableResult{TableName: "users", DDL: ddlText},
+			wantCols: []string{"id", "name"},
+			wantTTL:  "2 HOURS",
+		},
+		{
+			name:     "unreadable JSON falls back to the DDL",
+			table:    &nosqldb.TableResult{TableName: "users", Schema: "{", DDL: ddlText},
+			wantCols: []string{"id", "name"},
+			wantTTL:  "2 HOURS",
+		},
+		{
+			name:      "no schema",
+			table:     &nosqldb.TableResult{TableName: "users"},
+			wantError: true,
+		},
+		{
+			name:      "no table",
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			table, err := TableSchema(tt.table, tt.parent)
+			if (err != nil) != tt.wantError {
+				t.Fatalf("TableSchema() error = %v, wantError %v", err, tt.wantError)
+			}
+			if table == nil {
+				t.Fatal("TableSchema() returned nil")
+			}
+			var cols []string
+			for _, col := range table.Columns {
+				cols = append(cols, col.Name)
+			}
+			if !reflect.DeepEqual(cols, tt.wantCols) {
+				t.Errorf("columns = %v, want %v", cols, tt.wantCols)
+			}
+			var ttl string
+			if table.TTL != nil {
+				ttl = fmt.Sprintf("%d %s", table.TTL.Value, table.TTL.Unit)
+			}
+			if ttl != tt.wantTTL {
+				t.Errorf("TTL = %q, want %q", ttl, tt.wantTTL)
+			}
+		})
+	}
+}
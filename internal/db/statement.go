@@ -0,0 +1,142 @@
+package db
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// StatementKind classifies a SQL statement by what it can change
+type StatementKind int
+
+const (
+	StatementUnknown StatementKind = iota
+	StatementQuery                 // SELECT
+	StatementDML                   // INSERT, UPSERT, UPDATE, DELETE
+	StatementDDL                   // CREATE, DROP, ALTER of tables and indexes
+	StatementSystem                // GRANT, REVOKE, CREATE/DROP NAMESPACE, USER or ROLE, ALTER USER
+)
+
+// systemObjects are the objects of CREATE, DROP and ALTER statements that are
+// run as system requests rather than table requests
+var systemObjects = []string{"NAMESPACE", "USER", "ROLE"}
+
+// ClassifyStatement returns the kind of a statement and its leading keyword in upper case.
+// Leading comments and a DECLARE prolog of external variables are skipped.
+func ClassifyStatement(sql string) (StatementKind, string) {
+	s := sql
+	inProlog := false
+	for {
+		s = skipSpaceAndComments(s)
+		if inProlog && strings.HasPrefix(s, "$") {
+			// External variable declaration: "$name TYPE;"
+			end := strings.IndexByte(s, ';')
+			if end < 0 {
+				return StatementUnknown, ""
+			}
+			s = s[end+1:]
+			continue
+		}
+
+		word := leadingWord(s)
+		keyword := strings.ToUpper(word)
+		switch keyword {
+		case "DECLARE":
+			inProlog = true
+			s = s[len(word):]
+			continue
+		case "SELECT":
+			return StatementQuery, keyword
+		case "INSERT", "UPSERT", "UPDATE", "DELETE":
+			return StatementDML, keyword
+		case "GRANT", "REVOKE":
+			return StatementSystem, keyword
+		case "CREATE", "DROP", "ALTER":
+			object := strings.ToUpper(leadingWord(skipSpaceAndComments(s[len(word):])))
+			if slices.Contains(systemObjects, object) {
+				return StatementSystem, keyword
+			}
+			return StatementDDL, keyword
+		}
+		return StatementUnknown, keyword
+	}
+}
+
+// CheckReadOnly returns an error unless the statement is a query.
+// It guards read-only connections before a statement is prepared.
+func CheckReadOnly(sql string) error {
+	kind, keyword := ClassifyStatement(sql)
+	switch {
+	case kind == StatementQuery:
+		return nil
+	case kind == StatementUnknown || keyword == "":
+		return fmt.Errorf("read-only connection: only SELECT statements are allowed")
+	}
+	return fmt.Errorf("read-only connection: %s statements are not allowed", keyword)
+}
+
+// affectedRowsFields are the fields of the single row returned by a DML statement
+// without a RETURNING clause. Their names differ between statements and server versions.
+var affectedRowsFields = []string{
+	"NumRowsInserted",
+	"NumRowsUpdated",
+	"NumRowsDeleted",
+	"NumberOfUpdatedRows",
+	"NumberOfDeletions",
+}
+
+// affectedRows returns the number of rows changed by a DML statement from its result rows.
+// A statement with a RETURNING clause returns one row per changed row instead of a count.
+func affectedRows(rows []map[string]interface{}) int {
+	if len(rows) == 1 && len(rows[0]) == 1 {
+		for name, value := range rows[0] {
+			for _, field := range affectedRowsFields {
+				if !strings.EqualFold(name, field) {
+					continue
+				}
+				switch n := value.(type) {
+				case int:
+					return n
+				case int64:
+					return int(n)
+				case float64:
+					return int(n)
+				}
+			}
+		}
+	}
+	return len(rows)
+}
+
+// skipSpaceAndComments removes leading white space and /* */, // and -- comments
+func skipSpaceAndComments(s string) string {
+	for {
+		s = strings.TrimLeftFunc(s, unicode.IsSpace)
+		switch {
+		case strings.HasPrefix(s, "/*"):
+			end := strings.Index(s[2:], "*/")
+			if end < 0 {
+				return ""
+			}
+			s = s[end+4:]
+		case strings.HasPrefix(s, "//"), strings.HasPrefix(s, "--"):
+			end := strings.IndexByte(s, '\n')
+			if end < 0 {
+				return ""
+			}
+			s = s[end+1:]
+		default:
+			return s
+		}
+	}
+}
+
+// leadingWord returns the letters at the start of s
+func leadingWord(s string) string {
+	end := strings.IndexFunc(s, func(r rune) bool { return !unicode.IsLetter(r) })
+	if end < 0 {
+		return s
+	}
+	return s[:end]
+}
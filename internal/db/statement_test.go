@@ -0,0 +1,87 @@
+package db
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestClassifyStatement(t *testing.T) {
+	tests := []struct {
+		sql         string
+		wantKind    StatementKind
+		wantKeyword string
+	}{
+		{sql: "SELECT * FROM users", wantKind: StatementQuery, wantKeyword: "SELECT"},
+		{sql: "  select id from users", wantKind: StatementQuery, wantKeyword: "SELECT"},
+		{sql: "/* report */ SELECT 1 FROM users", wantKind: StatementQuery, wantKeyword: "SELECT"},
+		{sql: "-- note\n// another\nSELECT 1 FROM users", wantKind: StatementQuery, wantKeyword: "SELECT"},
+		{sql: "DECLARE $id INTEGER; $name STRING; SELECT * FROM users WHERE id = $id", wantKind: StatementQuery, wantKeyword: "SELECT"},
+		{sql: "DECLARE $id INTEGER; DELETE FROM users WHERE id = $id", wantKind: StatementDML, wantKeyword: "DELETE"},
+		{sql: "INSERT INTO users VALUES (1, 'a')", wantKind: StatementDML, wantKeyword: "INSERT"},
+		{sql: "upsert into users values (1, 'a')", wantKind: StatementDML, wantKeyword: "UPSERT"},
+		{sql: "UPDATE users SET name = 'b' WHERE id = 1", wantKind: StatementDML, wantKeyword: "UPDATE"},
+		{sql: "/* SELECT */ DELETE FROM users", wantKind: StatementDML, wantKeyword: "DELETE"},
+		{sql: "CREATE TABLE t (id INTEGER, PRIMARY KEY(id))", wantKind: StatementDDL, wantKeyword: "CREATE"},
+		{sql: "DROP TABLE t", wantKind: StatementDDL, wantKeyword: "DROP"},
+		{sql: "ALTER TABLE t (ADD age INTEGER)", wantKind: StatementDDL, wantKeyword: "ALTER"},
+		{sql: "CREATE INDEX idx ON t (name)", wantKind: StatementDDL, wantKeyword: "CREATE"},
+		{sql: "GRANT READ_TABLE ON t TO user1", wantKind: StatementSystem, wantKeyword: "GRANT"},
+		{sql: "revoke readwrite from user user1", wantKind: StatementSystem, wantKeyword: "REVOKE"},
+		{sql: "CREATE NAMESPACE ns1", wantKind: StatementSystem, wantKeyword: "CREATE"},
+		{sql: "drop namespace if exists ns1 cascade", wantKind: StatementSystem, wantKeyword: "DROP"},
+		{sql: "CREATE USER user1 IDENTIFIED BY 'secret'", wantKind: StatementSystem, wantKeyword: "CREATE"},
+		{sql: "ALTER USER user1 ACCOUNT LOCK", wantKind: StatementSystem, wantKeyword: "ALTER"},
+		{sql: "CREATE /* admin */ ROLE admins", wantKind: StatementSystem, wantKeyword: "CREATE"},
+		{sql: "DROP ROLE admins", wantKind: StatementSystem, wantKeyword: "DROP"},
+		{sql: "SHOW TABLES", wantKind: StatementUnknown, wantKeyword: "SHOW"},
+		{sql: "(SELECT 1)", wantKind: StatementUnknown, wantKeyword: ""},
+		{sql: "/* unterminated SELECT", wantKind: StatementUnknown, wantKeyword: ""},
+		{sql: "", wantKind: StatementUnknown, wantKeyword: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.sql, func(t *testing.T) {
+			kind, keyword := ClassifyStatement(tt.sql)
+			if kind != tt.wantKind || keyword != tt.wantKeyword {
+				t.Errorf("ClassifyStatement() = %v, %q, want %v, %q", kind, keyword, tt.wantKind, tt.wantKeyword)
+			}
+		})
+	}
+}
+
+func TestCheckReadOnly(t *testing.T) {
+	if err := CheckReadOnly("SELECT * FROM users"); err != nil {
+		t.Errorf("CheckReadOnly(SELECT) error = %v", err)
+	}
+	if err := CheckReadOnly("update users set a = 1"); err == nil || !strings.Contains(err.Error(), "UPDATE statements are not allowed") {
+		t.Errorf("CheckReadOnly(UPDATE) error = %v", err)
+	}
+	if err := CheckReadOnly("SHOW TABLES"); err == nil || !strings.Contains(err.Error(), "only SELECT") {
+		t.Errorf("CheckReadOnly(SHOW) error = %v", err)
+	}
+}
+
+func TestAffectedRows(t *testing.T) {
+	tests := []struct {
+		name string
+		rows []map[string]interface{}
+		want int
+	}{
+		{name: "update count", rows: []map[string]interface{}{{"NumRowsUpdated": 3}}, want: 3},
+		{name: "insert count", rows: []map[string]interface{}{{"NumRowsInserted": int64(1)}}, want: 1},
+		{name: "delete count", rows: []map[string]interface{}{{"numRowsDeleted": int64(5)}}, want: 5},
+		{name: "legacy update count", rows: []map[string]interface{}{{"NumberOfUpdatedRows": float64(2)}}, want: 2},
+		{name: "nothing deleted", rows: []map[string]interface{}{{"NumberOfDeletions": 0}}, want: 0},
+		{name: "returning rows", rows: []map[string]interface{}{{"id": 1}, {"id": 2}}, want: 2},
+		{name: "returning one column", rows: []map[string]interface{}{{"id": 7}}, want: 1},
+		{name: "no rows", rows: nil, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := affectedRows(tt.rows); got != tt.want {
+				t.Errorf("affectedRows() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,147 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/oracle/nosql-go-sdk/nosqldb"
+	"github.com/oracle/nosql-go-sdk/nosqldb/types"
+)
+
+// MaxBatchRows is the largest number of rows written with one WriteMultiple request.
+// It matches the Cloud Service limit on operations per request.
+const MaxBatchRows = 50
+
+// ErrRowExists is reported for a row that was not inserted because its primary key already exists.
+var ErrRowExists = errors.New("row already exists")
+
+// PutRows writes rows to tableName and returns one error per row (nil when written).
+// Rows sent together must share a shard key; more than one row is written with a
+// single WriteMultiple request. If that request is rejected as a whole, the rows are
+// written one at a time so the failure is attributed to the offending rows.
+// With ifAbsent, existing rows are left untouched and reported as ErrRowExists.
+func PutRows(client *nosqldb.Client, tableName string, rows []*types.MapValue, ifAbsent bool) []error {
+	errs := make([]error, len(rows))
+	if len(rows) == 1 {
+		errs[0] = putRow(client, tableName, rows[0], ifAbsent)
+		return errs
+	}
+
+	req := &nosqldb.WriteMultipleRequest{}
+	for _, row := range rows {
+		if err := req.AddPutRequest(newPutRequest(tableName, row, ifAbsent), false); err != nil {
+			return putRowsOneByOne(client, tableName, rows, ifAbsent)
+		}
+	}
+
+	result, err := client.WriteMultiple(req)
+	if err != nil || result == nil || len(result.ResultSet) != len(rows) {
+		return putRowsOneByOne(client, tableName, rows, ifAbsent)
+	}
+	for i, op := range result.ResultSet {
+		if !op.Success {
+			errs[i] = putFailure(ifAbsent)
+		}
+	}
+	return errs
+}
+
+// putRowsOneByOne writes each row with its own request
+func putRowsOneByOne(client *nosqldb.Client, tableName string, rows []*types.MapValue, ifAbsent bool) []error {
+	errs := make([]error, len(rows))
+	for i, row := range rows {
+		errs[i] = putRow(client, tableName, row, ifAbsent)
+	}
+	return errs
+}
+
+// putRow writes a single row
+func putRow(client *nosqldb.Client, tableName string, row *types.MapValue, ifAbsent bool) error {
+	result, err := client.Put(newPutRequest(tableName, row, ifAbsent))
+	if err != nil {
+		return err
+	}
+	if result.Version == nil {
+		return putFailure(ifAbsent)
+	}
+	return nil
+}
+
+func newPutRequest(tableName string, row *types.MapValue, ifAbsent bool) *nosqldb.PutRequest {
+	req := &nosqldb.PutRequest{TableName: tableName, Value: row}
+	if ifAbsent {
+		req.PutOption = types.PutIfAbsent
+	}
+	return req
+}
+
+// putFailure returns the reason a put without an error did not write the row
+func putFailure(ifAbsent bool) error {
+	if ifAbsent {
+		return ErrRowExists
+	}
+	return fmt.Errorf("row not written")
+}
+
+// ErrRowChanged is reported when a conditional write finds that the row was
+// modified or deleted after it was read.
+var ErrRowChanged = errors.New("row was changed or deleted since it was read")
+
+// GetRow reads the row with the given primary key and returns it with its version.
+// A missing row is returned as nil without an error.
+func GetRow(client *nosqldb.Client, tableName string, key *types.MapValue) (*types.MapValue, types.Version, error) {
+	result, err := client.Get(&nosqldb.GetRequest{TableName: tableName, Key: key})
+	if err != nil {
+		return nil, nil, err
+	}
+	if !result.RowExists() {
+		return nil, nil, nil
+	}
+	return result.Value, result.Version, nil
+}
+
+// PutRowIfVersion replaces a row only if its version still matches version,
+// returning the new version. A mismatch is reported as ErrRowChanged.
+func PutRowIfVersion(client *nosqldb.Client, tableName string, row *types.MapValue, version types.Version) (types.Version, error) {
+	result, err := client.Put(&nosqldb.PutRequest{
+		TableName:    tableName,
+		Value:        row,
+		PutOption:    types.PutIfVersion,
+		MatchVersion: version,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if result.Version == nil {
+		return nil, ErrRowChanged
+	}
+	return result.Version, nil
+}
+
+// DisplayRow converts a row read or written with the SDK to the form used for query results
+func DisplayRow(row *types.MapValue) map[string]interface{} {
+	return convertRowValues(row.Map())
+}
+
+// DeleteRow deletes the row with the given primary key and returns the deleted row,
+// so it can be written back to undo the deletion. The row is read first and deleted
+// only if it has not changed since; a mismatch is reported as ErrRowChanged.
+// A missing row is returned as nil without an error.
+func DeleteRow(client *nosqldb.Client, tableName string, key *types.MapValue) (*types.MapValue, error) {
+	row, version, err := GetRow(client, tableName, key)
+	if err != nil || row == nil {
+		return nil, err
+	}
+	result, err := client.Delete(&nosqldb.DeleteRequest{
+		TableName:    tableName,
+		Key:          key,
+		MatchVersion: version,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !result.Success {
+		return nil, ErrRowChanged
+	}
+	return row, nil
+}
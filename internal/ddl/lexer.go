@@ -0,0 +1,139 @@
+package ddl
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// tokenKind classifies a token of a DDL statement
+type tokenKind int
+
+const (
+	tokenEOF    tokenKind = iota
+	tokenIdent            // Identifier or keyword
+	tokenNumber           // Integer or decimal number, possibly negative
+	tokenString           // Single or double quoted string
+	tokenPunct            // One of ( ) , . : ;
+)
+
+// token is a lexical token with the line it starts on
+type token struct {
+	kind tokenKind
+	text string // Source text; strings keep their quotes
+	line int
+}
+
+// String describes the token for error messages
+func (t token) String() string {
+	if t.kind == tokenEOF {
+		return "end of statement"
+	}
+	return fmt.Sprintf("%q", t.text)
+}
+
+// is reports whether the token is the keyword or punctuation s (case-insensitive)
+func (t token) is(s string) bool {
+	return (t.kind == tokenIdent || t.kind == tokenPunct) && strings.EqualFold(t.text, s)
+}
+
+// tokenize splits a statement into tokens, skipping whitespace and comments
+// (/* ... */, // ... and # ...)
+func tokenize(src string) ([]token, error) {
+	var tokens []token
+	runes := []rune(src)
+	line := 1
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		start := i
+		switch {
+		case r == '\n':
+			line++
+			i++
+
+		case unicode.IsSpace(r):
+			i++
+
+		case r == '#' || (r == '/' && i+1 < len(runes) && runes[i+1] == '/'):
+			for i < len(runes) && runes[i] != '\n' {
+				i++
+			}
+
+		case r == '/' && i+1 < len(runes) && runes[i+1] == '*':
+			startLine := line
+			i += 2
+			for i < len(runes) && !(runes[i] == '*' && i+1 < len(runes) && runes[i+1] == '/') {
+				if runes[i] == '\n' {
+					line++
+				}
+				i++
+			}
+			if i >= len(runes) {
+				return nil, fmt.Errorf("line %d: unterminated comment", startLine)
+			}
+			i += 2
+
+		case r == '\'' || r == '"':
+			startLine := line
+			i++
+			for i < len(runes) && runes[i] != r {
+				if runes[i] == '\\' {
+					i++
+				}
+				if i < len(runes) && runes[i] == '\n' {
+					line++
+				}
+				i++
+			}
+			if i >= len(runes) {
+				return nil, fmt.Errorf("line %d: unterminated string", startLine)
+			}
+			i++
+			tokens = append(tokens, token{kind: tokenString, text: string(runes[start:i]), line: startLine})
+
+		case isDigit(r) || (r == '-' && i+1 < len(runes) && isDigit(runes[i+1])):
+			i++
+			for i < len(runes) && (isDigit(runes[i]) || runes[i] == '.') {
+				i++
+			}
+			// Exponent, e.g. 1.5E-3
+			if i < len(runes) && (runes[i] == 'e' || runes[i] == 'E') {
+				j := i + 1
+				if j < len(runes) && (runes[j] == '+' || runes[j] == '-') {
+					j++
+				}
+				if j < len(runes) && isDigit(runes[j]) {
+					for i = j; i < len(runes) && isDigit(runes[i]); i++ {
+					}
+				}
+			}
+			tokens = append(tokens, token{kind: tokenNumber, text: string(runes[start:i]), line: line})
+
+		case isIdentStart(r):
+			for i < len(runes) && isIdentPart(runes[i]) {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenIdent, text: string(runes[start:i]), line: line})
+
+		case strings.ContainsRune("(),.:;", r):
+			i++
+			tokens = append(tokens, token{kind: tokenPunct, text: string(r), line: line})
+
+		default:
+			return nil, fmt.Errorf("line %d: unexpected character %q", line, r)
+		}
+	}
+	return append(tokens, token{kind: tokenEOF, line: line}), nil
+}
+
+func isDigit(r rune) bool {
+	return r >= '0' && r <= '9'
+}
+
+func isIdentStart(r rune) bool {
+	return unicode.IsLetter(r) || r == '_' || r == '$'
+}
+
+func isIdentPart(r rune) bool {
+	return isIdentStart(r) || isDigit(r)
+}
@@ -0,0 +1,598 @@
+// Package ddl parses Oracle NoSQL CREATE TABLE statements into a schema model.
+package ddl
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Table is a parsed CREATE TABLE statement.
+type Table struct {
+	Name           string // As written, including namespace and parents, e.g. "ns1:users.addresses"
+	IfNotExists    bool
+	Comment        string
+	Columns        []*Column
+	PrimaryKey     []string // Primary key columns in key order (for child tables, without the inherited keys)
+	ShardKey       []string // The SHARD(...) part of the primary key, or the whole key when not given
+	TTL            *TTL
+	JSONCollection bool     // AS JSON COLLECTION
+	SchemaFrozen   bool     // WITH SCHEMA FROZEN
+	Regions        []string // IN REGIONS ...
+}
+
+// Column is a column of a table or a field of a RECORD type.
+type Column struct {
+	Name       string
+	Type       *Type
+	NotNull    bool
+	Default    string    // Default value as written (strings keep their quotes); "" when none
+	Identity   *Identity // Identity generator; nil when not an identity column
+	UUID       bool      // STRING AS UUID
+	UUIDGen    bool      // AS UUID GENERATED BY DEFAULT
+	MRCounter  bool      // AS MR_COUNTER
+	Comment    string
+	PrimaryKey bool
+	KeySize    int // Storage size of an INTEGER key column, e.g. 3 for PRIMARY KEY(id(3)); 0 when not given
+}
+
+// Identity describes a GENERATED ... AS IDENTITY column.
+type Identity struct {
+	Always  bool     // GENERATED ALWAYS; otherwise BY DEFAULT
+	OnNull  bool     // BY DEFAULT ON NULL
+	Options []string // Sequence options, e.g. "START WITH 1", "NO CYCLE"
+}
+
+// TTL is the default time to live of a table's rows.
+type TTL struct {
+	Value int
+	Unit  string // DAYS or HOURS
+}
+
+// Type is a column type; complex types nest other types.
+type Type struct {
+	Name   string    // Upper-case type name, e.g. INTEGER, TIMESTAMP, ARRAY
+	Size   int       // TIMESTAMP precision or fixed BINARY size; -1 when not given
+	Values []string  // ENUM values
+	Elem   *Type     // ARRAY and MAP element type
+	Fields []*Column // RECORD fields
+}
+
+// identityOptions start the sequence options of an identity column
+var identityOptions = []string{"START", "INCREMENT", "MAXVALUE", "MINVALUE", "CACHE", "CYCLE", "NO"}
+
+// String returns the type as written in DDL, e.g. "MAP(ARRAY(STRING))" or "TIMESTAMP(3)".
+func (t *Type) String() string {
+	if t == nil {
+		return ""
+	}
+	switch {
+	case t.Elem != nil:
+		return t.Name + "(" + t.Elem.String() + ")"
+	case t.Name == "RECORD":
+		fields := make([]string, len(t.Fields))
+		for i, f := range t.Fields {
+			fields[i] = f.Definition()
+		}
+		return "RECORD(" + strings.Join(fields, ", ") + ")"
+	case t.Name == "ENUM":
+		return "ENUM(" + strings.Join(t.Values, ", ") + ")"
+	case t.Size >= 0:
+		return fmt.Sprintf("%s(%d)", t.Name, t.Size)
+	}
+	return t.Name
+}
+
+// IsComplex reports whether values of the type are JSON-like (JSON, ARRAY, MAP or RECORD).
+func (t *Type) IsComplex() bool {
+	switch t.Name {
+	case "JSON", "ARRAY", "MAP", "RECORD":
+		return true
+	}
+	return false
+}
+
+// Definition returns the column as written in DDL, without its comment,
+// e.g. "id INTEGER GENERATED ALWAYS AS IDENTITY".
+func (c *Column) Definition() string {
+	parts := []string{c.Name, c.Type.String()}
+	parts = append(parts, c.Attributes()...)
+	return strings.Join(parts, " ")
+}
+
+// Attributes returns the clauses that follow the column type, e.g. ["DEFAULT 0", "NOT NULL"].
+func (c *Column) Attributes() []string {
+	var attrs []string
+	if c.Default != "" {
+		attrs = append(attrs, "DEFAULT "+c.Default)
+	}
+	if c.NotNull {
+		attrs = append(attrs, "NOT NULL")
+	}
+	if id := c.Identity; id != nil {
+		generated := "GENERATED BY DEFAULT"
+		switch {
+		case id.Always:
+			generated = "GENERATED ALWAYS"
+		case id.OnNull:
+			generated += " ON NULL"
+		}
+		generated += " AS IDENTITY"
+		if len(id.Options) > 0 {
+			generated += " (" + strings.Join(id.Options, " ") + ")"
+		}
+		attrs = append(attrs, generated)
+	}
+	switch {
+	case c.UUIDGen:
+		attrs = append(attrs, "AS UUID GENERATED BY DEFAULT")
+	case c.UUID:
+		attrs = append(attrs, "AS UUID")
+	}
+	if c.MRCounter {
+		attrs = append(attrs, "AS MR_COUNTER")
+	}
+	return attrs
+}
+
+// Generated reports whether the server can generate the column's value
+// (identity columns and generated UUIDs).
+func (c *Column) Generated() bool {
+	return c.Identity != nil || c.UUIDGen
+}
+
+// Column returns the column named name (case-insensitive), or nil.
+func (t *Table) Column(name string) *Column {
+	for _, col := range t.Columns {
+		if strings.EqualFold(col.Name, name) {
+			return col
+		}
+	}
+	return nil
+}
+
+// Parse parses a CREATE TABLE statement. On a syntax error it returns the
+// part of the table parsed so far together with the error.
+func Parse(statement string) (*Table, error) {
+	tokens, err := tokenize(statement)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens, keySizes: make(map[string]int)}
+	t := &Table{}
+	err = p.parseTable(t)
+	return t, err
+}
+
+// parser consumes the tokens of one statement
+type parser struct {
+	tokens   []token
+	pos      int
+	keySizes map[string]int // Sizes of primary key columns, by lower-case name
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokenEOF {
+		p.pos++
+	}
+	return tok
+}
+
+// accept consumes the keywords or punctuation words if they come next
+func (p *parser) accept(words ...string) bool {
+	for i, word := range words {
+		if p.pos+i >= len(p.tokens) || !p.tokens[p.pos+i].is(word) {
+			return false
+		}
+	}
+	p.pos += len(words)
+	return true
+}
+
+// expect consumes the keywords or punctuation words, or fails
+func (p *parser) expect(words ...string) error {
+	for _, word := range words {
+		if !p.accept(word) {
+			return p.errorf("expected %s but found %s", word, p.peek())
+		}
+	}
+	return nil
+}
+
+func (p *parser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("line %d: %s", p.peek().line, fmt.Sprintf(format, args...))
+}
+
+func (p *parser) ident() (string, error) {
+	tok := p.peek()
+	if tok.kind != tokenIdent {
+		return "", p.errorf("expected a name but found %s", tok)
+	}
+	p.next()
+	return tok.text, nil
+}
+
+func (p *parser) integer() (int, error) {
+	tok := p.peek()
+	n, err := strconv.Atoi(tok.text)
+	if tok.kind != tokenNumber || err != nil {
+		return 0, p.errorf("expected a whole number but found %s", tok)
+	}
+	p.next()
+	return n, nil
+}
+
+func (p *parser) stringLiteral() (string, error) {
+	tok := p.peek()
+	if tok.kind != tokenString {
+		return "", p.errorf("expected a string but found %s", tok)
+	}
+	p.next()
+	return unquote(tok.text), nil
+}
+
+// unquote removes the quotes of a string literal and resolves backslash escapes
+func unquote(s string) string {
+	var b strings.Builder
+	runes := []rune(s[1 : len(s)-1])
+	for i := 0; i < len(runes); i++ {
+		if runes[i] == '\\' && i+1 < len(runes) {
+			i++
+			switch runes[i] {
+			case 'n':
+				b.WriteRune('\n')
+			case 't':
+				b.WriteRune('\t')
+			default:
+				b.WriteRune(runes[i])
+			}
+			continue
+		}
+		b.WriteRune(runes[i])
+	}
+	return b.String()
+}
+
+// tableName parses [namespace:]name[.child...]
+func (p *parser) tableName() (string, error) {
+	path := func() (string, error) {
+		name, err := p.ident()
+		for err == nil && p.accept(".") {
+			var part string
+			part, err = p.ident()
+			name += "." + part
+		}
+		return name, err
+	}
+	name, err := path()
+	if err != nil || !p.accept(":") {
+		return name, err
+	}
+	child, err := path()
+	return name + ":" + child, err
+}
+
+func (p *parser) parseTable(t *Table) error {
+	if err := p.expect("CREATE", "TABLE"); err != nil {
+		return err
+	}
+	t.IfNotExists = p.accept("IF", "NOT", "EXISTS")
+	var err error
+	if t.Name, err = p.tableName(); err != nil {
+		return err
+	}
+	if p.accept("COMMENT") {
+		if t.Comment, err = p.stringLiteral(); err != nil {
+			return err
+		}
+	}
+
+	if err := p.expect("("); err != nil {
+		return err
+	}
+	for {
+		if p.accept("PRIMARY", "KEY") {
+			if err := p.primaryKey(t); err != nil {
+				return err
+			}
+		} else {
+			col, err := p.column()
+			if col != nil {
+				t.Columns = append(t.Columns, col)
+			}
+			if err != nil {
+				return err
+			}
+		}
+		if p.accept(")") {
+			break
+		}
+		if err := p.expect(","); err != nil {
+			return err
+		}
+	}
+	for _, name := range t.PrimaryKey {
+		if col := t.Column(name); col != nil {
+			col.PrimaryKey = true
+			col.KeySize = p.keySizes[strings.ToLower(name)]
+		}
+	}
+
+	// Table options
+	for {
+		switch {
+		case p.accept("USING", "TTL"):
+			value, err := p.integer()
+			if err != nil {
+				return err
+			}
+			unit, err := p.ident()
+			if err != nil {
+				return err
+			}
+			unit = strings.ToUpper(unit)
+			switch unit {
+			case "DAY":
+				unit = "DAYS"
+			case "HOUR":
+				unit = "HOURS"
+			case "DAYS", "HOURS":
+			default:
+				return p.errorf("unknown TTL unit %q", unit)
+			}
+			t.TTL = &TTL{Value: value, Unit: unit}
+		case p.accept("AS", "JSON", "COLLECTION"):
+			t.JSONCollection = true
+		case p.accept("WITH", "SCHEMA", "FROZEN"):
+			t.SchemaFrozen = true
+			p.accept("FORCE")
+		case p.accept("IN", "REGIONS"):
+			for {
+				region, err := p.ident()
+				if err != nil {
+					return err
+				}
+				t.Regions = append(t.Regions, region)
+				if !p.accept(",") {
+					break
+				}
+			}
+		case p.accept(";"), p.peek().kind == tokenEOF:
+			if p.peek().kind != tokenEOF {
+				return p.errorf("unexpected %s after the statement", p.peek())
+			}
+			return nil
+		default:
+			return p.errorf("unexpected %s after the column list", p.peek())
+		}
+	}
+}
+
+// primaryKey parses ([SHARD(keys)] [, keys]) after PRIMARY KEY
+func (p *parser) primaryKey(t *Table) error {
+	if err := p.expect("("); err != nil {
+		return err
+	}
+	if p.accept(")") {
+		return nil
+	}
+	sharded := false
+	for {
+		if p.accept("SHARD") {
+			if sharded || len(t.PrimaryKey) > 0 {
+				return p.errorf("SHARD must come first in the primary key")
+			}
+			sharded = true
+			if err := p.expect("("); err != nil {
+				return err
+			}
+			if err := p.keyList(t); err != nil {
+				return err
+			}
+			if err := p.expect(")"); err != nil {
+				return err
+			}
+			t.ShardKey = append([]string(nil), t.PrimaryKey...)
+		} else if err := p.keyList(t); err != nil {
+			return err
+		}
+		if p.accept(")") {
+			break
+		}
+		if err := p.expect(","); err != nil {
+			return err
+		}
+	}
+	if !sharded {
+		t.ShardKey = append([]string(nil), t.PrimaryKey...)
+	}
+	return nil
+}
+
+// keyList parses key columns with optional sizes, e.g. "a, b(3)"
+func (p *parser) keyList(t *Table) error {
+	for {
+		name, err := p.ident()
+		if err != nil {
+			return err
+		}
+		size := 0
+		if p.accept("(") {
+			if size, err = p.integer(); err != nil {
+				return err
+			}
+			if err := p.expect(")"); err != nil {
+				return err
+			}
+		}
+		t.PrimaryKey = append(t.PrimaryKey, name)
+		if size > 0 {
+			p.keySizes[strings.ToLower(name)] = size
+		}
+		// Stop before the next SHARD or at the end of the key
+		if p.peek().is(")") || (p.peek().is(",") && p.tokens[p.pos+1].is("SHARD")) {
+			return nil
+		}
+		if err := p.expect(","); err != nil {
+			return err
+		}
+	}
+}
+
+// column parses a column or RECORD field definition
+func (p *parser) column() (*Column, error) {
+	name, err := p.ident()
+	if err != nil {
+		return nil, err
+	}
+	col := &Column{Name: name}
+	if col.Type, err = p.typ(); err != nil {
+		return nil, err
+	}
+
+	for {
+		switch {
+		case p.accept("NOT", "NULL"):
+			col.NotNull = true
+		case p.accept("DEFAULT"):
+			tok := p.next()
+			switch tok.kind {
+			case tokenNumber, tokenString, tokenIdent:
+				col.Default = tok.text
+			default:
+				return col, p.errorf("expected a default value but found %s", tok)
+			}
+		case p.accept("GENERATED"):
+			if col.Identity, err = p.identity(); err != nil {
+				return col, err
+			}
+		case p.accept("AS", "UUID"):
+			col.UUID = true
+			if p.accept("GENERATED", "BY", "DEFAULT") {
+				col.UUIDGen = true
+			}
+		case p.accept("AS", "MR_COUNTER"):
+			col.MRCounter = true
+		case p.accept("COMMENT"):
+			if col.Comment, err = p.stringLiteral(); err != nil {
+				return col, err
+			}
+		default:
+			return col, nil
+		}
+	}
+}
+
+// identity parses the rest of GENERATED (ALWAYS | BY DEFAULT [ON NULL]) AS IDENTITY [(options)]
+func (p *parser) identity() (*Identity, error) {
+	id := &Identity{}
+	switch {
+	case p.accept("ALWAYS"):
+		id.Always = true
+	case p.accept("BY", "DEFAULT"):
+		id.OnNull = p.accept("ON", "NULL")
+	default:
+		return nil, p.errorf("expected ALWAYS or BY DEFAULT but found %s", p.peek())
+	}
+	if err := p.expect("AS", "IDENTITY"); err != nil {
+		return nil, err
+	}
+	if !p.accept("(") {
+		return id, nil
+	}
+
+	// Group the option words, e.g. "START WITH 1 NO CYCLE" into "START WITH 1" and "NO CYCLE"
+	var option []string
+	for !p.accept(")") {
+		tok := p.next()
+		if tok.kind == tokenEOF {
+			return nil, p.errorf("expected ) but found %s", tok)
+		}
+		word := tok.text
+		if tok.kind == tokenIdent {
+			word = strings.ToUpper(word)
+		}
+		newOption := tok.kind == tokenIdent && len(option) > 0 && option[len(option)-1] != "NO" && slices.Contains(identityOptions, word)
+		if newOption {
+			id.Options = append(id.Options, strings.Join(option, " "))
+			option = nil
+		}
+		option = append(option, word)
+	}
+	if len(option) > 0 {
+		id.Options = append(id.Options, strings.Join(option, " "))
+	}
+	return id, nil
+}
+
+// typ parses a type definition
+func (p *parser) typ() (*Type, error) {
+	name, err := p.ident()
+	if err != nil {
+		return nil, err
+	}
+	t := &Type{Name: strings.ToUpper(name), Size: -1}
+	switch t.Name {
+	case "INTEGER", "LONG", "FLOAT", "DOUBLE", "NUMBER", "STRING", "BOOLEAN", "JSON", "ANY":
+		return t, nil
+
+	case "TIMESTAMP", "BINARY":
+		if p.accept("(") {
+			if t.Size, err = p.integer(); err != nil {
+				return t, err
+			}
+			return t, p.expect(")")
+		}
+		return t, nil
+
+	case "ENUM":
+		if err := p.expect("("); err != nil {
+			return t, err
+		}
+		for {
+			value, err := p.ident()
+			if err != nil {
+				return t, err
+			}
+			t.Values = append(t.Values, value)
+			if !p.accept(",") {
+				break
+			}
+		}
+		return t, p.expect(")")
+
+	case "ARRAY", "MAP":
+		if err := p.expect("("); err != nil {
+			return t, err
+		}
+		if t.Elem, err = p.typ(); err != nil {
+			return t, err
+		}
+		return t, p.expect(")")
+
+	case "RECORD":
+		if err := p.expect("("); err != nil {
+			return t, err
+		}
+		for {
+			field, err := p.column()
+			if field != nil {
+				t.Fields = append(t.Fields, field)
+			}
+			if err != nil {
+				return t, err
+			}
+			if !p.accept(",") {
+				break
+			}
+		}
+		return t, p.expect(")")
+	}
+	return t, fmt.Errorf("line %d: unknown type %s", p.tokens[p.pos-1].line, t.Name)
+}
@@ -0,0 +1,507 @@
+package ddl
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		ddl     string
+		table   string   // Table name
+		columns []string // Column definitions as returned by Definition
+		key     []string
+		shard   []string
+		ttl     string // "<value> <unit>", or "" without TTL
+		check   func(t *testing.T, table *Table)
+	}{
+		{
+			name:    "simple table",
+			ddl:     "CREATE TABLE users (id INTEGER, name STRING, PRIMARY KEY(id))",
+			table:   "users",
+			columns: []string{"id INTEGER", "name STRING"},
+			key:     []string{"id"},
+			shard:   []string{"id"},
+		},
+		{
+			name:    "lower-case keywords and types",
+			ddl:     "create table Users (Id integer, score double, primary key(Id))",
+			table:   "Users",
+			columns: []string{"Id INTEGER", "score DOUBLE"},
+			key:     []string{"Id"},
+			shard:   []string{"Id"},
+		},
+		{
+			name:    "all atomic types",
+			ddl:     "CREATE TABLE t (a INTEGER, b LONG, c FLOAT, d DOUBLE, e NUMBER, f STRING, g BOOLEAN, h BINARY, i JSON, PRIMARY KEY(a))",
+			table:   "t",
+			columns: []string{"a INTEGER", "b LONG", "c FLOAT", "d DOUBLE", "e NUMBER", "f STRING", "g BOOLEAN", "h BINARY", "i JSON"},
+			key:     []string{"a"},
+			shard:   []string{"a"},
+		},
+		{
+			name:    "composite key",
+			ddl:     "CREATE TABLE orders (user_id INTEGER, order_id INTEGER, amount DOUBLE, PRIMARY KEY(user_id, order_id))",
+			table:   "orders",
+			columns: []string{"user_id INTEGER", "order_id INTEGER", "amount DOUBLE"},
+			key:     []string{"user_id", "order_id"},
+			shard:   []string{"user_id", "order_id"},
+		},
+		{
+			name:    "shard key",
+			ddl:     "CREATE TABLE items (id INTEGER, name STRING, PRIMARY KEY(SHARD(id), name))",
+			table:   "items",
+			columns: []string{"id INTEGER", "name STRING"},
+			key:     []string{"id", "name"},
+			shard:   []string{"id"},
+		},
+		{
+			name:    "multi-column shard key",
+			ddl:     "CREATE TABLE items (a INTEGER, b INTEGER, c STRING, PRIMARY KEY(shard(a, b), c))",
+			table:   "items",
+			columns: []string{"a INTEGER", "b INTEGER", "c STRING"},
+			key:     []string{"a", "b", "c"},
+			shard:   []string{"a", "b"},
+		},
+		{
+			name:    "shard key is the whole key",
+			ddl:     "CREATE TABLE items (a INTEGER, b INTEGER, PRIMARY KEY(SHARD(a, b)))",
+			table:   "items",
+			columns: []string{"a INTEGER", "b INTEGER"},
+			key:     []string{"a", "b"},
+			shard:   []string{"a", "b"},
+		},
+		{
+			name:    "key sizes",
+			ddl:     "CREATE TABLE t (a INTEGER, b INTEGER, PRIMARY KEY(SHARD(a(3)), b(5)))",
+			table:   "t",
+			columns: []string{"a INTEGER", "b INTEGER"},
+			key:     []string{"a", "b"},
+			shard:   []string{"a"},
+			check: func(t *testing.T, table *Table) {
+				if table.Columns[0].KeySize != 3 || table.Columns[1].KeySize != 5 {
+					t.Errorf("KeySize = %d, %d, want 3, 5", table.Columns[0].KeySize, table.Columns[1].KeySize)
+				}
+			},
+		},
+		{
+			name:    "primary key before the columns",
+			ddl:     "CREATE TABLE t (PRIMARY KEY(id), id LONG, v STRING)",
+			table:   "t",
+			columns: []string{"id LONG", "v STRING"},
+			key:     []string{"id"},
+			shard:   []string{"id"},
+			check: func(t *testing.T, table *Table) {
+				if !table.Columns[0].PrimaryKey || table.Columns[1].PrimaryKey {
+					t.Error("PrimaryKey flags are not set from a leading PRIMARY KEY")
+				}
+			},
+		},
+		{
+			name:    "no primary key",
+			ddl:     "CREATE TABLE simple (id INTEGER, name STRING)",
+			table:   "simple",
+			columns: []string{"id INTEGER", "name STRING"},
+		},
+		{
+			name:    "empty primary key",
+			ddl:     "CREATE TABLE t (id INTEGER, PRIMARY KEY())",
+			table:   "t",
+			columns: []string{"id INTEGER"},
+		},
+		{
+			name:    "if not exists",
+			ddl:     "CREATE TABLE IF NOT EXISTS users (id INTEGER, PRIMARY KEY(id))",
+			table:   "users",
+			columns: []string{"id INTEGER"},
+			key:     []string{"id"},
+			shard:   []string{"id"},
+			check: func(t *testing.T, table *Table) {
+				if !table.IfNotExists {
+					t.Error("IfNotExists = false")
+				}
+			},
+		},
+		{
+			name:    "child table",
+			ddl:     "CREATE TABLE users.addresses.phones (phone_id INTEGER, number STRING, PRIMARY KEY(phone_id))",
+			table:   "users.addresses.phones",
+			columns: []string{"phone_id INTEGER", "number STRING"},
+			key:     []string{"phone_id"},
+			shard:   []string{"phone_id"},
+		},
+		{
+			name:    "namespace",
+			ddl:     "CREATE TABLE sales:orders.items (item_id INTEGER, PRIMARY KEY(item_id))",
+			table:   "sales:orders.items",
+			columns: []string{"item_id INTEGER"},
+			key:     []string{"item_id"},
+			shard:   []string{"item_id"},
+		},
+		{
+			name:    "system table name",
+			ddl:     "CREATE TABLE SYS$TableStatsPartition (tableName STRING, partitionId INTEGER, PRIMARY KEY(SHARD(tableName), partitionId))",
+			table:   "SYS$TableStatsPartition",
+			columns: []string{"tableName STRING", "partitionId INTEGER"},
+			key:     []string{"tableName", "partitionId"},
+			shard:   []string{"tableName"},
+		},
+		{
+			name:    "timestamp precision and fixed binary",
+			ddl:     "CREATE TABLE t (id INTEGER, created TIMESTAMP(3), at timestamp, hash BINARY(16), PRIMARY KEY(id))",
+			table:   "t",
+			columns: []string{"id INTEGER", "created TIMESTAMP(3)", "at TIMESTAMP", "hash BINARY(16)"},
+			key:     []string{"id"},
+			shard:   []string{"id"},
+		},
+		{
+			name:    "timestamp precision zero",
+			ddl:     "CREATE TABLE t (id INTEGER, day TIMESTAMP(0), PRIMARY KEY(id))",
+			table:   "t",
+			columns: []string{"id INTEGER", "day TIMESTAMP(0)"},
+			key:     []string{"id"},
+			shard:   []string{"id"},
+		},
+		{
+			name:    "enum",
+			ddl:     "CREATE TABLE t (id INTEGER, size ENUM(small, medium, large), PRIMARY KEY(id))",
+			table:   "t",
+			columns: []string{"id INTEGER", "size ENUM(small, medium, large)"},
+			key:     []string{"id"},
+			shard:   []string{"id"},
+			check: func(t *testing.T, table *Table) {
+				if got := table.Columns[1].Type.Values; !reflect.DeepEqual(got, []string{"small", "medium", "large"}) {
+					t.Errorf("Values = %v", got)
+				}
+			},
+		},
+		{
+			name:    "array and map",
+			ddl:     "CREATE TABLE t (id INTEGER, tags ARRAY(STRING), props MAP(STRING), scores MAP(ARRAY(LONG)), PRIMARY KEY(id))",
+			table:   "t",
+			columns: []string{"id INTEGER", "tags ARRAY(STRING)", "props MAP(STRING)", "scores MAP(ARRAY(LONG))"},
+			key:     []string{"id"},
+			shard:   []string{"id"},
+			check: func(t *testing.T, table *Table) {
+				scores := table.Columns[3].Type
+				if scores.Name != "MAP" || scores.Elem.Name != "ARRAY" || scores.Elem.Elem.Name != "LONG" {
+					t.Errorf("scores = %+v", scores)
+				}
+			},
+		},
+		{
+			name:    "record with commas",
+			ddl:     "CREATE TABLE t (id INTEGER, address RECORD(street STRING, city STRING, zip INTEGER), note STRING, PRIMARY KEY(id))",
+			table:   "t",
+			columns: []string{"id INTEGER", "address RECORD(street STRING, city STRING, zip INTEGER)", "note STRING"},
+			key:     []string{"id"},
+			shard:   []string{"id"},
+			check: func(t *testing.T, table *Table) {
+				fields := table.Columns[1].Type.Fields
+				if len(fields) != 3 || fields[2].Name != "zip" || fields[2].Type.Name != "INTEGER" {
+					t.Errorf("Fields = %+v", fields)
+				}
+			},
+		},
+		{
+			name:    "deeply nested complex types",
+			ddl:     "CREATE TABLE t (id INTEGER, doc ARRAY(RECORD(a INTEGER, b MAP(ARRAY(RECORD(x DOUBLE, y DOUBLE))), c JSON)), PRIMARY KEY(id))",
+			table:   "t",
+			columns: []string{"id INTEGER", "doc ARRAY(RECORD(a INTEGER, b MAP(ARRAY(RECORD(x DOUBLE, y DOUBLE))), c JSON))"},
+			key:     []string{"id"},
+			shard:   []string{"id"},
+			check: func(t *testing.T, table *Table) {
+				if !table.Columns[1].Type.IsComplex() || table.Columns[0].Type.IsComplex() {
+					t.Error("IsComplex() is wrong")
+				}
+			},
+		},
+		{
+			name:    "record field attributes",
+			ddl:     "CREATE TABLE t (id INTEGER, r RECORD(a INTEGER NOT NULL DEFAULT 1 COMMENT 'first', b STRING), PRIMARY KEY(id))",
+			table:   "t",
+			columns: []string{"id INTEGER", "r RECORD(a INTEGER DEFAULT 1 NOT NULL, b STRING)"},
+			key:     []string{"id"},
+			shard:   []string{"id"},
+			check: func(t *testing.T, table *Table) {
+				if c := table.Columns[1].Type.Fields[0].Comment; c != "first" {
+					t.Errorf("Comment = %q", c)
+				}
+			},
+		},
+		{
+			name:    "defaults and nullability",
+			ddl:     "CREATE TABLE t (id INTEGER, a INTEGER DEFAULT 0 NOT NULL, b STRING NOT NULL DEFAULT 'x', c BOOLEAN DEFAULT true, d DOUBLE DEFAULT -1.5E3, e STRING NOT NULL, PRIMARY KEY(id))",
+			table:   "t",
+			columns: []string{"id INTEGER", "a INTEGER DEFAULT 0 NOT NULL", "b STRING DEFAULT 'x' NOT NULL", "c BOOLEAN DEFAULT true", "d DOUBLE DEFAULT -1.5E3", "e STRING NOT NULL"},
+			key:     []string{"id"},
+			shard:   []string{"id"},
+		},
+		{
+			name:    "string default with delimiters",
+			ddl:     `CREATE TABLE t (id INTEGER, a STRING DEFAULT "a, b) PRIMARY KEY(x", b STRING DEFAULT 'it\'s', PRIMARY KEY(id))`,
+			table:   "t",
+			columns: []string{"id INTEGER", `a STRING DEFAULT "a, b) PRIMARY KEY(x"`, `b STRING DEFAULT 'it\'s'`},
+			key:     []string{"id"},
+			shard:   []string{"id"},
+		},
+		{
+			name:    "enum default",
+			ddl:     "CREATE TABLE t (id INTEGER, size ENUM(s, m, l) DEFAULT m, PRIMARY KEY(id))",
+			table:   "t",
+			columns: []string{"id INTEGER", "size ENUM(s, m, l) DEFAULT m"},
+			key:     []string{"id"},
+			shard:   []string{"id"},
+		},
+		{
+			name:    "identity always",
+			ddl:     "CREATE TABLE t (id INTEGER GENERATED ALWAYS AS IDENTITY, name STRING, PRIMARY KEY(id))",
+			table:   "t",
+			columns: []string{"id INTEGER GENERATED ALWAYS AS IDENTITY", "name STRING"},
+			key:     []string{"id"},
+			shard:   []string{"id"},
+			check: func(t *testing.T, table *Table) {
+				if !table.Columns[0].Generated() || table.Columns[1].Generated() {
+					t.Error("Generated() is wrong")
+				}
+			},
+		},
+		{
+			name:    "identity by default on null with options",
+			ddl:     "CREATE TABLE t (id LONG GENERATED BY DEFAULT ON NULL AS IDENTITY (START WITH 1 INCREMENT BY 2 MAXVALUE 100 NO CYCLE CACHE 10), PRIMARY KEY(id))",
+			table:   "t",
+			columns: []string{"id LONG GENERATED BY DEFAULT ON NULL AS IDENTITY (START WITH 1 INCREMENT BY 2 MAXVALUE 100 NO CYCLE CACHE 10)"},
+			key:     []string{"id"},
+			shard:   []string{"id"},
+			check: func(t *testing.T, table *Table) {
+				want := []string{"START WITH 1", "INCREMENT BY 2", "MAXVALUE 100", "NO CYCLE", "CACHE 10"}
+				if got := table.Columns[0].Identity.Options; !reflect.DeepEqual(got, want) {
+					t.Errorf("Options = %q, want %q", got, want)
+				}
+			},
+		},
+		{
+			name:    "identity with negative increment",
+			ddl:     "CREATE TABLE t (id INTEGER GENERATED BY DEFAULT AS IDENTITY (start with -1 increment by -1 no minvalue), PRIMARY KEY(id))",
+			table:   "t",
+			columns: []string{"id INTEGER GENERATED BY DEFAULT AS IDENTITY (START WITH -1 INCREMENT BY -1 NO MINVALUE)"},
+			key:     []string{"id"},
+			shard:   []string{"id"},
+		},
+		{
+			name:    "uuid columns",
+			ddl:     "CREATE TABLE t (id STRING AS UUID GENERATED BY DEFAULT, ref STRING AS UUID, PRIMARY KEY(id))",
+			table:   "t",
+			columns: []string{"id STRING AS UUID GENERATED BY DEFAULT", "ref STRING AS UUID"},
+			key:     []string{"id"},
+			shard:   []string{"id"},
+			check: func(t *testing.T, table *Table) {
+				if !table.Columns[0].Generated() || table.Columns[1].Generated() {
+					t.Error("Generated() is wrong")
+				}
+			},
+		},
+		{
+			name:    "mr counter",
+			ddl:     "CREATE TABLE t (id INTEGER, hits INTEGER AS MR_COUNTER, PRIMARY KEY(id)) IN REGIONS fra, lon",
+			table:   "t",
+			columns: []string{"id INTEGER", "hits INTEGER AS MR_COUNTER"},
+			key:     []string{"id"},
+			shard:   []string{"id"},
+			check: func(t *testing.T, table *Table) {
+				if !reflect.DeepEqual(table.Regions, []string{"fra", "lon"}) {
+					t.Errorf("Regions = %v", table.Regions)
+				}
+			},
+		},
+		{
+			name:    "ttl in days",
+			ddl:     "CREATE TABLE t (id INTEGER, PRIMARY KEY(id)) USING TTL 5 DAYS",
+			table:   "t",
+			columns: []string{"id INTEGER"},
+			key:     []string{"id"},
+			shard:   []string{"id"},
+			ttl:     "5 DAYS",
+		},
+		{
+			name:    "ttl in singular hours",
+			ddl:     "CREATE TABLE t (id INTEGER, PRIMARY KEY(id)) using ttl 1 hour",
+			table:   "t",
+			columns: []string{"id INTEGER"},
+			key:     []string{"id"},
+			shard:   []string{"id"},
+			ttl:     "1 HOURS",
+		},
+		{
+			name:    "json collection",
+			ddl:     "CREATE TABLE t (id LONG, PRIMARY KEY(id)) AS JSON COLLECTION",
+			table:   "t",
+			columns: []string{"id LONG"},
+			key:     []string{"id"},
+			shard:   []string{"id"},
+			check: func(t *testing.T, table *Table) {
+				if !table.JSONCollection {
+					t.Error("JSONCollection = false")
+				}
+			},
+		},
+		{
+			name:    "frozen schema with ttl",
+			ddl:     "CREATE TABLE t (id LONG, doc JSON, PRIMARY KEY(id)) USING TTL 30 DAYS WITH SCHEMA FROZEN FORCE",
+			table:   "t",
+			columns: []string{"id LONG", "doc JSON"},
+			key:     []string{"id"},
+			shard:   []string{"id"},
+			ttl:     "30 DAYS",
+			check: func(t *testing.T, table *Table) {
+				if !table.SchemaFrozen {
+					t.Error("SchemaFrozen = false")
+				}
+			},
+		},
+		{
+			name: "comments",
+			ddl: "/* users of the shop */\n" +
+				"CREATE TABLE users COMMENT \"shop users\" ( # the key\n" +
+				"  id INTEGER, // numeric id\n" +
+				"  name STRING COMMENT 'full name, as given',\n" +
+				"  /* id, name */ PRIMARY KEY(id)\n" +
+				")",
+			table:   "users",
+			columns: []string{"id INTEGER", "name STRING"},
+			key:     []string{"id"},
+			shard:   []string{"id"},
+			check: func(t *testing.T, table *Table) {
+				if table.Comment != "shop users" || table.Columns[1].Comment != "full name, as given" {
+					t.Errorf("Comment = %q, %q", table.Comment, table.Columns[1].Comment)
+				}
+			},
+		},
+		{
+			name: "multi-line statement with semicolon",
+			ddl: "CREATE TABLE users (\n" +
+				"  tenant STRING,\n" +
+				"  id LONG,\n" +
+				"  created TIMESTAMP(6),\n" +
+				"  address RECORD(street STRING, city STRING),\n" +
+				"  PRIMARY KEY(SHARD(tenant), id)\n" +
+				") USING TTL 30 DAYS;",
+			table:   "users",
+			columns: []string{"tenant STRING", "id LONG", "created TIMESTAMP(6)", "address RECORD(street STRING, city STRING)"},
+			key:     []string{"tenant", "id"},
+			shard:   []string{"tenant"},
+			ttl:     "30 DAYS",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			table, err := Parse(tt.ddl)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if table.Name != tt.table {
+				t.Errorf("Name = %q, want %q", table.Name, tt.table)
+			}
+			var columns []string
+			for _, col := range table.Columns {
+				columns = append(columns, col.Definition())
+			}
+			if !reflect.DeepEqual(columns, tt.columns) {
+				t.Errorf("columns =\n%q\nwant\n%q", columns, tt.columns)
+			}
+			if !reflect.DeepEqual(table.PrimaryKey, tt.key) {
+				t.Errorf("PrimaryKey = %q, want %q", table.PrimaryKey, tt.key)
+			}
+			if !reflect.DeepEqual(table.ShardKey, tt.shard) {
+				t.Errorf("ShardKey = %q, want %q", table.ShardKey, tt.shard)
+			}
+			for _, col := range table.Columns {
+				isKey := false
+				for _, key := range tt.key {
+					isKey = isKey || key == col.Name
+				}
+				if col.PrimaryKey != isKey {
+					t.Errorf("%s: PrimaryKey = %v", col.Name, col.PrimaryKey)
+				}
+			}
+			ttl := ""
+			if table.TTL != nil {
+				ttl = fmt.Sprintf("%d %s", table.TTL.Value, table.TTL.Unit)
+			}
+			if ttl != tt.ttl {
+				t.Errorf("TTL = %q, want %q", ttl, tt.ttl)
+			}
+			if tt.check != nil {
+				tt.check(t, table)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		ddl     string
+		wantErr string
+		columns int // Columns parsed before the error
+	}{
+		{name: "empty", ddl: "", wantErr: "line 1: expected CREATE but found end of statement"},
+		{name: "not a create table", ddl: "DROP TABLE users", wantErr: `expected CREATE but found "DROP"`},
+		{name: "create index", ddl: "CREATE INDEX idx ON users (name)", wantErr: `expected TABLE but found "INDEX"`},
+		{name: "missing column list", ddl: "CREATE TABLE users", wantErr: "expected ( but found end of statement"},
+		{name: "unknown type", ddl: "CREATE TABLE t (id INTEGER, a VARCHAR, PRIMARY KEY(id))", wantErr: "unknown type VARCHAR", columns: 1},
+		{name: "missing type", ddl: "CREATE TABLE t (id, PRIMARY KEY(id))", wantErr: `expected a name but found ","`},
+		{name: "unclosed column list", ddl: "CREATE TABLE t (id INTEGER, PRIMARY KEY(id)", wantErr: "expected , but found end of statement", columns: 1},
+		{name: "unclosed record", ddl: "CREATE TABLE t (id INTEGER, r RECORD(a INTEGER, PRIMARY KEY(id))", wantErr: "unknown type KEY", columns: 1},
+		{name: "array without element type", ddl: "CREATE TABLE t (id INTEGER, a ARRAY, PRIMARY KEY(id))", wantErr: `expected ( but found ","`, columns: 1},
+		{name: "bad precision", ddl: "CREATE TABLE t (id INTEGER, at TIMESTAMP(x), PRIMARY KEY(id))", wantErr: `expected a whole number but found "x"`, columns: 1},
+		{name: "shard after key", ddl: "CREATE TABLE t (a INTEGER, b INTEGER, PRIMARY KEY(a, SHARD(b)))", wantErr: "SHARD must come first", columns: 2},
+		{name: "bad identity", ddl: "CREATE TABLE t (id INTEGER GENERATED SOMETIMES AS IDENTITY, PRIMARY KEY(id))", wantErr: `expected ALWAYS or BY DEFAULT but found "SOMETIMES"`, columns: 1},
+		{name: "bad default", ddl: "CREATE TABLE t (id INTEGER DEFAULT (1), PRIMARY KEY(id))", wantErr: `expected a default value but found "("`, columns: 1},
+		{name: "bad ttl unit", ddl: "CREATE TABLE t (id INTEGER, PRIMARY KEY(id)) USING TTL 5 WEEKS", wantErr: `unknown TTL unit "WEEKS"`, columns: 1},
+		{name: "trailing text", ddl: "CREATE TABLE t (id INTEGER, PRIMARY KEY(id)) garbage", wantErr: `unexpected "garbage" after the column list`, columns: 1},
+		{name: "text after semicolon", ddl: "CREATE TABLE t (id INTEGER, PRIMARY KEY(id)); DROP TABLE t", wantErr: `unexpected "DROP" after the statement`, columns: 1},
+		{name: "unterminated string", ddl: "CREATE TABLE t (id INTEGER,\n name STRING DEFAULT 'abc", wantErr: "line 2: unterminated string"},
+		{name: "unterminated comment", ddl: "CREATE TABLE t (id INTEGER /* key", wantErr: "line 1: unterminated comment"},
+		{name: "unexpected character", ddl: "CREATE TABLE t (id INTEGER @, PRIMARY KEY(id))", wantErr: `unexpected character '@'`},
+		{name: "error line number", ddl: "CREATE TABLE t (\n  id INTEGER,\n  a FOO,\n  PRIMARY KEY(id)\n)", wantErr: "line 3: unknown type FOO", columns: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			table, err := Parse(tt.ddl)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Parse() error = %v, want %q", err, tt.wantErr)
+			}
+			columns := 0
+			if table != nil {
+				columns = len(table.Columns)
+			}
+			if columns != tt.columns {
+				t.Errorf("parsed %d columns before the error, want %d", columns, tt.columns)
+			}
+		})
+	}
+}
+
+func TestTypeString(t *testing.T) {
+	tests := []struct {
+		typ  *Type
+		want string
+	}{
+		{&Type{Name: "STRING", Size: -1}, "STRING"},
+		{&Type{Name: "TIMESTAMP", Size: 9}, "TIMESTAMP(9)"},
+		{&Type{Name: "ARRAY", Size: -1, Elem: &Type{Name: "JSON", Size: -1}}, "ARRAY(JSON)"},
+		{&Type{Name: "RECORD", Size: -1, Fields: []*Column{{Name: "a", Type: &Type{Name: "LONG", Size: -1}, NotNull: true, Default: "5"}}}, "RECORD(a LONG DEFAULT 5 NOT NULL)"},
+		{nil, ""},
+	}
+	for _, tt := range tests {
+		if got := tt.typ.String(); got != tt.want {
+			t.Errorf("String() = %q, want %q", got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,265 @@
+// Package importer loads rows from JSON Lines or CSV files into a table.
+// Records are mapped to the table schema and validated before they are written
+// in batches of rows sharing a shard key; records that fail are written to a
+// rejects file together with the reason.
+package importer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/oracle/nosql-go-sdk/nosqldb"
+	"github.com/oracle/nosql-go-sdk/nosqldb/types"
+
+	"github.com/camikura/dito/internal/db"
+)
+
+// Mode selects how existing rows are treated
+type Mode string
+
+const (
+	ModeUpsert Mode = "upsert" // Insert new rows and overwrite existing ones
+	ModeInsert Mode = "insert" // Insert new rows only; existing rows are rejected
+)
+
+// ParseMode validates a mode name (case-insensitive)
+func ParseMode(value string) (Mode, error) {
+	switch Mode(strings.ToLower(value)) {
+	case ModeUpsert:
+		return ModeUpsert, nil
+	case ModeInsert:
+		return ModeInsert, nil
+	}
+	return "", fmt.Errorf("unknown mode %q (want upsert or insert)", value)
+}
+
+// maxPendingRows bounds the rows held while waiting for a full batch.
+// When reached, all pending groups are written even if not full.
+const maxPendingRows = 1000
+
+// WriteFunc writes rows sharing a shard key and returns one error per row (nil when written)
+type WriteFunc func(rows []*types.MapValue) []error
+
+// ClientWriter returns a WriteFunc that writes to tableName with client
+func ClientWriter(client *nosqldb.Client, tableName string, mode Mode) WriteFunc {
+	return func(rows []*types.MapValue) []error {
+		return db.PutRows(client, tableName, rows, mode == ModeInsert)
+	}
+}
+
+// DefaultRejectsPath returns the rejects file used for input path: "users.csv" -> "users.rejects.jsonl"
+func DefaultRejectsPath(path string) string {
+	if path == "" || path == "-" {
+		return "rejects.jsonl"
+	}
+	return strings.TrimSuffix(path, filepath.Ext(path)) + ".rejects.jsonl"
+}
+
+// Options configures an import
+type Options struct {
+	BatchSize   int    // Rows per write request (default and maximum db.MaxBatchRows)
+	RejectsPath string // Rejects file, created on the first rejected record
+}
+
+// Stats counts processed records
+type Stats struct {
+	Read     int
+	Written  int
+	Rejected int
+}
+
+// pendingRow is a converted row waiting to be written
+type pendingRow struct {
+	record Record
+	row    *types.MapValue
+}
+
+// Importer reads records, converts them and writes them in batches.
+// Call Step until it reports done, or Cancel to stop early, then Close.
+type Importer struct {
+	schema *Schema
+	reader Reader
+	write  WriteFunc
+	opts   Options
+
+	groups  map[string][]pendingRow // Pending rows by shard key
+	order   []string                // Shard keys in first-seen order
+	pending int
+
+	rejects *os.File
+	stats   Stats
+}
+
+// New returns an importer writing the records of reader with write
+func New(schema *Schema, reader Reader, write WriteFunc, opts Options) *Importer {
+	if opts.BatchSize <= 0 || opts.BatchSize > db.MaxBatchRows {
+		opts.BatchSize = db.MaxBatchRows
+	}
+	return &Importer{
+		schema: schema,
+		reader: reader,
+		write:  write,
+		opts:   opts,
+		groups: make(map[string][]pendingRow),
+	}
+}
+
+// Stats returns the counts so far
+func (im *Importer) Stats() Stats {
+	return im.stats
+}
+
+// RejectsPath returns the rejects file path
+func (im *Importer) RejectsPath() string {
+	return im.opts.RejectsPath
+}
+
+// Step processes up to maxRecords records and reports whether the input is exhausted.
+// Rows still pending at the end of the input are written before done is returned.
+// An error stops the import; rows already written stay written.
+func (im *Importer) Step(maxRecords int) (done bool, err error) {
+	for i := 0; i < maxRecords; i++ {
+		record, err := im.reader.Read()
+		if err == io.EOF {
+			return true, im.flushAll()
+		}
+		if err != nil {
+			return false, err
+		}
+		im.stats.Read++
+
+		if record.Err != nil {
+			if err := im.reject(record, record.Err); err != nil {
+				return false, err
+			}
+			continue
+		}
+		row, err := im.schema.Convert(record.Fields)
+		if err != nil {
+			if err := im.reject(record, err); err != nil {
+				return false, err
+			}
+			continue
+		}
+
+		key := im.schema.ShardKeyOf(row)
+		if _, exists := im.groups[key]; !exists {
+			im.order = append(im.order, key)
+		}
+		im.groups[key] = append(im.groups[key], pendingRow{record: record, row: row})
+		im.pending++
+
+		if len(im.groups[key]) >= im.opts.BatchSize {
+			// The key is added again with its next row
+			im.order = slices.DeleteFunc(im.order, func(k string) bool { return k == key })
+			if err := im.flush(key); err != nil {
+				return false, err
+			}
+		} else if im.pending >= maxPendingRows {
+			if err := im.flushAll(); err != nil {
+				return false, err
+			}
+		}
+	}
+	return false, nil
+}
+
+// flushAll writes all pending groups
+func (im *Importer) flushAll() error {
+	for _, key := range im.order {
+		if err := im.flush(key); err != nil {
+			return err
+		}
+	}
+	im.order = im.order[:0]
+	return nil
+}
+
+// flush writes the pending rows of one shard key
+func (im *Importer) flush(key string) error {
+	group := im.groups[key]
+	if len(group) == 0 {
+		return nil
+	}
+	delete(im.groups, key)
+	im.pending -= len(group)
+
+	rows := make([]*types.MapValue, len(group))
+	for i, p := range group {
+		rows[i] = p.row
+	}
+	errs := im.write(rows)
+	for i, p := range group {
+		if i < len(errs) && errs[i] != nil {
+			if err := im.reject(p.record, errs[i]); err != nil {
+				return err
+			}
+			continue
+		}
+		im.stats.Written++
+	}
+	return nil
+}
+
+// rejectLine is one line of the rejects file
+type rejectLine struct {
+	Line   int                    `json:"line"`
+	Error  string                 `json:"error"`
+	Record map[string]interface{} `json:"record,omitempty"`
+	Raw    string                 `json:"raw,omitempty"`
+}
+
+// reject records a failed record in the rejects file
+func (im *Importer) reject(record Record, reason error) error {
+	im.stats.Rejected++
+	if im.opts.RejectsPath == "" {
+		return nil
+	}
+	if im.rejects == nil {
+		file, err := os.Create(im.opts.RejectsPath)
+		if err != nil {
+			return fmt.Errorf("rejects file: %w", err)
+		}
+		im.rejects = file
+	}
+	data, err := json.Marshal(rejectLine{Line: record.Line, Error: reason.Error(), Record: record.Fields, Raw: record.Raw})
+	if err != nil {
+		return err
+	}
+	_, err = im.rejects.Write(append(data, '\n'))
+	return err
+}
+
+// errCancelled is the reject reason of rows still pending when an import is cancelled
+var errCancelled = errors.New("import cancelled before the row was written")
+
+// Cancel stops the import before the end of the input. Rows still waiting for
+// a full batch are not written; they are rejected so that every record read is
+// either written or in the rejects file.
+func (im *Importer) Cancel() error {
+	for _, key := range im.order {
+		for _, p := range im.groups[key] {
+			if err := im.reject(p.record, errCancelled); err != nil {
+				return err
+			}
+		}
+		delete(im.groups, key)
+	}
+	im.order = im.order[:0]
+	im.pending = 0
+	return nil
+}
+
+// Close closes the rejects file
+func (im *Importer) Close() error {
+	if im.rejects == nil {
+		return nil
+	}
+	return im.rejects.Close()
+}
@@ -0,0 +1,469 @@
+package importer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/oracle/nosql-go-sdk/nosqldb/types"
+)
+
+const usersDDL = `CREATE TABLE users (
+  tenant STRING,
+  id INTEGER,
+  name STRING,
+  score DOUBLE,
+  joined TIMESTAMP(3),
+  tags ARRAY(STRING),
+  PRIMARY KEY(SHARD(tenant), id)
+)`
+
+func newUsersSchema(t *testing.T) *Schema {
+	t.Helper()
+	schema, err := NewSchema("users", usersDDL, nil)
+	if err != nil {
+		t.Fatalf("NewSchema() error = %v", err)
+	}
+	return schema
+}
+
+func TestNewSchema(t *testing.T) {
+	schema := newUsersSchema(t)
+	if len(schema.Columns) != 6 {
+		t.Fatalf("Columns = %d, want 6", len(schema.Columns))
+	}
+	if strings.Join(schema.ShardKey, ",") != "tenant" {
+		t.Errorf("ShardKey = %v, want [tenant]", schema.ShardKey)
+	}
+
+	child, err := NewSchema("users.orders",
+		"CREATE TABLE users.orders (oid LONG GENERATED ALWAYS AS IDENTITY, total NUMBER, PRIMARY KEY(oid))",
+		[]string{usersDDL})
+	if err != nil {
+		t.Fatalf("NewSchema(child) error = %v", err)
+	}
+	var names []string
+	for _, col := range child.Columns {
+		names = append(names, col.Name)
+	}
+	if got := strings.Join(names, ","); got != "tenant,id,oid,total" {
+		t.Errorf("child columns = %s, want tenant,id,oid,total", got)
+	}
+	if !child.Columns[2].Identity {
+		t.Error("oid should be an identity column")
+	}
+	if strings.Join(child.ShardKey, ",") != "tenant" {
+		t.Errorf("child ShardKey = %v, want [tenant]", child.ShardKey)
+	}
+
+	if _, err := NewSchema("empty", "", nil); err == nil {
+		t.Error("NewSchema() with empty DDL should fail")
+	}
+}
+
+func TestConvert(t *testing.T) {
+	schema := newUsersSchema(t)
+
+	tests := []struct {
+		name    string
+		fields  map[string]interface{}
+		wantErr string
+	}{
+		{name: "csv strings", fields: map[string]interface{}{"tenant": "a", "id": "1", "score": "2.5", "joined": "2024-01-02 03:04:05"}},
+		{name: "json values", fields: map[string]interface{}{"tenant": "a", "ID": json.Number("1"), "tags": []interface{}{"x"}}},
+		{name: "null is skipped", fields: map[string]interface{}{"tenant": "a", "id": "1", "name": nil}},
+		{name: "unknown column", fields: map[string]interface{}{"tenant": "a", "id": "1", "age": "3"}, wantErr: `unknown column "age"`},
+		{name: "bad integer", fields: map[string]interface{}{"tenant": "a", "id": "x"}, wantErr: "column id: invalid integer"},
+		{name: "integer range", fields: map[string]interface{}{"tenant": "a", "id": "3000000000"}, wantErr: "out of range"},
+		{name: "bad timestamp", fields: map[string]interface{}{"tenant": "a", "id": "1", "joined": "yesterday"}, wantErr: "invalid timestamp"},
+		{name: "array shape", fields: map[string]interface{}{"tenant": "a", "id": "1", "tags": `{"a":1}`}, wantErr: "expected ARRAY"},
+		{name: "missing key", fields: map[string]interface{}{"tenant": "a"}, wantErr: "missing primary key column id"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			row, err := schema.Convert(tt.fields)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Convert() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Convert() error = %v", err)
+			}
+			if id, _ := row.Get("id"); id != 1 {
+				t.Errorf("id = %#v, want 1", id)
+			}
+		})
+	}
+}
+
+func TestConvertValue(t *testing.T) {
+	if v, err := convertValue("NUMBER", "1.25"); err != nil || v.(*big.Rat).String() != "5/4" {
+		t.Errorf("NUMBER = %v, %v", v, err)
+	}
+	if v, err := convertValue("BOOLEAN", "true"); err != nil || v != true {
+		t.Errorf("BOOLEAN = %v, %v", v, err)
+	}
+	if v, err := convertValue("TIMESTAMP(0)", "2024-05-06"); err != nil || !v.(time.Time).Equal(time.Date(2024, 5, 6, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("TIMESTAMP = %v, %v", v, err)
+	}
+	if v, err := convertValue("BINARY", "aGk="); err != nil || string(v.([]byte)) != "hi" {
+		t.Errorf("BINARY = %v, %v", v, err)
+	}
+	if v, err := convertValue("JSON", `{"a":[1]}`); err != nil {
+		t.Errorf("JSON error = %v", err)
+	} else if _, ok := v.(map[string]interface{}); !ok {
+		t.Errorf("JSON = %T, want a decoded object", v)
+	}
+	if v, err := convertValue("JSON", "plain text"); err != nil || v != "plain text" {
+		t.Errorf("JSON text = %v, %v", v, err)
+	}
+	if _, err := convertValue("LONG", ""); err == nil {
+		t.Error("empty LONG should fail")
+	}
+}
+
+func TestFormatForPath(t *testing.T) {
+	tests := []struct {
+		path    string
+		want    Format
+		wantErr bool
+	}{
+		{path: "users.jsonl", want: FormatJSONL},
+		{path: "users.NDJSON", want: FormatJSONL},
+		{path: "dir/users.csv", want: FormatCSV},
+		{path: "users.json", wantErr: true},
+		{path: "users", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			got, err := FormatForPath(tt.path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("FormatForPath(%q) error = %v, wantErr %v", tt.path, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("FormatForPath(%q) = %q, want %q", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+// readAll reads all records
+func readAll(t *testing.T, input string, format Format) []Record {
+	t.Helper()
+	reader, err := NewReader(strings.NewReader(input), format)
+	if err != nil {
+		t.Fatalf("NewReader() error = %v", err)
+	}
+	var records []Record
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return records
+		}
+		if err != nil {
+			t.Fatalf("Read() error = %v", err)
+		}
+		records = append(records, record)
+	}
+}
+
+func TestJSONLReader(t *testing.T) {
+	records := readAll(t, "{\"id\":1}\n\n[1]\n{bad\n{\"id\":2}\n", FormatJSONL)
+	if len(records) != 4 {
+		t.Fatalf("records = %d, want 4", len(records))
+	}
+	if records[0].Line != 1 || records[0].Fields["id"] != json.Number("1") {
+		t.Errorf("record 0 = %+v", records[0])
+	}
+	if records[1].Line != 3 || records[1].Err == nil || records[1].Raw != "[1]" {
+		t.Errorf("record 1 = %+v, want a non-object error", records[1])
+	}
+	if records[2].Err == nil {
+		t.Errorf("record 2 = %+v, want a JSON error", records[2])
+	}
+	if records[3].Line != 5 || records[3].Err != nil {
+		t.Errorf("record 3 = %+v", records[3])
+	}
+}
+
+func TestCSVReader(t *testing.T) {
+	records := readAll(t, "\ufeffid, name\n1,Alice\n2,\n3\n\"4\",\"multi\nline\"\n", FormatCSV)
+	if len(records) != 4 {
+		t.Fatalf("records = %d, want 4", len(records))
+	}
+	if records[0].Line != 2 || records[0].Fields["id"] != "1" || records[0].Fields["name"] != "Alice" {
+		t.Errorf("record 0 = %+v", records[0])
+	}
+	if _, ok := records[1].Fields["name"]; ok {
+		t.Errorf("empty cell should be omitted: %+v", records[1])
+	}
+	if records[2].Err == nil {
+		t.Errorf("record 2 = %+v, want a field count error", records[2])
+	}
+	if records[3].Line != 5 || records[3].Fields["name"] != "multi\nline" {
+		t.Errorf("record 3 = %+v", records[3])
+	}
+
+	if _, err := NewReader(strings.NewReader(""), FormatCSV); err == nil {
+		t.Error("NewReader() with empty CSV should fail")
+	}
+}
+
+func TestImporter(t *testing.T) {
+	input := strings.Join([]string{
+		`{"tenant":"a","id":1}`,
+		`{"tenant":"b","id":2}`,
+		`{"tenant":"a","id":3}`,
+		`{"tenant":"a","id":"x"}`,
+		`{"tenant":"b","id":4}`,
+		`{"tenant":"a","id":5}`,
+	}, "\n")
+	reader, err := NewReader(strings.NewReader(input), FormatJSONL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Batches of two; the row with id 4 fails to write
+	var batches [][]string
+	write := func(rows []*types.MapValue) []error {
+		var batch []string
+		errs := make([]error, len(rows))
+		for i, row := range rows {
+			tenant, _ := row.Get("tenant")
+			id, _ := row.Get("id")
+			batch = append(batch, tenant.(string))
+			if id == 4 {
+				errs[i] = errors.New("write failed")
+			}
+		}
+		batches = append(batches, batch)
+		return errs
+	}
+
+	rejectsPath := filepath.Join(t.TempDir(), "users.rejects.jsonl")
+	im := New(newUsersSchema(t), reader, write, Options{BatchSize: 2, RejectsPath: rejectsPath})
+
+	done, err := im.Step(3)
+	if done || err != nil {
+		t.Fatalf("Step(3) = %v, %v", done, err)
+	}
+	if len(batches) != 1 || strings.Join(batches[0], ",") != "a,a" {
+		t.Fatalf("batches after first step = %v, want [[a a]]", batches)
+	}
+
+	for !done {
+		if done, err = im.Step(3); err != nil {
+			t.Fatalf("Step() error = %v", err)
+		}
+	}
+	if err := im.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Every batch shares a shard key
+	for _, batch := range batches {
+		for _, tenant := range batch {
+			if tenant != batch[0] {
+				t.Errorf("batch %v mixes shard keys", batch)
+			}
+		}
+	}
+	if got := im.Stats(); got != (Stats{Read: 6, Written: 4, Rejected: 2}) {
+		t.Errorf("Stats() = %+v", got)
+	}
+
+	data, err := os.ReadFile(rejectsPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("rejects = %q, want 2 lines", data)
+	}
+	var reject rejectLine
+	if err := json.Unmarshal([]byte(lines[0]), &reject); err != nil {
+		t.Fatal(err)
+	}
+	if reject.Line != 4 || !strings.Contains(reject.Error, "invalid integer") || reject.Record["tenant"] != "a" {
+		t.Errorf("first reject = %+v", reject)
+	}
+}
+
+func TestImporterInterleavedShardKeys(t *testing.T) {
+	// Tenants a and b alternate, each filling several batches
+	var lines []string
+	for i := 0; i < 20; i++ {
+		lines = append(lines, fmt.Sprintf(`{"tenant":"%c","id":%d}`, 'a'+i%2, i))
+	}
+	reader, err := NewReader(strings.NewReader(strings.Join(lines, "\n")), FormatJSONL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	batches := 0
+	im := New(newUsersSchema(t), reader, func(rows []*types.MapValue) []error {
+		batches++
+		return make([]error, len(rows))
+	}, Options{BatchSize: 3})
+
+	for done := false; !done; {
+		if done, err = im.Step(4); err != nil {
+			t.Fatalf("Step() error = %v", err)
+		}
+		if len(im.order) > 2 {
+			t.Fatalf("order = %v, want each pending shard key once", im.order)
+		}
+	}
+	if got := im.Stats(); got != (Stats{Read: 20, Written: 20}) {
+		t.Errorf("Stats() = %+v", got)
+	}
+	// 10 rows per tenant: three full batches and one of the last row
+	if batches != 8 {
+		t.Errorf("wrote %d batches, want 8", batches)
+	}
+}
+
+func TestImporterCancel(t *testing.T) {
+	input := strings.Join([]string{
+		`{"tenant":"a","id":1}`,
+		`{"tenant":"a","id":2}`,
+		`{"tenant":"b","id":3}`,
+		`{"tenant":"a","id":4}`,
+		`{"tenant":"a","id":5}`,
+	}, "\n")
+	reader, err := NewReader(strings.NewReader(input), FormatJSONL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rejectsPath := filepath.Join(t.TempDir(), "users.rejects.jsonl")
+	im := New(newUsersSchema(t), reader, func(rows []*types.MapValue) []error {
+		return make([]error, len(rows))
+	}, Options{BatchSize: 2, RejectsPath: rejectsPath})
+
+	// Cancel with rows 3 and 4 waiting for a full batch
+	if done, err := im.Step(4); done || err != nil {
+		t.Fatalf("Step(4) = %v, %v", done, err)
+	}
+	if err := im.Cancel(); err != nil {
+		t.Fatalf("Cancel() error = %v", err)
+	}
+	if err := im.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	stats := im.Stats()
+	if stats != (Stats{Read: 4, Written: 2, Rejected: 2}) {
+		t.Errorf("Stats() = %+v", stats)
+	}
+	if stats.Read != stats.Written+stats.Rejected {
+		t.Errorf("Read = %d, want Written + Rejected = %d", stats.Read, stats.Written+stats.Rejected)
+	}
+
+	data, err := os.ReadFile(rejectsPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var lines []int
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		var reject rejectLine
+		if err := json.Unmarshal([]byte(line), &reject); err != nil {
+			t.Fatal(err)
+		}
+		if reject.Error != errCancelled.Error() {
+			t.Errorf("reject error = %q", reject.Error)
+		}
+		lines = append(lines, reject.Line)
+	}
+	if fmt.Sprint(lines) != "[3 4]" {
+		t.Errorf("rejected lines = %v, want [3 4]", lines)
+	}
+}
+
+func TestImporterNoRejectsFile(t *testing.T) {
+	reader, _ := NewReader(strings.NewReader(`{"tenant":"a","id":1}`), FormatJSONL)
+	rejectsPath := filepath.Join(t.TempDir(), "rejects.jsonl")
+	im := New(newUsersSchema(t), reader, func(rows []*types.MapValue) []error {
+		return make([]error, len(rows))
+	}, Options{RejectsPath: rejectsPath})
+	if done, err := im.Step(10); !done || err != nil {
+		t.Fatalf("Step() = %v, %v", done, err)
+	}
+	im.Close()
+	if _, err := os.Stat(rejectsPath); !os.IsNotExist(err) {
+		t.Error("rejects file should not be created without rejected records")
+	}
+}
+
+func TestDefaultRejectsPath(t *testing.T) {
+	if got := DefaultRejectsPath("data/users.csv"); got != "data/users.rejects.jsonl" {
+		t.Errorf("DefaultRejectsPath() = %q", got)
+	}
+	if got := DefaultRejectsPath("-"); got != "rejects.jsonl" {
+		t.Errorf("DefaultRejectsPath(-) = %q", got)
+	}
+}
+
+func TestPrimaryKey(t *testing.T) {
+	schema := newUsersSchema(t)
+
+	// Query results decode numbers as float64
+	key, err := schema.PrimaryKey(map[string]interface{}{"tenant": "a", "id": float64(12345678), "name": "x"})
+	if err != nil {
+		t.Fatalf("PrimaryKey() error = %v", err)
+	}
+	if id, _ := key.Get("id"); id != 12345678 {
+		t.Errorf("id = %#v, want 12345678", id)
+	}
+	if _, ok := key.Get("name"); ok {
+		t.Error("key should only hold primary key columns")
+	}
+
+	if _, err := schema.PrimaryKey(map[string]interface{}{"tenant": "a"}); err == nil {
+		t.Error("PrimaryKey() without id should fail")
+	}
+}
+
+func TestFormatValueRoundTrip(t *testing.T) {
+	schema, err := NewSchema("t", `CREATE TABLE t (
+  id INTEGER, n NUMBER, ts TIMESTAMP(3), b BINARY, j JSON, m MAP(INTEGER), PRIMARY KEY(id))`, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		column string
+		value  interface{}
+		want   string
+	}{
+		{column: "id", value: 7, want: "7"},
+		{column: "n", value: big.NewRat(5, 4), want: "1.25"},
+		{column: "n", value: big.NewRat(1, 3), want: "1/3"},
+		{column: "ts", value: time.Date(2024, 5, 6, 7, 8, 9, 5e6, time.UTC), want: "2024-05-06T07:08:09.005Z"},
+		{column: "b", value: []byte("hi"), want: "aGk="},
+		{column: "j", value: map[string]interface{}{"a": []interface{}{1.0}}, want: `{"a":[1]}`},
+		{column: "j", value: "text", want: `"text"`},
+		{column: "m", value: map[string]interface{}{"x": 1}, want: `{"x":1}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			col, _ := schema.Column(tt.column)
+			text := FormatValue(col.Type, tt.value)
+			if text != tt.want {
+				t.Fatalf("FormatValue() = %q, want %q", text, tt.want)
+			}
+			if _, err := schema.ConvertField(tt.column, text); err != nil {
+				t.Errorf("ConvertField(%q) error = %v", text, err)
+			}
+		})
+	}
+}
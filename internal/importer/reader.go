@@ -0,0 +1,150 @@
+package importer
+
+import (
+	"bufio"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+)
+
+// Format is an input file format
+type Format string
+
+const (
+	FormatJSONL Format = "jsonl"
+	FormatCSV   Format = "csv"
+)
+
+// ParseFormat validates a format name (case-insensitive)
+func ParseFormat(value string) (Format, error) {
+	switch strings.ToLower(value) {
+	case "jsonl", "ndjson":
+		return FormatJSONL, nil
+	case "csv":
+		return FormatCSV, nil
+	}
+	return "", fmt.Errorf("unknown format %q (want jsonl or csv)", value)
+}
+
+// FormatForPath detects the format from the file extension
+func FormatForPath(path string) (Format, error) {
+	ext := strings.TrimPrefix(filepath.Ext(path), ".")
+	if ext == "" {
+		return "", fmt.Errorf("cannot detect the format of %s (use jsonl or csv)", path)
+	}
+	return ParseFormat(ext)
+}
+
+// Record is one input record
+type Record struct {
+	Line   int                    // Line number where the record starts
+	Fields map[string]interface{} // Field values: strings for CSV, decoded JSON values for JSON Lines
+	Raw    string                 // Original text when the record could not be parsed
+	Err    error                  // Parse error; the record is rejected but reading continues
+}
+
+// Reader reads records; Read returns io.EOF after the last record
+type Reader interface {
+	Read() (Record, error)
+}
+
+// NewReader returns a reader for format
+func NewReader(r io.Reader, format Format) (Reader, error) {
+	switch format {
+	case FormatJSONL:
+		scanner := bufio.NewScanner(r)
+		// Allow rows up to the maximum row size
+		scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+		return &jsonlReader{scanner: scanner}, nil
+	case FormatCSV:
+		return newCSVReader(r)
+	}
+	return nil, fmt.Errorf("unknown format %q", format)
+}
+
+// jsonlReader reads one JSON object per line, skipping blank lines
+type jsonlReader struct {
+	scanner *bufio.Scanner
+	line    int
+}
+
+func (j *jsonlReader) Read() (Record, error) {
+	for j.scanner.Scan() {
+		j.line++
+		text := strings.TrimSpace(j.scanner.Text())
+		if text == "" {
+			continue
+		}
+		record := Record{Line: j.line}
+		value, err := decodeJSON(text)
+		fields, isObject := value.(map[string]interface{})
+		switch {
+		case err != nil:
+			record.Raw, record.Err = text, fmt.Errorf("invalid JSON: %w", err)
+		case !isObject:
+			record.Raw, record.Err = text, errors.New("expected a JSON object")
+		default:
+			record.Fields = fields
+		}
+		return record, nil
+	}
+	if err := j.scanner.Err(); err != nil {
+		return Record{}, err
+	}
+	return Record{}, io.EOF
+}
+
+// csvReader reads CSV records with a header line naming the columns.
+// Empty cells are treated as null.
+type csvReader struct {
+	reader *csv.Reader
+	header []string
+}
+
+func newCSVReader(r io.Reader) (*csvReader, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1 // Checked against the header per record
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, errors.New("empty CSV file")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading CSV header: %w", err)
+	}
+	for i, name := range header {
+		header[i] = strings.TrimSpace(strings.TrimPrefix(name, "\ufeff"))
+	}
+	return &csvReader{reader: reader, header: header}, nil
+}
+
+func (c *csvReader) Read() (Record, error) {
+	values, err := c.reader.Read()
+	if err == io.EOF {
+		return Record{}, io.EOF
+	}
+	var parseErr *csv.ParseError
+	if errors.As(err, &parseErr) {
+		return Record{Line: parseErr.StartLine, Raw: strings.Join(values, ","), Err: err}, nil
+	}
+	if err != nil {
+		return Record{}, err
+	}
+
+	line, _ := c.reader.FieldPos(0)
+	record := Record{Line: line}
+	if len(values) != len(c.header) {
+		record.Raw = strings.Join(values, ",")
+		record.Err = fmt.Errorf("expected %d fields, got %d", len(c.header), len(values))
+		return record, nil
+	}
+	record.Fields = make(map[string]interface{}, len(values))
+	for i, value := range values {
+		if value != "" {
+			record.Fields[c.header[i]] = value
+		}
+	}
+	return record, nil
+}
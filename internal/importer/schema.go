@@ -0,0 +1,404 @@
+package importer
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/oracle/nosql-go-sdk/nosqldb/types"
+
+	"github.com/camikura/dito/internal/ddl"
+	"github.com/camikura/dito/internal/ui"
+)
+
+// Column is a column of the target table
+type Column struct {
+	Name       string
+	Type       string // Type as written in the DDL, e.g. INTEGER or TIMESTAMP(3)
+	PrimaryKey bool
+	Identity   bool // Generated identity or UUID column; may be omitted
+}
+
+// Schema maps input fields to the columns of the target table
+type Schema struct {
+	Table    string
+	Columns  []Column
+	ShardKey []string // Shard key columns; rows written together must share their values
+
+	byName map[string]int // Lower-case column name to index
+}
+
+// NewSchema builds the schema of tableName from its DDL and the DDLs of its
+// ancestors (root to parent). See NewSchemaFromTables.
+func NewSchema(tableName, ddlText string, ancestorDDLs []string) (*Schema, error) {
+	table, _ := ddl.Parse(ddlText)
+	var ancestors []*ddl.Table
+	for _, ancestorDDL := range ancestorDDLs {
+		ancestor, _ := ddl.Parse(ancestorDDL)
+		ancestors = append(ancestors, ancestor)
+	}
+	return NewSchemaFromTables(tableName, table, ancestors)
+}
+
+// NewSchemaFromTables builds the schema of tableName from its table schema and
+// the schemas of its ancestors (root to parent). Child tables inherit the
+// ancestors' primary key columns and the root table's shard key.
+func NewSchemaFromTables(tableName string, table *ddl.Table, ancestors []*ddl.Table) (*Schema, error) {
+	s := &Schema{Table: tableName, byName: make(map[string]int)}
+	add := func(col Column) {
+		key := strings.ToLower(col.Name)
+		if _, exists := s.byName[key]; !exists {
+			s.byName[key] = len(s.Columns)
+			s.Columns = append(s.Columns, col)
+		}
+	}
+
+	for _, ancestor := range ancestors {
+		for _, col := range ui.ColumnsFromTable(ancestor) {
+			if col.IsPrimaryKey {
+				add(Column{Name: col.Name, Type: col.Type, PrimaryKey: true})
+			}
+		}
+	}
+
+	for _, col := range ui.ColumnsFromTable(table) {
+		add(Column{Name: col.Name, Type: col.Type, PrimaryKey: col.IsPrimaryKey, Identity: col.Generated})
+	}
+	if len(s.Columns) == 0 {
+		return nil, fmt.Errorf("no columns found in the schema of %s", tableName)
+	}
+
+	root := table
+	if len(ancestors) > 0 && ancestors[0] != nil && len(ancestors[0].Columns) > 0 {
+		root = ancestors[0]
+	}
+	if root != nil {
+		s.ShardKey = root.ShardKey
+	}
+	return s, nil
+}
+
+// Column returns the column named name (case-insensitive)
+func (s *Schema) Column(name string) (Column, bool) {
+	index, ok := s.byName[strings.ToLower(name)]
+	if !ok {
+		return Column{}, false
+	}
+	return s.Columns[index], true
+}
+
+// ConvertField converts one input value (text, or a decoded JSON value) to the type of column name
+func (s *Schema) ConvertField(name string, value interface{}) (interface{}, error) {
+	col, ok := s.Column(name)
+	if !ok {
+		return nil, fmt.Errorf("unknown column %q", name)
+	}
+	converted, err := convertValue(col.Type, value)
+	if err != nil {
+		return nil, fmt.Errorf("column %s: %w", col.Name, err)
+	}
+	return converted, nil
+}
+
+// Convert maps input fields to a row of the table, converting each value to its column type.
+// Field names match columns case-insensitively; null and empty CSV values are left unset.
+func (s *Schema) Convert(fields map[string]interface{}) (*types.MapValue, error) {
+	row := types.NewOrderedMapValue()
+	for name, value := range fields {
+		col, ok := s.Column(name)
+		if !ok {
+			return nil, fmt.Errorf("unknown column %q", name)
+		}
+		if value == nil {
+			continue
+		}
+		converted, err := s.ConvertField(col.Name, value)
+		if err != nil {
+			return nil, err
+		}
+		row.Put(col.Name, converted)
+	}
+
+	for _, col := range s.Columns {
+		if col.PrimaryKey && !col.Identity {
+			if _, ok := row.Get(col.Name); !ok {
+				return nil, fmt.Errorf("missing primary key column %s", col.Name)
+			}
+		}
+	}
+	return row, nil
+}
+
+// PrimaryKey builds the primary key of a displayed row.
+// Key values are converted from their text form, so rows from query results can be used.
+func (s *Schema) PrimaryKey(row map[string]interface{}) (*types.MapValue, error) {
+	key := types.NewOrderedMapValue()
+	for _, col := range s.Columns {
+		if !col.PrimaryKey {
+			continue
+		}
+		value, ok := row[col.Name]
+		if !ok || value == nil {
+			return nil, fmt.Errorf("missing primary key column %s", col.Name)
+		}
+		text := fmt.Sprint(value)
+		if f, isFloat := value.(float64); isFloat {
+			// Query results decode numbers as float64; avoid exponent notation for integer keys
+			text = strconv.FormatFloat(f, 'f', -1, 64)
+		}
+		converted, err := s.ConvertField(col.Name, text)
+		if err != nil {
+			return nil, err
+		}
+		key.Put(col.Name, converted)
+	}
+	return key, nil
+}
+
+// ShardKeyOf returns a string identifying the row's shard key values
+func (s *Schema) ShardKeyOf(row *types.MapValue) string {
+	values := make([]string, len(s.ShardKey))
+	for i, name := range s.ShardKey {
+		value, _ := row.Get(name)
+		values[i] = fmt.Sprintf("%v", value)
+	}
+	return strings.Join(values, "\x00")
+}
+
+// IsJSONType reports whether values of type typ are written as JSON text:
+// JSON, ARRAY, MAP and RECORD columns
+func IsJSONType(typ string) bool {
+	switch TypeName(typ) {
+	case "JSON", "ARRAY", "MAP", "RECORD":
+		return true
+	}
+	return false
+}
+
+// TypeName returns the upper-case type name without parameters, e.g. TIMESTAMP for timestamp(3)
+func TypeName(typ string) string {
+	name := strings.ToUpper(strings.TrimSpace(typ))
+	if i := strings.Index(name, "("); i >= 0 {
+		name = strings.TrimSpace(name[:i])
+	}
+	return name
+}
+
+// timestampLayouts are the accepted TIMESTAMP input formats
+var timestampLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02",
+}
+
+// convertValue converts an input value (a string from CSV, or a decoded JSON value
+// with json.Number numbers) to the Go type written for a column of type typ
+func convertValue(typ string, value interface{}) (interface{}, error) {
+	baseType := TypeName(typ)
+	text, isText := value.(string)
+	if isText && text == "" && baseType != "STRING" && baseType != "JSON" {
+		return nil, fmt.Errorf("empty value for %s", typ)
+	}
+
+	switch baseType {
+	case "INTEGER":
+		n, err := parseInt(value)
+		if err != nil {
+			return nil, err
+		}
+		if n < math.MinInt32 || n > math.MaxInt32 {
+			return nil, fmt.Errorf("%d is out of range for INTEGER", n)
+		}
+		return int(n), nil
+
+	case "LONG":
+		return parseInt(value)
+
+	case "FLOAT", "DOUBLE":
+		return parseFloat(value)
+
+	case "NUMBER":
+		var s string
+		switch v := value.(type) {
+		case string:
+			s = v
+		case json.Number:
+			s = v.String()
+		default:
+			return nil, fmt.Errorf("expected a number, got %T", value)
+		}
+		r, ok := new(big.Rat).SetString(s)
+		if !ok {
+			return nil, fmt.Errorf("invalid number %q", s)
+		}
+		return r, nil
+
+	case "BOOLEAN":
+		switch v := value.(type) {
+		case bool:
+			return v, nil
+		case string:
+			b, err := strconv.ParseBool(v)
+			if err != nil {
+				return nil, fmt.Errorf("invalid boolean %q", v)
+			}
+			return b, nil
+		}
+		return nil, fmt.Errorf("expected a boolean, got %T", value)
+
+	case "STRING", "ENUM":
+		if !isText {
+			return nil, fmt.Errorf("expected a string, got %T", value)
+		}
+		return text, nil
+
+	case "TIMESTAMP":
+		if !isText {
+			return nil, fmt.Errorf("expected a timestamp string, got %T", value)
+		}
+		for _, layout := range timestampLayouts {
+			if t, err := time.Parse(layout, text); err == nil {
+				return t, nil
+			}
+		}
+		return nil, fmt.Errorf("invalid timestamp %q", text)
+
+	case "BINARY", "FIXED_BINARY":
+		if !isText {
+			return nil, fmt.Errorf("expected base64 data, got %T", value)
+		}
+		data, err := base64.StdEncoding.DecodeString(text)
+		if err != nil {
+			return nil, fmt.Errorf("invalid base64 data")
+		}
+		return data, nil
+
+	case "JSON":
+		if isText {
+			// CSV cells hold JSON text; anything else is a plain string
+			if decoded, err := decodeJSON(text); err == nil {
+				return decoded, nil
+			}
+		}
+		return value, nil
+
+	case "ARRAY", "MAP", "RECORD":
+		if isText {
+			decoded, err := decodeJSON(text)
+			if err != nil {
+				return nil, fmt.Errorf("invalid JSON for %s", baseType)
+			}
+			value = decoded
+		}
+		switch value.(type) {
+		case []interface{}:
+			if baseType == "ARRAY" {
+				return value, nil
+			}
+		case map[string]interface{}:
+			if baseType != "ARRAY" {
+				return value, nil
+			}
+		}
+		return nil, fmt.Errorf("expected %s, got %T", baseType, value)
+	}
+
+	// Unknown types are passed through and validated by the server
+	return value, nil
+}
+
+// FormatValue returns the text form of a value read with the SDK, in the format
+// accepted back by ConvertField for a column of type typ. Null is returned as "".
+// Values of JSON columns are always written as JSON, so strings are quoted.
+func FormatValue(typ string, value interface{}) string {
+	if value != nil && IsJSONType(typ) {
+		if data, err := json.Marshal(value); err == nil {
+			return string(data)
+		}
+	}
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	case *big.Rat:
+		return ratString(v)
+	case []byte:
+		return base64.StdEncoding.EncodeToString(v)
+	case *types.MapValue, map[string]interface{}, []interface{}:
+		if data, err := json.Marshal(v); err == nil {
+			return string(data)
+		}
+	}
+	return fmt.Sprint(value)
+}
+
+// ratString formats a number as the shortest exact decimal, falling back to a fraction
+func ratString(r *big.Rat) string {
+	if r.IsInt() {
+		return r.Num().String()
+	}
+	for prec := 1; prec <= 64; prec++ {
+		text := r.FloatString(prec)
+		if parsed, ok := new(big.Rat).SetString(text); ok && parsed.Cmp(r) == 0 {
+			return text
+		}
+	}
+	return r.String()
+}
+
+func parseInt(value interface{}) (int64, error) {
+	var s string
+	switch v := value.(type) {
+	case string:
+		s = v
+	case json.Number:
+		s = v.String()
+	default:
+		return 0, fmt.Errorf("expected an integer, got %T", value)
+	}
+	n, err := strconv.ParseInt(strings.TrimSpace(s), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid integer %q", s)
+	}
+	return n, nil
+}
+
+func parseFloat(value interface{}) (float64, error) {
+	var s string
+	switch v := value.(type) {
+	case string:
+		s = v
+	case json.Number:
+		s = v.String()
+	default:
+		return 0, fmt.Errorf("expected a number, got %T", value)
+	}
+	f, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid number %q", s)
+	}
+	return f, nil
+}
+
+// decodeJSON decodes JSON text keeping numbers as json.Number
+func decodeJSON(text string) (interface{}, error) {
+	decoder := json.NewDecoder(strings.NewReader(text))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	if decoder.More() {
+		return nil, fmt.Errorf("trailing data after JSON value")
+	}
+	return value, nil
+}
@@ -0,0 +1,291 @@
+// Package output writes query result rows as an aligned table, JSON lines, a JSON array, CSV or TSV.
+package output
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/camikura/dito/internal/ui"
+)
+
+// Format is an output format name.
+type Format string
+
+const (
+	FormatTable Format = "table"
+	FormatJSONL Format = "jsonl"
+	FormatJSON  Format = "json"
+	FormatCSV   Format = "csv"
+	FormatTSV   Format = "tsv"
+)
+
+// Formats returns the supported formats in display order.
+func Formats() []Format {
+	return []Format{FormatTable, FormatJSONL, FormatJSON, FormatCSV, FormatTSV}
+}
+
+// ParseFormat validates a format name (case-insensitive).
+func ParseFormat(value string) (Format, error) {
+	for _, format := range Formats() {
+		if strings.EqualFold(value, string(format)) {
+			return format, nil
+		}
+	}
+	return "", fmt.Errorf("unknown format %q (want table, jsonl, json, csv or tsv)", value)
+}
+
+// Writer writes rows with a fixed column order.
+// Close must be called to flush buffered output.
+type Writer interface {
+	WriteRow(row map[string]interface{}) error
+	Close() error
+}
+
+// NewWriter returns a writer for format that writes to w.
+// Columns not present in a row are written as null (or empty for CSV/TSV).
+func NewWriter(w io.Writer, format Format, columns []string) (Writer, error) {
+	switch format {
+	case FormatTable:
+		return &tableWriter{w: w, columns: columns}, nil
+	case FormatJSONL:
+		return &jsonlWriter{w: w, columns: columns}, nil
+	case FormatJSON:
+		return &jsonWriter{w: w, columns: columns}, nil
+	case FormatCSV:
+		writer := &csvWriter{w: csv.NewWriter(w), columns: columns}
+		if err := writer.w.Write(columns); err != nil {
+			return nil, err
+		}
+		return writer, nil
+	case FormatTSV:
+		writer := &tsvWriter{w: w, columns: columns}
+		if err := writer.writeLine(columns); err != nil {
+			return nil, err
+		}
+		return writer, nil
+	}
+	return nil, fmt.Errorf("unknown format %q", format)
+}
+
+var tableEscaper = strings.NewReplacer("\r", `\r`, "\n", `\n`, "\t", " ")
+
+// tableWriter buffers all rows to align the columns
+type tableWriter struct {
+	w       io.Writer
+	columns []string
+	rows    [][]string
+}
+
+func (t *tableWriter) WriteRow(row map[string]interface{}) error {
+	cells := make([]string, len(t.columns))
+	for i, col := range t.columns {
+		// Keep each row on one line
+		cells[i] = tableEscaper.Replace(ui.FormatValue(row[col]))
+	}
+	t.rows = append(t.rows, cells)
+	return nil
+}
+
+func (t *tableWriter) Close() error {
+	widths := make([]int, len(t.columns))
+	for i, col := range t.columns {
+		widths[i] = lipgloss.Width(col)
+	}
+	for _, cells := range t.rows {
+		for i, cell := range cells {
+			if w := lipgloss.Width(cell); w > widths[i] {
+				widths[i] = w
+			}
+		}
+	}
+
+	separators := make([]string, len(t.columns))
+	for i, width := range widths {
+		separators[i] = strings.Repeat("-", width)
+	}
+
+	var buf bytes.Buffer
+	writeTableLine(&buf, t.columns, widths)
+	writeTableLine(&buf, separators, widths)
+	for _, cells := range t.rows {
+		writeTableLine(&buf, cells, widths)
+	}
+	_, err := t.w.Write(buf.Bytes())
+	return err
+}
+
+// writeTableLine writes cells padded to widths, separated by two spaces
+func writeTableLine(buf *bytes.Buffer, cells []string, widths []int) {
+	for i, cell := range cells {
+		if i > 0 {
+			buf.WriteString("  ")
+		}
+		buf.WriteString(cell)
+		// No trailing spaces after the last column
+		if i < len(cells)-1 {
+			buf.WriteString(strings.Repeat(" ", widths[i]-lipgloss.Width(cell)))
+		}
+	}
+	buf.WriteByte('\n')
+}
+
+// jsonlWriter writes one JSON object per row, keeping the column order
+type jsonlWriter struct {
+	w       io.Writer
+	columns []string
+}
+
+func (j *jsonlWriter) WriteRow(row map[string]interface{}) error {
+	line, err := MarshalRow(row, j.columns)
+	if err != nil {
+		return err
+	}
+	_, err = j.w.Write(append(line, '\n'))
+	return err
+}
+
+func (j *jsonlWriter) Close() error {
+	return nil
+}
+
+// jsonWriter writes a JSON array with one row object per line
+type jsonWriter struct {
+	w       io.Writer
+	columns []string
+	rows    int
+}
+
+func (j *jsonWriter) WriteRow(row map[string]interface{}) error {
+	line, err := MarshalRow(row, j.columns)
+	if err != nil {
+		return err
+	}
+	prefix := ",\n  "
+	if j.rows == 0 {
+		prefix = "[\n  "
+	}
+	j.rows++
+	_, err = j.w.Write(append([]byte(prefix), line...))
+	return err
+}
+
+func (j *jsonWriter) Close() error {
+	closing := "\n]\n"
+	if j.rows == 0 {
+		closing = "[]\n"
+	}
+	_, err := io.WriteString(j.w, closing)
+	return err
+}
+
+// MarshalRow encodes row as a JSON object with keys in column order.
+// Keys missing from columns are appended in alphabetical order.
+func MarshalRow(row map[string]interface{}, columns []string) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	seen := make(map[string]bool, len(columns))
+	keys := make([]string, 0, len(row))
+	for _, col := range columns {
+		seen[col] = true
+		keys = append(keys, col)
+	}
+	var extra []string
+	for key := range row {
+		if !seen[key] {
+			extra = append(extra, key)
+		}
+	}
+	sort.Strings(extra)
+	keys = append(keys, extra...)
+
+	for i, key := range keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		name, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(row[key])
+		if err != nil {
+			return nil, fmt.Errorf("column %s: %w", key, err)
+		}
+		buf.Write(name)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// csvWriter writes RFC 4180 CSV records
+type csvWriter struct {
+	w       *csv.Writer
+	columns []string
+}
+
+func (c *csvWriter) WriteRow(row map[string]interface{}) error {
+	record := make([]string, len(c.columns))
+	for i, col := range c.columns {
+		record[i] = FormatCell(row[col])
+	}
+	return c.w.Write(record)
+}
+
+func (c *csvWriter) Close() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+// tsvWriter writes tab-separated lines.
+// Tabs, newlines and backslashes are escaped instead of quoted so each record stays on one line.
+type tsvWriter struct {
+	w       io.Writer
+	columns []string
+}
+
+var tsvEscaper = strings.NewReplacer(`\`, `\\`, "\t", `\t`, "\n", `\n`, "\r", `\r`)
+
+func (t *tsvWriter) WriteRow(row map[string]interface{}) error {
+	fields := make([]string, len(t.columns))
+	for i, col := range t.columns {
+		fields[i] = FormatCell(row[col])
+	}
+	return t.writeLine(fields)
+}
+
+func (t *tsvWriter) writeLine(fields []string) error {
+	escaped := make([]string, len(fields))
+	for i, field := range fields {
+		escaped[i] = tsvEscaper.Replace(field)
+	}
+	_, err := io.WriteString(t.w, strings.Join(escaped, "\t")+"\n")
+	return err
+}
+
+func (t *tsvWriter) Close() error {
+	return nil
+}
+
+// FormatCell formats a value for a delimited file.
+// Null is empty, and JSON objects and arrays are written as compact JSON.
+func FormatCell(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case map[string]interface{}, []interface{}:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprintf("%v", v)
+		}
+		return string(data)
+	}
+	return fmt.Sprintf("%v", value)
+}
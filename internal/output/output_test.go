@@ -0,0 +1,125 @@
+package output
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseFormat(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    Format
+		wantErr bool
+	}{
+		{input: "table", want: FormatTable},
+		{input: "JSONL", want: FormatJSONL},
+		{input: "json", want: FormatJSON},
+		{input: "csv", want: FormatCSV},
+		{input: "tsv", want: FormatTSV},
+		{input: "xml", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseFormat(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseFormat(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseFormat(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriter(t *testing.T) {
+	columns := []string{"id", "name", "tags"}
+	rows := []map[string]interface{}{
+		{"id": 1, "name": "Alice", "tags": []interface{}{"a", "b"}},
+		{"id": 2, "name": "Bob\tSmith, Jr.", "tags": nil},
+	}
+
+	tests := []struct {
+		format Format
+		want   string
+	}{
+		{
+			format: FormatTable,
+			want: "id  name            tags\n" +
+				"--  --------------  ---------\n" +
+				"1   Alice           [\"a\",\"b\"]\n" +
+				"2   Bob Smith, Jr.  (null)\n",
+		},
+		{
+			format: FormatJSONL,
+			want: `{"id":1,"name":"Alice","tags":["a","b"]}` + "\n" +
+				`{"id":2,"name":"Bob\tSmith, Jr.","tags":null}` + "\n",
+		},
+		{
+			format: FormatJSON,
+			want: "[\n" +
+				`  {"id":1,"name":"Alice","tags":["a","b"]},` + "\n" +
+				`  {"id":2,"name":"Bob\tSmith, Jr.","tags":null}` + "\n" +
+				"]\n",
+		},
+		{
+			format: FormatCSV,
+			want: "id,name,tags\n" +
+				"1,Alice,\"[\"\"a\"\",\"\"b\"\"]\"\n" +
+				"2,\"Bob\tSmith, Jr.\",\n",
+		},
+		{
+			format: FormatTSV,
+			want: "id\tname\ttags\n" +
+				"1\tAlice\t[\"a\",\"b\"]\n" +
+				"2\tBob\\tSmith, Jr.\t\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.format), func(t *testing.T) {
+			var buf strings.Builder
+			w, err := NewWriter(&buf, tt.format, columns)
+			if err != nil {
+				t.Fatalf("NewWriter() error = %v", err)
+			}
+			for _, row := range rows {
+				if err := w.WriteRow(row); err != nil {
+					t.Fatalf("WriteRow() error = %v", err)
+				}
+			}
+			if err := w.Close(); err != nil {
+				t.Fatalf("Close() error = %v", err)
+			}
+			if got := buf.String(); got != tt.want {
+				t.Errorf("output =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJSONWriterEmpty(t *testing.T) {
+	var buf strings.Builder
+	w, err := NewWriter(&buf, FormatJSON, []string{"id"})
+	if err != nil {
+		t.Fatalf("NewWriter() error = %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if got := buf.String(); got != "[]\n" {
+		t.Errorf("output = %q, want %q", got, "[]\n")
+	}
+}
+
+func TestMarshalRowKeepsColumnOrder(t *testing.T) {
+	row := map[string]interface{}{"z": 1, "a": "x", "extra": true, "b": nil}
+
+	got, err := MarshalRow(row, []string{"z", "a", "b"})
+	if err != nil {
+		t.Fatalf("MarshalRow() error = %v", err)
+	}
+	if want := `{"z":1,"a":"x","b":null,"extra":true}`; string(got) != want {
+		t.Errorf("MarshalRow() = %s, want %s", got, want)
+	}
+}
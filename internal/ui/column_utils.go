@@ -3,6 +3,8 @@ package ui
 import (
 	"sort"
 	"strings"
+
+	"github.com/camikura/dito/internal/ddl"
 )
 
 // ColumnInfo represents a column with its name and type.
@@ -10,7 +12,9 @@ type ColumnInfo struct {
 	Name         string
 	Type         string
 	IsPrimaryKey bool
-	IsInherited  bool // True if this column is inherited from a parent table
+	IsInherited  bool     // True if this column is inherited from a parent table
+	Attributes   []string // Clauses after the type, e.g. "NOT NULL" or "DEFAULT 0"
+	Generated    bool     // Identity or generated UUID column
 }
 
 // GetParentTableName returns the parent table name for a child table.
@@ -41,91 +45,57 @@ func GetAncestorTableNames(tableName string) []string {
 	return ancestors
 }
 
-// ParsePrimaryKeysFromDDL extracts primary key column names from DDL string.
-func ParsePrimaryKeysFromDDL(ddl string) []string {
-	var primaryKeys []string
-
-	// Find PRIMARY KEY(col1, col2, ...) part
-	upperDDL := strings.ToUpper(ddl)
-	pkIndex := strings.Index(upperDDL, "PRIMARY KEY")
-	if pkIndex == -1 {
-		return primaryKeys
-	}
-
-	// Get content inside parentheses after PRIMARY KEY
-	pkPart := ddl[pkIndex:]
-	start := strings.Index(pkPart, "(")
-	end := strings.LastIndex(pkPart, ")") // Get last parenthesis
-	if start == -1 || end == -1 || start >= end {
-		return primaryKeys
+// parseDDL parses a CREATE TABLE statement, keeping what could be parsed
+// before a syntax error. Returns an empty table when nothing could be parsed.
+func parseDDL(ddlText string) *ddl.Table {
+	table, _ := ddl.Parse(ddlText)
+	if table == nil {
+		return &ddl.Table{}
 	}
+	return table
+}
 
-	// Extract column name list
-	keysPart := pkPart[start+1 : end]
-
-	// Handle SHARD() syntax
-	// Support format like PRIMARY KEY(SHARD(id), name)
-	keysPart = strings.ReplaceAll(keysPart, "SHARD(", "")
-	keysPart = strings.ReplaceAll(keysPart, "shard(", "")
-	keysPart = strings.ReplaceAll(keysPart, ")", "")
-
-	keys := strings.Split(keysPart, ",")
-	for _, key := range keys {
-		key = strings.TrimSpace(key)
-		if key != "" {
-			primaryKeys = append(primaryKeys, key)
-		}
-	}
+// ParsePrimaryKeysFromDDL extracts primary key column names from DDL string.
+func ParsePrimaryKeysFromDDL(ddl string) []string {
+	return parseDDL(ddl).PrimaryKey
+}
 
-	return primaryKeys
+// ParseShardKeysFromDDL extracts the shard key column names from DDL string.
+// The shard key is the SHARD(...) part of the primary key, or the whole primary key when not given.
+func ParseShardKeysFromDDL(ddl string) []string {
+	return parseDDL(ddl).ShardKey
 }
 
 // ParseColumnsFromDDL extracts column information from DDL string.
 func ParseColumnsFromDDL(ddl string, primaryKeys []string) []ColumnInfo {
-	var columns []ColumnInfo
-
 	// Create PRIMARY KEY map for fast lookup
 	pkMap := make(map[string]bool)
 	for _, pk := range primaryKeys {
 		pkMap[pk] = true
 	}
 
-	// Extract column definition part from CREATE TABLE ... ( ... )
-	start := strings.Index(ddl, "(")
-	end := strings.LastIndex(ddl, ")")
-	if start == -1 || end == -1 || start >= end {
-		return columns
+	columns := ColumnsFromTable(parseDDL(ddl))
+	for i := range columns {
+		columns[i].IsPrimaryKey = pkMap[columns[i].Name]
 	}
+	return columns
+}
 
-	columnDefs := ddl[start+1 : end]
-
-	// Exclude PRIMARY KEY definition
-	lines := strings.Split(columnDefs, ",")
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-
-		// Skip PRIMARY KEY line
-		if strings.HasPrefix(strings.ToUpper(line), "PRIMARY KEY") {
-			continue
-		}
-
-		// Extract column name and type
-		parts := strings.Fields(line)
-		if len(parts) >= 2 {
-			name := parts[0]
-			typ := parts[1]
-
-			// Check if it's a PRIMARY KEY
-			isPK := pkMap[name]
-
-			columns = append(columns, ColumnInfo{
-				Name:         name,
-				Type:         typ,
-				IsPrimaryKey: isPK,
-			})
-		}
+// ColumnsFromTable returns the columns of a table schema in definition order.
+func ColumnsFromTable(table *ddl.Table) []ColumnInfo {
+	if table == nil {
+		return nil
+	}
+	var columns []ColumnInfo
+	for _, col := range table.Columns {
+		columns = append(columns, ColumnInfo{
+			Name:         col.Name,
+			Type:         col.Type.String(),
+			IsPrimaryKey: col.PrimaryKey,
+			Attributes:   col.Attributes(),
+			Generated:    col.Generated(),
+		})
 	}
-
 	return columns
 }
 
@@ -138,36 +108,41 @@ func GetColumnsInSchemaOrder(ddl string, rows []map[string]interface{}) []string
 // GetColumnsInSchemaOrderWithAncestors returns column names in schema definition order,
 // including inherited primary key columns from ancestor tables.
 // ancestorDDLs should be in order from root to immediate parent.
-func GetColumnsInSchemaOrderWithAncestors(ddl string, ancestorDDLs []string, rows []map[string]interface{}) []string {
+func GetColumnsInSchemaOrderWithAncestors(ddlText string, ancestorDDLs []string, rows []map[string]interface{}) []string {
+	var table *ddl.Table
+	if ddlText != "" {
+		table = parseDDL(ddlText)
+	}
+	var ancestors []*ddl.Table
+	for _, ancestorDDL := range ancestorDDLs {
+		ancestors = append(ancestors, parseDDL(ancestorDDL))
+	}
+	return GetColumnsInSchemaOrderFromTables(table, ancestors, rows)
+}
+
+// GetColumnsInSchemaOrderFromTables returns column names in schema definition order,
+// including inherited primary key columns from the ancestor table schemas
+// (root to immediate parent), followed by any other columns found in rows.
+func GetColumnsInSchemaOrderFromTables(table *ddl.Table, ancestors []*ddl.Table, rows []map[string]interface{}) []string {
 	var columns []string
 	columnSet := make(map[string]bool)
 
 	// First, add primary key columns from ancestors (root to parent order)
-	for _, ancestorDDL := range ancestorDDLs {
-		if ancestorDDL != "" {
-			ancestorPKs := ParsePrimaryKeysFromDDL(ancestorDDL)
-			ancestorCols := ParseColumnsFromDDL(ancestorDDL, ancestorPKs)
-			// Only add primary key columns from ancestors
-			for _, col := range ancestorCols {
-				if col.IsPrimaryKey && !columnSet[col.Name] {
-					columns = append(columns, col.Name)
-					columnSet[col.Name] = true
-				}
+	for _, ancestor := range ancestors {
+		// Only add primary key columns from ancestors
+		for _, col := range ColumnsFromTable(ancestor) {
+			if col.IsPrimaryKey && !columnSet[col.Name] {
+				columns = append(columns, col.Name)
+				columnSet[col.Name] = true
 			}
 		}
 	}
 
-	// Then add this table's own columns from DDL
-	if ddl != "" {
-		primaryKeys := ParsePrimaryKeysFromDDL(ddl)
-		cols := ParseColumnsFromDDL(ddl, primaryKeys)
-
-		// Extract column names in schema order
-		for _, col := range cols {
-			if !columnSet[col.Name] {
-				columns = append(columns, col.Name)
-				columnSet[col.Name] = true
-			}
+	// Then add this table's own columns in schema order
+	for _, col := range ColumnsFromTable(table) {
+		if !columnSet[col.Name] {
+			columns = append(columns, col.Name)
+			columnSet[col.Name] = true
 		}
 	}
 
@@ -214,18 +189,17 @@ func GetColumnsFromData(rows []map[string]interface{}) []string {
 // GetColumnTypes extracts column types from DDL.
 // Returns a map of column name to type (without Primary Key suffix).
 func GetColumnTypes(ddl string) map[string]string {
-	types := make(map[string]string)
-
 	if ddl == "" {
-		return types
+		return make(map[string]string)
 	}
+	return ColumnTypes(parseDDL(ddl))
+}
 
-	primaryKeys := ParsePrimaryKeysFromDDL(ddl)
-	cols := ParseColumnsFromDDL(ddl, primaryKeys)
-
-	for _, col := range cols {
+// ColumnTypes returns a map of column name to type for a table schema.
+func ColumnTypes(table *ddl.Table) map[string]string {
+	types := make(map[string]string)
+	for _, col := range ColumnsFromTable(table) {
 		types[col.Name] = col.Type
 	}
-
 	return types
 }
@@ -2,6 +2,7 @@ package ui
 
 import (
 	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -120,6 +121,43 @@ func TestParsePrimaryKeysFromDDL(t *testing.T) {
 	}
 }
 
+func TestParseShardKeysFromDDL(t *testing.T) {
+	tests := []struct {
+		name     string
+		ddl      string
+		expected []string
+	}{
+		{
+			name:     "shard key",
+			ddl:      "CREATE TABLE items (id INTEGER, name STRING, PRIMARY KEY(SHARD(id), name))",
+			expected: []string{"id"},
+		},
+		{
+			name:     "composite shard key",
+			ddl:      "CREATE TABLE items (a INTEGER, b INTEGER, c STRING, PRIMARY KEY(shard(a, b), c))",
+			expected: []string{"a", "b"},
+		},
+		{
+			name:     "defaults to primary key",
+			ddl:      "CREATE TABLE orders (user_id INTEGER, order_id INTEGER, PRIMARY KEY(user_id, order_id))",
+			expected: []string{"user_id", "order_id"},
+		},
+		{
+			name: "no primary key",
+			ddl:  "CREATE TABLE simple (id INTEGER)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := ParseShardKeysFromDDL(tt.ddl)
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("ParseShardKeysFromDDL() = %v, want %v", result, tt.expected)
+			}
+		})
+	}
+}
+
 func TestParseColumnsFromDDL(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -147,6 +185,18 @@ func TestParseColumnsFromDDL(t *testing.T) {
 				{Name: "active", Type: "BOOLEAN", IsPrimaryKey: false},
 			},
 		},
+		{
+			name: "complex types, defaults and identity",
+			ddl: "CREATE TABLE t (id LONG GENERATED ALWAYS AS IDENTITY, address RECORD(street STRING, city STRING), " +
+				"props MAP(STRING), qty INTEGER NOT NULL DEFAULT 1, PRIMARY KEY(id)) USING TTL 3 DAYS",
+			primaryKeys: []string{"id"},
+			expected: []ColumnInfo{
+				{Name: "id", Type: "LONG", IsPrimaryKey: true, Attributes: []string{"GENERATED ALWAYS AS IDENTITY"}, Generated: true},
+				{Name: "address", Type: "RECORD(street STRING, city STRING)"},
+				{Name: "props", Type: "MAP(STRING)"},
+				{Name: "qty", Type: "INTEGER", Attributes: []string{"DEFAULT 1", "NOT NULL"}},
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -166,6 +216,9 @@ func TestParseColumnsFromDDL(t *testing.T) {
 				if col.IsPrimaryKey != tt.expected[i].IsPrimaryKey {
 					t.Errorf("column[%d].IsPrimaryKey = %v, want %v", i, col.IsPrimaryKey, tt.expected[i].IsPrimaryKey)
 				}
+				if strings.Join(col.Attributes, "; ") != strings.Join(tt.expected[i].Attributes, "; ") || col.Generated != tt.expected[i].Generated {
+					t.Errorf("column[%d] = %+v, want %+v", i, col, tt.expected[i])
+				}
 			}
 		})
 	}
@@ -2,6 +2,7 @@ package ui
 
 import (
 	"fmt"
+	"strings"
 )
 
 // TextField renders a text input field with optional cursor support.
@@ -62,6 +63,12 @@ func TextField(value string, width int, focused bool, cursorPos int) string {
 	return StyleNormal.Render(formattedText)
 }
 
+// MaskText replaces every character of a secret with an asterisk,
+// keeping the rune count so cursor positions stay valid.
+func MaskText(value string) string {
+	return strings.Repeat("*", RuneLen(value))
+}
+
 // Button renders a button with focus indicator.
 // When focused, uses background color highlighting.
 func Button(label string, focused bool) string {
@@ -111,6 +111,26 @@ func TestButton(t *testing.T) {
 	}
 }
 
+func TestMaskText(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{name: "empty", input: "", want: ""},
+		{name: "ascii", input: "secret", want: "******"},
+		{name: "multi-byte keeps rune count", input: "パス", want: "**"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := MaskText(tt.input); got != tt.want {
+				t.Errorf("MaskText(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
 func TestCheckbox(t *testing.T) {
 	tests := []struct {
 		name    string
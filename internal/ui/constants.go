@@ -51,6 +51,12 @@ const (
 	// DefaultFetchSize is the default number of rows to fetch at once.
 	DefaultFetchSize = 100
 
+	// ExportFetchSize is the number of rows fetched per page when exporting.
+	ExportFetchSize = 1000
+
+	// ImportStepRecords is the number of records imported between progress updates.
+	ImportStepRecords = 500
+
 	// FetchMoreThreshold is the number of remaining rows that triggers fetching more data.
 	FetchMoreThreshold = 10
 )
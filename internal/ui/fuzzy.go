@@ -0,0 +1,61 @@
+package ui
+
+import "unicode"
+
+// FuzzyMatch reports whether the characters of pattern appear in text in the
+// same order (case-insensitive), e.g. "usph" matches "users.phones". Returns
+// the rune positions of text that matched, preferring the first character of a
+// word (after ".", ":", "_" or "$") when the rest of the pattern still matches.
+func FuzzyMatch(pattern, text string) ([]int, bool) {
+	patternRunes := []rune(toLower(pattern))
+	textRunes := []rune(toLower(text))
+	if !isSubsequence(patternRunes, textRunes) {
+		return nil, false
+	}
+
+	positions := make([]int, 0, len(patternRunes))
+	start := 0
+	for k, p := range patternRunes {
+		pos := -1
+		for i := start; i < len(textRunes); i++ {
+			if textRunes[i] != p || !isSubsequence(patternRunes[k+1:], textRunes[i+1:]) {
+				continue
+			}
+			if pos < 0 {
+				pos = i
+			}
+			if i == 0 || isWordSeparator(textRunes[i-1]) {
+				pos = i
+				break
+			}
+		}
+		positions = append(positions, pos)
+		start = pos + 1
+	}
+	return positions, true
+}
+
+// isSubsequence reports whether the runes of pattern appear in text in order
+func isSubsequence(pattern, text []rune) bool {
+	k := 0
+	for _, r := range text {
+		if k < len(pattern) && r == pattern[k] {
+			k++
+		}
+	}
+	return k == len(pattern)
+}
+
+// toLower lowercases s rune by rune, keeping its rune positions
+func toLower(s string) string {
+	runes := []rune(s)
+	for i, r := range runes {
+		runes[i] = unicode.ToLower(r)
+	}
+	return string(runes)
+}
+
+// isWordSeparator reports whether r separates the words of a table name
+func isWordSeparator(r rune) bool {
+	return r == '.' || r == ':' || r == '_' || r == '$'
+}
@@ -0,0 +1,35 @@
+package ui
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFuzzyMatch(t *testing.T) {
+	tests := []struct {
+		name      string
+		pattern   string
+		text      string
+		positions []int
+		matched   bool
+	}{
+		{name: "empty pattern", pattern: "", text: "users", positions: []int{}, matched: true},
+		{name: "prefix", pattern: "use", text: "users", positions: []int{0, 1, 2}, matched: true},
+		{name: "case-insensitive", pattern: "USR", text: "Users", positions: []int{0, 1, 3}, matched: true},
+		{name: "child path", pattern: "usph", text: "users.phones", positions: []int{0, 1, 6, 7}, matched: true},
+		{name: "prefers word starts", pattern: "ua", text: "users_addresses", positions: []int{0, 6}, matched: true},
+		{name: "namespace", pattern: "ns1:o", text: "ns1:orders", positions: []int{0, 1, 2, 3, 4}, matched: true},
+		{name: "word start only when the rest matches", pattern: "xbc", text: "xbc_b", positions: []int{0, 1, 2}, matched: true},
+		{name: "out of order", pattern: "su", text: "users", matched: false},
+		{name: "missing character", pattern: "usz", text: "users", matched: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			positions, matched := FuzzyMatch(tt.pattern, tt.text)
+			if matched != tt.matched || !reflect.DeepEqual(positions, tt.positions) {
+				t.Errorf("FuzzyMatch(%q, %q) = %v, %v, want %v, %v", tt.pattern, tt.text, positions, matched, tt.positions, tt.matched)
+			}
+		})
+	}
+}
@@ -13,6 +13,7 @@ import (
 // - Vertical scrolling (row-based)
 // - Cell truncation with ellipsis
 // - Row selection highlighting
+// - Marked rows (shown in light red)
 // - Numeric column right-alignment
 type Grid struct {
 	// Data
@@ -24,6 +25,9 @@ type Grid struct {
 	VerticalOffset   int // Row offset for vertical scroll
 	SelectedRow      int // Currently selected row index (absolute)
 
+	// Marked rows
+	Marked map[int]bool // Marked row indexes (absolute), e.g. for deletion
+
 	// Display dimensions
 	Width  int // Available width for rendering
 	Height int // Available height (number of rows including header)
@@ -146,7 +150,7 @@ func (g *Grid) Render() string {
 		rowIndex := g.VerticalOffset + i
 		if rowIndex < len(g.Rows) {
 			isSelected := rowIndex == g.SelectedRow
-			rowLine := g.renderRow(g.Rows[rowIndex], isSelected)
+			rowLine := g.renderRow(g.Rows[rowIndex], isSelected, g.Marked[rowIndex])
 			lines = append(lines, rowLine)
 		} else if rowIndex == len(g.Rows) && g.ShowLoading && g.HasMore {
 			// Show loading indicator at the position after last row
@@ -194,8 +198,8 @@ func (g *Grid) renderSeparator() string {
 	return g.applyHorizontalScroll(fullLine)
 }
 
-// renderRow renders a single data row with optional selection highlighting.
-func (g *Grid) renderRow(row map[string]interface{}, isSelected bool) string {
+// renderRow renders a single data row with optional selection and mark highlighting.
+func (g *Grid) renderRow(row map[string]interface{}, isSelected bool, isMarked bool) string {
 	// Build full row line WITHOUT styles first (for correct width calculation)
 	var parts []string
 	var nullPositions []nullRegion // Track null value positions
@@ -226,6 +230,19 @@ func (g *Grid) renderRow(row map[string]interface{}, isSelected bool) string {
 	// Apply horizontal scroll and width constraint (on unstyled text)
 	scrolledLine := g.applyHorizontalScroll(fullLine)
 
+	// Marked rows are shown in light red as a whole, over the selection background if selected
+	if isMarked {
+		style := lipgloss.NewStyle().Foreground(ColorErrorLight)
+		if isSelected {
+			bgColor := ColorPrimaryBg
+			if !g.IsFocused {
+				bgColor = ColorGrayLightBg
+			}
+			style = style.Background(bgColor)
+		}
+		return style.Render(scrolledLine)
+	}
+
 	// Apply null styling after scrolling
 	if len(nullPositions) > 0 && !isSelected {
 		scrolledLine = g.applyNullStyling(scrolledLine, nullPositions)
@@ -3,6 +3,8 @@ package ui
 import (
 	"strings"
 	"testing"
+
+	"github.com/charmbracelet/lipgloss"
 )
 
 func TestGrid_NewGrid(t *testing.T) {
@@ -276,6 +278,26 @@ func TestGrid_Render_Truncation(t *testing.T) {
 	}
 }
 
+func TestGrid_Render_Marked(t *testing.T) {
+	columns := []string{"id", "name"}
+	rows := []map[string]interface{}{
+		{"id": 1, "name": nil},
+		{"id": 2, "name": "Bob"},
+	}
+
+	g := NewGrid(columns, nil, rows)
+	g.Width = 20
+	g.Height = 4
+	g.SelectedRow = 1
+	g.Marked = map[int]bool{0: true, 1: true}
+
+	for i, line := range strings.Split(g.Render(), "\n") {
+		if w := lipgloss.Width(line); w != g.Width {
+			t.Errorf("Line %d width = %d, expected %d: %q", i, w, g.Width, line)
+		}
+	}
+}
+
 func TestGrid_Render_EmptyData(t *testing.T) {
 	g := NewGrid([]string{"id"}, nil, []map[string]interface{}{})
 	g.Width = 20
@@ -0,0 +1,66 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+)
+
+// IndexFieldTypes are the types that can be declared for an index field.
+// JSON fields must be indexed with a type; "" indexes a typed column as is.
+var IndexFieldTypes = []string{
+	"", "ANYATOMIC", "STRING", "INTEGER", "LONG", "DOUBLE", "NUMBER", "BOOLEAN", "POINT", "GEOMETRY",
+}
+
+// IndexField is a field of an index being designed.
+type IndexField struct {
+	Path string // Column name or path, e.g. "doc.address.city", "tags[]" or "props.keys()"
+	Type string // One of IndexFieldTypes
+}
+
+// IndexDesign describes a new index for the index dialog.
+type IndexDesign struct {
+	Name      string
+	TableName string
+	Fields    []IndexField
+}
+
+// IsMultiKeyPath reports whether an index path indexes the elements of an array
+// or the keys or values of a map, making the index a multi-key index
+func IsMultiKeyPath(path string) bool {
+	lower := strings.ToLower(path)
+	return strings.Contains(lower, "[]") || strings.Contains(lower, "keys()") || strings.Contains(lower, "values()")
+}
+
+// Validate checks the design and returns the first problem found.
+func (d IndexDesign) Validate() error {
+	name := strings.TrimSpace(d.Name)
+	switch {
+	case name == "":
+		return fmt.Errorf("index name is required")
+	case strings.ContainsAny(name, " \t.,()"):
+		return fmt.Errorf("invalid index name")
+	case len(d.Fields) == 0:
+		return fmt.Errorf("at least one field is required")
+	}
+	for i, field := range d.Fields {
+		if strings.TrimSpace(field.Path) == "" {
+			return fmt.Errorf("field %d: path is required", i+1)
+		}
+	}
+	return nil
+}
+
+// DDL returns the CREATE INDEX statement of the design.
+func (d IndexDesign) DDL() (string, error) {
+	if err := d.Validate(); err != nil {
+		return "", err
+	}
+	fields := make([]string, len(d.Fields))
+	for i, field := range d.Fields {
+		fields[i] = strings.TrimSpace(field.Path)
+		if field.Type != "" {
+			fields[i] += " AS " + field.Type
+		}
+	}
+	return fmt.Sprintf("CREATE INDEX %s ON %s (%s)", strings.TrimSpace(d.Name), d.TableName, strings.Join(fields, ", ")), nil
+}
@@ -0,0 +1,62 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestIndexDesignDDL(t *testing.T) {
+	tests := []struct {
+		name    string
+		design  IndexDesign
+		want    string
+		wantErr string
+	}{
+		{
+			name:   "single column",
+			design: IndexDesign{Name: "idx_name", TableName: "users", Fields: []IndexField{{Path: "name"}}},
+			want:   "CREATE INDEX idx_name ON users (name)",
+		},
+		{
+			name: "typed JSON path and multi-key field",
+			design: IndexDesign{Name: "idx_doc", TableName: "users.logins", Fields: []IndexField{
+				{Path: " doc.address.city ", Type: "STRING"},
+				{Path: "tags[]"},
+			}},
+			want: "CREATE INDEX idx_doc ON users.logins (doc.address.city AS STRING, tags[])",
+		},
+		{name: "no name", design: IndexDesign{TableName: "t", Fields: []IndexField{{Path: "a"}}}, wantErr: "index name is required"},
+		{name: "invalid name", design: IndexDesign{Name: "a b", TableName: "t", Fields: []IndexField{{Path: "a"}}}, wantErr: "invalid index name"},
+		{name: "no fields", design: IndexDesign{Name: "i", TableName: "t"}, wantErr: "at least one field"},
+		{name: "empty path", design: IndexDesign{Name: "i", TableName: "t", Fields: []IndexField{{Path: "a"}, {Type: "STRING"}}}, wantErr: "field 2: path is required"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.design.DDL()
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("DDL() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("DDL() = %q, %v, want %q", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsMultiKeyPath(t *testing.T) {
+	for path, want := range map[string]bool{
+		"name":              false,
+		"doc.city":          false,
+		"tags[]":            true,
+		"props.keys()":      true,
+		"props.VALUES().id": true,
+	} {
+		if got := IsMultiKeyPath(path); got != want {
+			t.Errorf("IsMultiKeyPath(%q) = %v, want %v", path, got, want)
+		}
+	}
+}
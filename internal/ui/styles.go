@@ -76,6 +76,8 @@ var (
 	StyleTableSelected = lipgloss.NewStyle().Foreground(ColorWhite)   // Selected table (*)
 	StyleTableCursor   = lipgloss.NewStyle().Foreground(ColorPrimary) // Cursor position
 	StyleTableNormal   = lipgloss.NewStyle().Foreground(ColorGray)    // Normal table
+	StyleTableState    = lipgloss.NewStyle().Foreground(ColorIndex)   // State of a running DDL operation
+	StyleTableMatch    = lipgloss.NewStyle().Foreground(ColorIndex).Bold(true) // Characters matched by the filter
 )
 
 // Schema pane styles
@@ -92,6 +94,7 @@ var (
 	StyleHelpText   = lipgloss.NewStyle().Foreground(ColorGray)
 	StyleErrorLight = lipgloss.NewStyle().Foreground(ColorErrorLight)
 	StyleGrayText   = lipgloss.NewStyle().Foreground(ColorGray)
+	StyleReadOnly   = lipgloss.NewStyle().Foreground(ColorBlack).Background(ColorIndex).Bold(true) // Read-only connection badge
 )
 
 // Text input cursor styles (unified across the app)
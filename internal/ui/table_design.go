@@ -0,0 +1,162 @@
+package ui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// DesignTypes are the column types offered by the table designer
+var DesignTypes = []string{
+	"INTEGER", "LONG", "FLOAT", "DOUBLE", "NUMBER", "STRING", "BOOLEAN", "BINARY",
+	"TIMESTAMP", "JSON", "ARRAY", "MAP", "RECORD",
+}
+
+// DesignElementTypes are the element types offered for ARRAY and MAP columns
+var DesignElementTypes = []string{
+	"STRING", "INTEGER", "LONG", "FLOAT", "DOUBLE", "NUMBER", "BOOLEAN", "BINARY", "JSON",
+}
+
+// DesignTTLUnits are the units of a table's default TTL
+var DesignTTLUnits = []string{"DAYS", "HOURS"}
+
+// DefaultTimestampPrecision is the fractional second precision of new TIMESTAMP columns
+const DefaultTimestampPrecision = 3
+
+// DesignColumn is a column of a table being designed.
+type DesignColumn struct {
+	Name        string
+	Type        string // One of DesignTypes
+	Precision   int    // Fractional second digits of a TIMESTAMP (0-9)
+	ElementType string // Element type of an ARRAY or MAP
+	Fields      string // Field definitions of a RECORD, e.g. "street STRING, city STRING"
+	PrimaryKey  bool
+	ShardKey    bool // Part of the shard key; only for primary key columns of top-level tables
+}
+
+// TypeDDL returns the column type as written in DDL, e.g. "TIMESTAMP(3)" or "ARRAY(STRING)"
+func (c DesignColumn) TypeDDL() string {
+	switch c.Type {
+	case "TIMESTAMP":
+		return fmt.Sprintf("TIMESTAMP(%d)", c.Precision)
+	case "ARRAY", "MAP":
+		return c.Type + "(" + c.ElementType + ")"
+	case "RECORD":
+		return "RECORD(" + strings.TrimSpace(c.Fields) + ")"
+	}
+	return c.Type
+}
+
+// TableDesign describes a new table for the table designer.
+type TableDesign struct {
+	Name    string // Full table name; "parent.child" designs a child table
+	Columns []DesignColumn
+	TTL     string // Default time to live; empty or "0" for none
+	TTLUnit string // One of DesignTTLUnits
+}
+
+// IsChild reports whether the design is a child table
+func (d TableDesign) IsChild() bool {
+	return GetParentTableName(d.Name) != ""
+}
+
+// NewDesignColumn returns a column with the designer's defaults for typ
+func NewDesignColumn(name, typ string) DesignColumn {
+	return DesignColumn{
+		Name:        name,
+		Type:        typ,
+		Precision:   DefaultTimestampPrecision,
+		ElementType: DesignElementTypes[0],
+	}
+}
+
+// nonKeyTypes are the types a primary key column cannot have
+var nonKeyTypes = map[string]bool{
+	"BOOLEAN": true, "BINARY": true, "JSON": true, "ARRAY": true, "MAP": true, "RECORD": true,
+}
+
+// Validate checks the design and returns the first problem found.
+func (d TableDesign) Validate() error {
+	if strings.TrimSpace(d.Name) == "" {
+		return fmt.Errorf("table name is required")
+	}
+	if len(d.Columns) == 0 {
+		return fmt.Errorf("at least one column is required")
+	}
+
+	seen := make(map[string]bool)
+	keys, shardKeys := 0, 0
+	for i, col := range d.Columns {
+		name := strings.TrimSpace(col.Name)
+		switch {
+		case name == "":
+			return fmt.Errorf("column %d: name is required", i+1)
+		case strings.ContainsAny(name, " \t,()"):
+			return fmt.Errorf("column %s: invalid name", name)
+		case seen[strings.ToLower(name)]:
+			return fmt.Errorf("column %s: duplicate name", name)
+		case col.Type == "RECORD" && strings.TrimSpace(col.Fields) == "":
+			return fmt.Errorf("column %s: RECORD needs fields", name)
+		case col.Type == "TIMESTAMP" && (col.Precision < 0 || col.Precision > 9):
+			return fmt.Errorf("column %s: TIMESTAMP precision must be 0-9", name)
+		case col.PrimaryKey && nonKeyTypes[col.Type]:
+			return fmt.Errorf("column %s: %s cannot be a primary key", name, col.Type)
+		case col.ShardKey && !col.PrimaryKey:
+			return fmt.Errorf("column %s: shard key columns must be primary key columns", name)
+		case col.ShardKey && d.IsChild():
+			return fmt.Errorf("child tables inherit the shard key of their parent")
+		case col.ShardKey && shardKeys < keys:
+			return fmt.Errorf("shard key columns must come first in the primary key")
+		}
+		seen[strings.ToLower(name)] = true
+		if col.PrimaryKey {
+			keys++
+		}
+		if col.ShardKey {
+			shardKeys++
+		}
+	}
+	if keys == 0 {
+		return fmt.Errorf("at least one primary key column is required")
+	}
+
+	if ttl := strings.TrimSpace(d.TTL); ttl != "" {
+		if n, err := strconv.Atoi(ttl); err != nil || n < 0 {
+			return fmt.Errorf("TTL must be a whole number")
+		}
+	}
+	return nil
+}
+
+// DDL returns the CREATE TABLE statement of the design, one column per line.
+func (d TableDesign) DDL() (string, error) {
+	if err := d.Validate(); err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	b.WriteString("CREATE TABLE " + strings.TrimSpace(d.Name) + " (\n")
+	var keys, shardKeys []string
+	for _, col := range d.Columns {
+		name := strings.TrimSpace(col.Name)
+		b.WriteString("  " + name + " " + col.TypeDDL() + ",\n")
+		if col.ShardKey {
+			shardKeys = append(shardKeys, name)
+		} else if col.PrimaryKey {
+			keys = append(keys, name)
+		}
+	}
+	if len(shardKeys) > 0 {
+		keys = append([]string{"SHARD(" + strings.Join(shardKeys, ", ") + ")"}, keys...)
+	}
+	b.WriteString("  PRIMARY KEY(" + strings.Join(keys, ", ") + ")\n)")
+
+	if ttl, _ := strconv.Atoi(strings.TrimSpace(d.TTL)); ttl > 0 {
+		unit := d.TTLUnit
+		if unit == "" {
+			unit = DesignTTLUnits[0]
+		}
+		fmt.Fprintf(&b, " USING TTL %d %s", ttl, unit)
+	}
+	return b.String(), nil
+}
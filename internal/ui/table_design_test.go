@@ -0,0 +1,111 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTableDesignDDL(t *testing.T) {
+	col := func(name, typ string, pk, shard bool) DesignColumn {
+		c := NewDesignColumn(name, typ)
+		c.PrimaryKey, c.ShardKey = pk, shard
+		return c
+	}
+
+	t.Run("top-level table with shard key and TTL", func(t *testing.T) {
+		tags := col("tags", "ARRAY", false, false)
+		address := col("address", "RECORD", false, false)
+		address.Fields = "street STRING, city STRING"
+		created := col("created", "TIMESTAMP", false, false)
+		created.Precision = 6
+		design := TableDesign{
+			Name: "users",
+			Columns: []DesignColumn{
+				col("tenant", "STRING", true, true),
+				col("id", "LONG", true, false),
+				created,
+				tags,
+				address,
+				col("doc", "JSON", false, false),
+			},
+			TTL:     "30",
+			TTLUnit: "DAYS",
+		}
+
+		got, err := design.DDL()
+		if err != nil {
+			t.Fatalf("DDL() error = %v", err)
+		}
+		want := "CREATE TABLE users (\n" +
+			"  tenant STRING,\n" +
+			"  id LONG,\n" +
+			"  created TIMESTAMP(6),\n" +
+			"  tags ARRAY(STRING),\n" +
+			"  address RECORD(street STRING, city STRING),\n" +
+			"  doc JSON,\n" +
+			"  PRIMARY KEY(SHARD(tenant), id)\n" +
+			") USING TTL 30 DAYS"
+		if got != want {
+			t.Errorf("DDL() =\n%s\nwant\n%s", got, want)
+		}
+
+		// The generated DDL can be read back by the Schema pane parser
+		if keys := ParsePrimaryKeysFromDDL(got); strings.Join(keys, ",") != "tenant,id" {
+			t.Errorf("ParsePrimaryKeysFromDDL() = %v", keys)
+		}
+	})
+
+	t.Run("child table without TTL", func(t *testing.T) {
+		design := TableDesign{
+			Name:    "users.addresses",
+			Columns: []DesignColumn{col("aid", "INTEGER", true, false), col("city", "STRING", false, false)},
+			TTL:     "0",
+		}
+		got, err := design.DDL()
+		if err != nil {
+			t.Fatalf("DDL() error = %v", err)
+		}
+		want := "CREATE TABLE users.addresses (\n  aid INTEGER,\n  city STRING,\n  PRIMARY KEY(aid)\n)"
+		if got != want {
+			t.Errorf("DDL() =\n%s\nwant\n%s", got, want)
+		}
+	})
+}
+
+func TestTableDesignValidate(t *testing.T) {
+	key := NewDesignColumn("id", "INTEGER")
+	key.PrimaryKey = true
+
+	tests := []struct {
+		name    string
+		design  TableDesign
+		wantErr string
+	}{
+		{name: "no name", design: TableDesign{Columns: []DesignColumn{key}}, wantErr: "table name is required"},
+		{name: "no columns", design: TableDesign{Name: "t"}, wantErr: "at least one column"},
+		{name: "no primary key", design: TableDesign{Name: "t", Columns: []DesignColumn{NewDesignColumn("a", "STRING")}}, wantErr: "primary key column is required"},
+		{name: "empty column name", design: TableDesign{Name: "t", Columns: []DesignColumn{key, NewDesignColumn("", "STRING")}}, wantErr: "column 2: name is required"},
+		{name: "duplicate column", design: TableDesign{Name: "t", Columns: []DesignColumn{key, NewDesignColumn("ID", "STRING")}}, wantErr: "duplicate name"},
+		{name: "JSON key", design: TableDesign{Name: "t", Columns: []DesignColumn{{Name: "doc", Type: "JSON", PrimaryKey: true}}}, wantErr: "JSON cannot be a primary key"},
+		{name: "record without fields", design: TableDesign{Name: "t", Columns: []DesignColumn{key, NewDesignColumn("r", "RECORD")}}, wantErr: "RECORD needs fields"},
+		{name: "shard key after key", design: TableDesign{Name: "t", Columns: []DesignColumn{key, {Name: "b", Type: "STRING", PrimaryKey: true, ShardKey: true}}}, wantErr: "must come first"},
+		{name: "child shard key", design: TableDesign{Name: "p.c", Columns: []DesignColumn{{Name: "b", Type: "STRING", PrimaryKey: true, ShardKey: true}}}, wantErr: "inherit the shard key"},
+		{name: "bad TTL", design: TableDesign{Name: "t", Columns: []DesignColumn{key}, TTL: "-1"}, wantErr: "TTL must be a whole number"},
+		{name: "valid", design: TableDesign{Name: "t", Columns: []DesignColumn{key}, TTL: "7"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.design.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Validate() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Validate() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -5,11 +5,22 @@ import (
 	"strings"
 )
 
+// sqlTableNamePattern matches a table name with an optional namespace and parent table
+const sqlTableNamePattern = `((?:[a-zA-Z_][a-zA-Z0-9_]*:)?[a-zA-Z_][a-zA-Z0-9_]*(?:\.[a-zA-Z_][a-zA-Z0-9_]*)?)`
+
 // ExtractTableNameFromSQL extracts the table name from a SQL query.
-// Supports SELECT ... FROM table and SELECT ... FROM namespace.table
+// Supports SELECT ... FROM table and SELECT ... FROM parent.child, optionally
+// qualified with a namespace (ns1:table), as well as the target table of
+// INSERT/UPSERT INTO, UPDATE and DELETE FROM statements.
 func ExtractTableNameFromSQL(sql string) string {
-	// Case-insensitive regex to find FROM clause
-	re := regexp.MustCompile(`(?i)\bFROM\s+([a-zA-Z_][a-zA-Z0-9_]*(?:\.[a-zA-Z_][a-zA-Z0-9_]*)?)`)
+	// UPDATE names its table first; other statements use FROM or INTO
+	re := regexp.MustCompile(`(?i)^\s*UPDATE\s+` + sqlTableNamePattern)
+	if matches := re.FindStringSubmatch(sql); len(matches) >= 2 {
+		return strings.TrimSpace(matches[1])
+	}
+
+	// Case-insensitive regex to find FROM or INTO clause
+	re = regexp.MustCompile(`(?i)\b(?:FROM|INTO)\s+` + sqlTableNamePattern)
 	matches := re.FindStringSubmatch(sql)
 	if len(matches) >= 2 {
 		return strings.TrimSpace(matches[1])
@@ -17,6 +28,25 @@ func ExtractTableNameFromSQL(sql string) string {
 	return ""
 }
 
+// aliasStopWords are keywords that may follow a table name in place of an alias
+var aliasStopWords = map[string]bool{
+	"WHERE": true, "ORDER": true, "GROUP": true, "LIMIT": true, "OFFSET": true, "SET": true,
+	"NESTED": true, "LEFT": true, "JOIN": true, "VALUES": true, "RETURNING": true,
+}
+
+// ExtractTableAliasFromSQL returns the alias given to tableName in the FROM,
+// INTO or UPDATE clause of a SQL statement, e.g. "t" for "SELECT * FROM users t"
+// or "$u" for "UPDATE users AS $u ...". Returns "" when the table has no alias.
+func ExtractTableAliasFromSQL(sql, tableName string) string {
+	re := regexp.MustCompile(`(?i)(?:^\s*UPDATE|\bFROM|\bINTO)\s+` + regexp.QuoteMeta(tableName) +
+		`\s+(?:AS\s+)?(\$?[a-zA-Z_][a-zA-Z0-9_]*)`)
+	matches := re.FindStringSubmatch(sql)
+	if len(matches) < 2 || aliasStopWords[strings.ToUpper(matches[1])] {
+		return ""
+	}
+	return matches[1]
+}
+
 // InsertAt inserts a string at the specified rune position in text.
 // Returns the new text.
 func InsertAt(text string, pos int, insert string) string {
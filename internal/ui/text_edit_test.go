@@ -129,6 +129,12 @@ func TestExtractTableNameFromSQL(t *testing.T) {
 		{"table with underscore", "SELECT * FROM user_accounts", "user_accounts"},
 		{"table with numbers", "SELECT * FROM table123", "table123"},
 		{"child table", "SELECT * FROM orders.items", "orders.items"},
+		{"insert", "INSERT INTO users VALUES (1, 'a')", "users"},
+		{"upsert", "upsert into orders.items values (1, 2)", "orders.items"},
+		{"update", "UPDATE users SET name = 'b' WHERE id = 1", "users"},
+		{"delete", "DELETE FROM users WHERE id = 1", "users"},
+		{"namespace qualified", "SELECT * FROM ns1:users ORDER BY id", "ns1:users"},
+		{"namespace qualified child", "UPDATE ns1:users.phones SET n = 1", "ns1:users.phones"},
 	}
 
 	for _, tt := range tests {
@@ -141,6 +147,34 @@ func TestExtractTableNameFromSQL(t *testing.T) {
 	}
 }
 
+func TestExtractTableAliasFromSQL(t *testing.T) {
+	tests := []struct {
+		name     string
+		sql      string
+		table    string
+		expected string
+	}{
+		{"alias", "SELECT * FROM users t", "users", "t"},
+		{"AS alias", "SELECT u.name FROM users AS u WHERE u.id = 1", "users", "u"},
+		{"dollar alias", "SELECT $u.name FROM users $u", "users", "$u"},
+		{"child table", "SELECT * FROM users.addresses a ORDER BY a.id", "users.addresses", "a"},
+		{"update", "UPDATE users t SET t.name = 'b' WHERE id = 1", "users", "t"},
+		{"no alias", "SELECT * FROM users", "users", ""},
+		{"keyword after table", "SELECT * FROM users ORDER BY id", "users", ""},
+		{"where after table", "select * from users where id = 1", "users", ""},
+		{"other table", "SELECT * FROM orders o", "users", ""},
+		{"empty", "", "users", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ExtractTableAliasFromSQL(tt.sql, tt.table); got != tt.expected {
+				t.Errorf("ExtractTableAliasFromSQL(%q, %q) = %q, want %q", tt.sql, tt.table, got, tt.expected)
+			}
+		})
+	}
+}
+
 func TestRuneLen(t *testing.T) {
 	tests := []struct {
 		name     string